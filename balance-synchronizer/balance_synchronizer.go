@@ -11,11 +11,14 @@ import (
 	"github.com/pkg/errors"
 	"github.com/redis/go-redis/v9"
 
+	dwh "github.com/ice-blockchain/freezer/bookkeeper/storage"
 	"github.com/ice-blockchain/freezer/model"
+	"github.com/ice-blockchain/freezer/tokenomics"
 	appCfg "github.com/ice-blockchain/wintr/config"
 	messagebroker "github.com/ice-blockchain/wintr/connectors/message_broker"
 	"github.com/ice-blockchain/wintr/connectors/storage/v3"
 	"github.com/ice-blockchain/wintr/log"
+	"github.com/ice-blockchain/wintr/time"
 )
 
 func init() {
@@ -25,7 +28,8 @@ func init() {
 
 func MustStartSynchronizingBalance(ctx context.Context) {
 	bs := &balanceSynchronizer{
-		mb: messagebroker.MustConnect(context.Background(), parentApplicationYamlKey),
+		mb:  messagebroker.MustConnect(context.Background(), parentApplicationYamlKey),
+		dwh: dwh.MustConnect(context.Background(), parentApplicationYamlKey),
 	}
 	defer func() { log.Panic(errors.Wrap(bs.Close(), "failed to stop balanceSynchronizer")) }()
 
@@ -44,6 +48,7 @@ func MustStartSynchronizingBalance(ctx context.Context) {
 func (bs *balanceSynchronizer) Close() error {
 	return multierror.Append(
 		errors.Wrap(bs.mb.Close(), "failed to close mb"),
+		errors.Wrap(bs.dwh.Close(), "failed to close dwh"),
 	).ErrorOrNil()
 }
 
@@ -67,23 +72,37 @@ func (bs *balanceSynchronizer) synchronize(ctx context.Context, workerNumber int
 		msgs               = make([]*messagebroker.Message, 0, batchSize)
 		errs               = make([]error, 0, batchSize)
 		updatedUsers       = make([]redis.Z, 0, batchSize)
+		leaderboardRanks   = make(map[string][]redis.Z)
+		leaderboardUnranks = make(map[string][]any)
 		blockchainMessages = make([]*blockchainMessage, 0, batchSize)
+		// The global ranks are snapshotted once per `GlobalAggregationInterval.Child`, by the first iteration that starts in it.
+		ranksSnapshotAt, ranksSnapshottedAt *time.Time
 	)
 	resetVars := func(success bool) {
 		if success && len(userResults) < int(batchSize) {
 			batchNumber = 0
 			iteration++
+			ranksSnapshottedAt = ranksSnapshotAt
 		}
 		userKeys = userKeys[:0]
 		userResults = userResults[:0]
 		msgs, errs = msgs[:0], errs[:0]
 		updatedUsers = updatedUsers[:0]
+		for k := range leaderboardRanks {
+			delete(leaderboardRanks, k)
+		}
+		for k := range leaderboardUnranks {
+			delete(leaderboardUnranks, k)
+		}
 		blockchainMessages = blockchainMessages[:0]
 	}
 	for ctx.Err() == nil {
 		/******************************************************************************************************************************************************
 			1. Fetching a new batch of users.
 		******************************************************************************************************************************************************/
+		if batchNumber == 0 {
+			ranksSnapshotAt = time.New(time.Now().Truncate(cfg.GlobalAggregationInterval.Child))
+		}
 		if len(userKeys) == 0 {
 			for ix := batchNumber * batchSize; ix < (batchNumber+1)*batchSize; ix++ {
 				userKeys = append(userKeys, model.SerializedUsersKey((workers*ix)+workerNumber))
@@ -102,8 +121,12 @@ func (bs *balanceSynchronizer) synchronize(ctx context.Context, workerNumber int
 			2. Processing batch.
 		******************************************************************************************************************************************************/
 
+		now := time.Now()
 		for _, usr := range userResults {
-			updatedUsers = append(updatedUsers, GlobalRank(usr.ID, usr.BalanceTotalStandard+usr.BalanceTotalPreStaking))
+			totalBalance := usr.BalanceTotalStandard + usr.BalanceTotalPreStaking
+			updatedUsers = append(updatedUsers, GlobalRank(usr.ID, totalBalance))
+			miningStreak := model.CalculateMiningStreak(now, usr.MiningSessionSoloStartedAt, usr.MiningSessionSoloEndedAt, cfg.MiningSessionDuration.Max)
+			AppendLeaderboardRanks(leaderboardRanks, leaderboardUnranks, usr.ID, usr.Country, totalBalance, usr.ActiveT1Referrals, miningStreak)
 			msgs = append(msgs, BalanceUpdatedMessage(ctx, usr.UserID, usr.BalanceTotalStandard, usr.BalanceTotalPreStaking))
 			if msg := shouldSynchronizeBlockchainAccount(iteration, usr); msg != nil {
 				blockchainMessages = append(blockchainMessages, msg)
@@ -131,13 +154,19 @@ func (bs *balanceSynchronizer) synchronize(ctx context.Context, workerNumber int
 		reqCancel()
 
 		/******************************************************************************************************************************************************
-			4. Updating user scores in `top_miners` sorted set.
+			4. Updating user scores in `top_miners` and the other leaderboards' sorted sets.
 		******************************************************************************************************************************************************/
 
 		if len(updatedUsers) > 0 {
 			reqCtx, reqCancel = context.WithTimeout(context.Background(), requestDeadline)
-			if err := db.ZAdd(reqCtx, "top_miners", updatedUsers...).Err(); err != nil {
-				log.Error(errors.Wrapf(err, "[balanceSynchronizer] failed to ZAdd top_miners for batchNumer:%v,workerNumber:%v", batchNumber, workerNumber))
+			if _, err := db.Pipelined(reqCtx, func(pipeliner redis.Pipeliner) error {
+				if err := pipeliner.ZAdd(reqCtx, "top_miners", updatedUsers...).Err(); err != nil {
+					return err
+				}
+
+				return UpdateLeaderboards(reqCtx, pipeliner, leaderboardRanks, leaderboardUnranks)
+			}); err != nil {
+				log.Error(errors.Wrapf(err, "[balanceSynchronizer] failed to update leaderboards for batchNumer:%v,workerNumber:%v", batchNumber, workerNumber))
 				reqCancel()
 				resetVars(false)
 
@@ -147,7 +176,25 @@ func (bs *balanceSynchronizer) synchronize(ctx context.Context, workerNumber int
 		}
 
 		/******************************************************************************************************************************************************
-			5. Updating balances in the blockchain for that batch of users.
+			5. Snapshotting the global ranks and announcing the users that entered or left the top miners.
+		******************************************************************************************************************************************************/
+
+		reqCtx, reqCancel = context.WithTimeout(context.Background(), requestDeadline)
+		snapshotAt := ranksSnapshotAt
+		if ranksSnapshottedAt != nil && ranksSnapshottedAt.Equal(*ranksSnapshotAt.Time) {
+			snapshotAt = nil
+		}
+		if err := bs.synchronizeRanks(reqCtx, db, snapshotAt, userResults); err != nil {
+			log.Error(errors.Wrapf(err, "[balanceSynchronizer] failed to synchronizeRanks for batchNumer:%v,workerNumber:%v", batchNumber, workerNumber))
+			reqCancel()
+			resetVars(false)
+
+			continue
+		}
+		reqCancel()
+
+		/******************************************************************************************************************************************************
+			6. Updating balances in the blockchain for that batch of users.
 		******************************************************************************************************************************************************/
 
 		reqCtx, reqCancel = context.WithTimeout(context.Background(), requestDeadline)
@@ -173,6 +220,43 @@ func GlobalRank(id int64, totalBalance float64) redis.Z {
 	}
 }
 
+// AppendLeaderboardRanks collects the scores of the user in the leaderboards other than `top_miners`, into `ranks`,
+// or, for the ones where the user doesn't qualify anymore, into `unranked`.
+func AppendLeaderboardRanks(
+	ranks map[string][]redis.Z, unranked map[string][]any, id int64, country string, totalBalance float64, activeT1Referrals int32, miningStreak uint64,
+) {
+	member := model.SerializedUsersKey(id)
+	if country != "" {
+		key := tokenomics.TopMinersKey(tokenomics.CountryTopMinersDimension, country)
+		ranks[key] = append(ranks[key], redis.Z{Score: totalBalance, Member: member})
+	}
+	for key, score := range map[string]float64{
+		tokenomics.TopMinersKey(tokenomics.ReferralsTopMinersDimension, ""):    float64(activeT1Referrals),
+		tokenomics.TopMinersKey(tokenomics.MiningStreakTopMinersDimension, ""): float64(miningStreak),
+	} {
+		if score > 0 {
+			ranks[key] = append(ranks[key], redis.Z{Score: score, Member: member})
+		} else {
+			unranked[key] = append(unranked[key], member)
+		}
+	}
+}
+
+func UpdateLeaderboards(ctx context.Context, db redis.Cmdable, ranks map[string][]redis.Z, unranked map[string][]any) error {
+	for key, values := range ranks {
+		if err := db.ZAdd(ctx, key, values...).Err(); err != nil {
+			return errors.Wrapf(err, "failed to ZAdd %v", key)
+		}
+	}
+	for key, members := range unranked {
+		if err := db.ZRem(ctx, key, members...).Err(); err != nil {
+			return errors.Wrapf(err, "failed to ZRem %v", key)
+		}
+	}
+
+	return nil
+}
+
 func BalanceUpdatedMessage(
 	ctx context.Context, userID string, totalStandardBalance, totalPreStakingBalance float64,
 ) *messagebroker.Message {
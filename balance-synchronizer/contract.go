@@ -5,6 +5,7 @@ package balancesynchronizer
 import (
 	stdlibtime "time"
 
+	dwh "github.com/ice-blockchain/freezer/bookkeeper/storage"
 	"github.com/ice-blockchain/freezer/model"
 	"github.com/ice-blockchain/freezer/tokenomics"
 	messagebroker "github.com/ice-blockchain/wintr/connectors/message_broker"
@@ -18,6 +19,13 @@ type (
 		Standard   float64 `json:"standard,omitempty"`
 		PreStaking float64 `json:"preStaking,omitempty"`
 	}
+	// TopMinersChange is sent when a user enters or leaves the first `TopMiners` of the global rank.
+	TopMinersChange struct {
+		UserID     string `json:"userId,omitempty"`
+		GlobalRank uint64 `json:"globalRank,omitempty"`
+		TopMiners  uint64 `json:"topMiners,omitempty"`
+		Entered    bool   `json:"entered"`
+	}
 )
 
 // Private API.
@@ -26,6 +34,7 @@ const (
 	applicationYamlKey       = "balance-synchronizer"
 	parentApplicationYamlKey = "tokenomics"
 	requestDeadline          = 30 * stdlibtime.Second
+	topMinersKey             = "top_miners_announced"
 )
 
 // .
@@ -35,19 +44,26 @@ var (
 		tokenomics.Config `mapstructure:",squash"` //nolint:tagliatelle // Nope.
 		Workers           int64                    `yaml:"workers"`
 		BatchSize         int64                    `yaml:"batchSize"`
+		TopMiners         uint64                   `yaml:"topMiners"`
 	}
 )
 
 type (
 	user struct {
+		model.MiningSessionSoloStartedAtField
+		model.MiningSessionSoloEndedAtField
 		model.UserIDField
+		model.CountryField
 		model.MiningBlockchainAccountAddressField
 		model.DeserializedUsersKey
 		model.BalanceTotalStandardField
 		model.BalanceTotalPreStakingField
+		model.ActiveT1ReferralsField
+		model.HideRankingField
 	}
 
 	balanceSynchronizer struct {
-		mb messagebroker.Client
+		mb  messagebroker.Client
+		dwh dwh.Client
 	}
 )
@@ -0,0 +1,118 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package balancesynchronizer
+
+import (
+	"context"
+
+	"github.com/goccy/go-json"
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+	"github.com/redis/go-redis/v9"
+
+	dwh "github.com/ice-blockchain/freezer/bookkeeper/storage"
+	"github.com/ice-blockchain/freezer/model"
+	messagebroker "github.com/ice-blockchain/wintr/connectors/message_broker"
+	"github.com/ice-blockchain/wintr/connectors/storage/v3"
+	"github.com/ice-blockchain/wintr/log"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+// synchronizeRanks snapshots the global ranks of the users, if snapshotAt is set,
+// and announces the ones that entered or left the top miners since their previous batch.
+//
+//nolint:funlen,gocognit // .
+func (bs *balanceSynchronizer) synchronizeRanks(ctx context.Context, db storage.DB, snapshotAt *time.Time, usrs []*user) error {
+	if len(usrs) == 0 {
+		return nil
+	}
+	rankCmds, topMinerCmds := make([]*redis.IntCmd, 0, len(usrs)), make([]*redis.BoolCmd, 0, len(usrs))
+	if _, err := db.Pipelined(ctx, func(pipeliner redis.Pipeliner) error {
+		for _, usr := range usrs {
+			rankCmds = append(rankCmds, pipeliner.ZRevRank(ctx, "top_miners", model.SerializedUsersKey(usr.ID)))
+			topMinerCmds = append(topMinerCmds, pipeliner.SIsMember(ctx, topMinersKey, model.SerializedUsersKey(usr.ID)))
+		}
+
+		return nil
+	}); err != nil && !errors.Is(err, redis.Nil) {
+		return errors.Wrap(err, "failed to get the global ranks")
+	}
+	var (
+		ranks        = make([]*dwh.RankHistory, 0, len(usrs))
+		entered      = make([]any, 0)
+		left         = make([]any, 0)
+		msgs         = make([]*messagebroker.Message, 0)
+		msgResponder = make(chan error, len(usrs))
+		errs         = make([]error, 0, len(usrs))
+	)
+	for ix, usr := range usrs {
+		rank, err := rankCmds[ix].Uint64()
+		if err != nil {
+			if errors.Is(err, redis.Nil) {
+				continue
+			}
+
+			return errors.Wrapf(err, "failed to ZRevRank top_miners for id:%v", usr.ID)
+		}
+		rank++
+		if snapshotAt != nil {
+			ranks = append(ranks, &dwh.RankHistory{CreatedAt: snapshotAt, ID: usr.ID, GlobalRank: rank})
+		}
+		wasTopMiner, err := topMinerCmds[ix].Result()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			return errors.Wrapf(err, "failed to SIsMember %v for id:%v", topMinersKey, usr.ID)
+		}
+		if isTopMiner := rank <= cfg.TopMiners && !usr.HideRanking; isTopMiner != wasTopMiner {
+			if isTopMiner {
+				entered = append(entered, model.SerializedUsersKey(usr.ID))
+			} else {
+				left = append(left, model.SerializedUsersKey(usr.ID))
+			}
+			msgs = append(msgs, TopMinersChangeMessage(ctx, &TopMinersChange{
+				UserID:     usr.UserID,
+				GlobalRank: rank,
+				TopMiners:  cfg.TopMiners,
+				Entered:    isTopMiner,
+			}))
+		}
+	}
+	for _, message := range msgs {
+		bs.mb.SendMessage(ctx, message, msgResponder)
+	}
+	for (len(msgs) > 0 && len(errs) < len(msgs)) || len(msgResponder) > 0 {
+		errs = append(errs, <-msgResponder)
+	}
+	if err := multierror.Append(ctx.Err(), errs...).ErrorOrNil(); err != nil {
+		return errors.Wrap(err, "failed to send top miners change messages")
+	}
+	if len(entered)+len(left) > 0 {
+		if _, err := db.TxPipelined(ctx, func(pipeliner redis.Pipeliner) error {
+			if len(entered) > 0 {
+				if err := pipeliner.SAdd(ctx, topMinersKey, entered...).Err(); err != nil {
+					return err
+				}
+			}
+			if len(left) > 0 {
+				return pipeliner.SRem(ctx, topMinersKey, left...).Err()
+			}
+
+			return nil
+		}); err != nil {
+			return errors.Wrapf(err, "failed to update %v", topMinersKey)
+		}
+	}
+
+	return errors.Wrap(bs.dwh.InsertRankHistory(ctx, ranks), "failed to InsertRankHistory")
+}
+
+func TopMinersChangeMessage(ctx context.Context, event *TopMinersChange) *messagebroker.Message {
+	valueBytes, err := json.MarshalContext(ctx, event)
+	log.Panic(errors.Wrapf(err, "failed to marshal %#v", event))
+
+	return &messagebroker.Message{
+		Headers: map[string]string{"producer": "freezer"},
+		Key:     event.UserID,
+		Topic:   cfg.MessageBroker.Topics[6].Name,
+		Value:   valueBytes,
+	}
+}
@@ -25,6 +25,13 @@ type (
 		Insert(ctx context.Context, columns *Columns, input InsertMetadata, usrs []*model.User) error
 		SelectBalanceHistory(ctx context.Context, id int64, createdAts []stdlibtime.Time) ([]*BalanceHistory, error)
 		SelectTotalCoins(ctx context.Context, createdAts []stdlibtime.Time) ([]*TotalCoins, error)
+		InsertRankHistory(ctx context.Context, ranks []*RankHistory) error
+		SelectRankHistory(ctx context.Context, id int64, createdAts []stdlibtime.Time) ([]*RankHistory, error)
+	}
+	RankHistory struct {
+		CreatedAt  *time.Time
+		ID         int64
+		GlobalRank uint64
 	}
 	BalanceHistory struct {
 		CreatedAt                               *time.Time
@@ -117,7 +124,8 @@ type (
 // Private API.
 
 const (
-	tableName = "freezer_user_history"
+	tableName            = "freezer_user_history"
+	rankHistoryTableName = "freezer_user_rank_history"
 )
 
 // .
@@ -385,4 +385,28 @@ ALTER TABLE freezer_user_history
     ADD COLUMN IF NOT EXISTS kyc_steps_last_updated_at Array(DateTime64(9,'UTC')) DEFAULT [] AFTER kyc_steps_created_at;
 
 ALTER TABLE freezer_user_history
-    ADD COLUMN IF NOT EXISTS country String  DEFAULT '' AFTER kyc_steps_last_updated_at;
\ No newline at end of file
+    ADD COLUMN IF NOT EXISTS country String  DEFAULT '' AFTER kyc_steps_last_updated_at;
+CREATE TABLE IF NOT EXISTS light.freezer_user_rank_history
+(
+      created_at DateTime('UTC'),
+      id Int64,
+      global_rank UInt64  DEFAULT 0
+) ENGINE=ReplicatedMergeTree('/clickhouse/tables/{cluster}/{shard_light}/freezer_user_rank_history', '{replica_light}')
+  PARTITION BY toDate(created_at)
+  PRIMARY KEY (id, created_at);
+
+CREATE TABLE IF NOT EXISTS dark.freezer_user_rank_history
+(
+      created_at DateTime('UTC'),
+      id Int64,
+      global_rank UInt64  DEFAULT 0
+) ENGINE=ReplicatedMergeTree('/clickhouse/tables/{cluster}/{shard_dark}/freezer_user_rank_history', '{replica_dark}')
+  PARTITION BY toDate(created_at)
+  PRIMARY KEY (id, created_at);
+
+CREATE TABLE IF NOT EXISTS freezer_user_rank_history
+(
+     created_at DateTime('UTC'),
+     id Int64,
+     global_rank UInt64  DEFAULT 0
+) ENGINE = Distributed('{cluster}', '', 'freezer_user_rank_history', toUInt64(toDate(created_at)));
@@ -635,3 +635,74 @@ func (db *db) SelectTotalCoins(ctx context.Context, createdAts []stdlibtime.Time
 func (t *TotalCoins) Key() string {
 	return fmt.Sprintf("totalCoinStats:%v", t.CreatedAt.Format(stdlibtime.RFC3339))
 }
+
+func (db *db) InsertRankHistory(ctx context.Context, ranks []*RankHistory) error {
+	if len(ranks) == 0 {
+		return nil
+	}
+	var (
+		createdAt  = &proto.ColDateTime{Data: make([]proto.DateTime, 0, len(ranks)), Location: stdlibtime.UTC}
+		id         = make(proto.ColInt64, 0, len(ranks))
+		globalRank = make(proto.ColUInt64, 0, len(ranks))
+	)
+	for _, rank := range ranks {
+		createdAt.Append(*rank.CreatedAt.Time)
+		id.Append(rank.ID)
+		globalRank.Append(rank.GlobalRank)
+	}
+	input := proto.Input{
+		{Name: "created_at", Data: createdAt},
+		{Name: "id", Data: &id},
+		{Name: "global_rank", Data: &globalRank},
+	}
+
+	return db.pools[atomic.AddUint64(&db.currentIndex, 1)%uint64(len(db.pools))].Do(ctx, ch.Query{
+		Body:     input.Into(rankHistoryTableName),
+		Input:    input,
+		Settings: db.settings,
+	})
+}
+
+func (db *db) SelectRankHistory(ctx context.Context, id int64, createdAts []stdlibtime.Time) ([]*RankHistory, error) {
+	var (
+		createdAt  = proto.ColDateTime{Data: make([]proto.DateTime, 0, len(createdAts)), Location: stdlibtime.UTC}
+		globalRank = make(proto.ColUInt64, 0, len(createdAts))
+		res        = make([]*RankHistory, 0, len(createdAts))
+	)
+	createdAtArray := make([]string, 0, len(createdAts))
+	for _, date := range createdAts {
+		format := date.UTC().Format(stdlibtime.RFC3339)
+		createdAtArray = append(createdAtArray, format[0:len(format)-1])
+	}
+	if err := db.pools[atomic.AddUint64(&db.currentIndex, 1)%uint64(len(db.pools))].Do(ctx, ch.Query{
+		Body: fmt.Sprintf(`SELECT DISTINCT ON (created_at)
+								  created_at,
+								  global_rank
+						   FROM %[1]v
+						   WHERE id = %[2]v
+						     AND created_at IN ['%[3]v']
+						   ORDER BY created_at`, rankHistoryTableName, id, strings.Join(createdAtArray, "','")),
+		Result: append(make(proto.Results, 0, 2),
+			proto.ResultColumn{Name: "created_at", Data: &createdAt},
+			proto.ResultColumn{Name: "global_rank", Data: &globalRank}),
+		OnResult: func(_ context.Context, block proto.Block) error {
+			for ix := 0; ix < block.Rows; ix++ {
+				res = append(res, &RankHistory{
+					CreatedAt:  time.New((&createdAt).Row(ix)),
+					ID:         id,
+					GlobalRank: (&globalRank).Row(ix),
+				})
+			}
+			(&createdAt).Reset()
+			(&globalRank).Reset()
+
+			return nil
+		},
+		Secret:      "",
+		InitialUser: "",
+	}); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
@@ -114,4 +114,16 @@ func TestStorage(t *testing.T) {
 	require.NoError(t, err)
 	sort.SliceStable(h2, func(ii, jj int) bool { return h2[ii].CreatedAt.Before(*h2[jj].CreatedAt.Time) })
 	assert.EqualValues(t, []*BalanceHistory{}, h2)
+
+	require.NoError(t, cl.InsertRankHistory(context.Background(), []*RankHistory{
+		{CreatedAt: time.New(t1), ID: id1, GlobalRank: 2},
+		{CreatedAt: time.New(t2), ID: id1, GlobalRank: 1},
+		{CreatedAt: time.New(t2), ID: id2, GlobalRank: 2},
+	}))
+	r1, err := cl.SelectRankHistory(context.Background(), id1, []stdlibtime.Time{t1, t2})
+	require.NoError(t, err)
+	assert.EqualValues(t, []*RankHistory{
+		{CreatedAt: time.New(t1), ID: id1, GlobalRank: 2},
+		{CreatedAt: time.New(t2), ID: id1, GlobalRank: 1},
+	}, r1)
 }
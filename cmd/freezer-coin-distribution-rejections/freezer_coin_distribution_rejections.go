@@ -0,0 +1,64 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/goccy/go-json"
+	"github.com/pkg/errors"
+
+	coindistribution "github.com/ice-blockchain/freezer/coin-distribution"
+	"github.com/ice-blockchain/wintr/log"
+)
+
+const defaultLimit = 100
+
+func main() {
+	var (
+		target   = flag.String("target", "", "target of the rejected coin distributions, i.e. `ethereum`; required with -decision, optional filter otherwise")
+		decision = flag.String("decision", "", "`requeue` (after checking the transaction failed on-chain) or `fail` (move to the permanent failures ledger); lists the rejected ones if empty") //nolint:lll // .
+		batchID  = flag.String("batch-id", "", "batch of the rejected coin distributions to resolve, as listed")
+		txHash   = flag.String("tx-hash", "", "transaction of the rejected coin distributions to resolve, as listed")
+		operator = flag.String("operator", "", "who resolves them, recorded in the audit trail; required with -decision")
+		cursor   = flag.Uint64("cursor", 0, "cursor to list from")
+		limit    = flag.Uint64("limit", defaultLimit, "how many groups of rejected coin distributions to list")
+	)
+	flag.Parse()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	repo := coindistribution.NewRepository(ctx, cancel)
+	defer func() {
+		log.Error(errors.Wrap(repo.Close(), "failed to close coin distribution repository"))
+	}()
+
+	if *decision == "" {
+		rejected, err := repo.GetRejectedCoinDistributions(ctx, &coindistribution.GetRejectedCoinDistributionsArg{Target: *target, Cursor: *cursor, Limit: *limit})
+		log.Panic(errors.Wrap(err, "failed to list rejected coin distributions")) //nolint:revive // That's intended.
+		log.Panic(errors.Wrap(writeJSON(rejected), "failed to write rejected coin distributions"))
+
+		return
+	}
+
+	if *operator == "" || *target == "" {
+		log.Panic("-operator and -target are required with -decision")
+	}
+	arg := &coindistribution.ResolveRejectedCoinDistributionsArg{Decision: *decision, Target: *target, BatchID: *batchID, TxHash: *txHash}
+	log.Panic(errors.Wrapf(repo.ResolveRejectedCoinDistributions(ctx, *operator, arg), "failed to resolve rejected coin distributions %#v", arg))
+	log.Info(fmt.Sprintf("rejected coin distributions of target %v, batch %q, TX %q: %v", *target, *batchID, *txHash, *decision))
+}
+
+func writeJSON(value any) error {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal %#v", value)
+	}
+	_, err = os.Stdout.Write(data)
+
+	return errors.Wrap(err, "failed to write to stdout")
+}
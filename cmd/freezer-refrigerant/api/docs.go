@@ -20,9 +20,9 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/getCoinDistributionsForReview": {
-            "post": {
-                "description": "Fetches data of pending coin distributions for review.",
+        "/coin-distributions/{userId}/merkle-proofs": {
+            "get": {
+                "description": "Fetches the Merkle proofs the user needs to claim the coins of the cycles published in the Merkle-claim mode, newest cycle first.",
                 "consumes": [
                     "application/json"
                 ],
@@ -43,74 +43,15 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "the type of the client calling this API. I.E. ` + "`" + `web` + "`" + `",
-                        "name": "x_client_type",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 0,
-                        "description": "current cursor to fetch data from",
-                        "name": "cursor",
-                        "in": "query",
+                        "description": "ID of the user",
+                        "name": "userId",
+                        "in": "path",
                         "required": true
                     },
-                    {
-                        "type": "integer",
-                        "description": "count of records in response, 5000 by default",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "enum": [
-                            "asc",
-                            "desc"
-                        ],
-                        "type": "string",
-                        "description": "if u want to order by createdAt",
-                        "name": "createdAtOrderBy",
-                        "in": "query"
-                    },
-                    {
-                        "enum": [
-                            "asc",
-                            "desc"
-                        ],
-                        "type": "string",
-                        "description": "if u want to order by ice amount",
-                        "name": "iceOrderBy",
-                        "in": "query"
-                    },
-                    {
-                        "enum": [
-                            "asc",
-                            "desc"
-                        ],
-                        "type": "string",
-                        "description": "if u want to order by username lexicographically",
-                        "name": "usernameOrderBy",
-                        "in": "query"
-                    },
-                    {
-                        "enum": [
-                            "asc",
-                            "desc"
-                        ],
-                        "type": "string",
-                        "description": "if u want to order by referredByUsername lexicographically",
-                        "name": "referredByUsernameOrderBy",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "if u want to find usernames starting with keyword",
-                        "name": "usernameKeyword",
-                        "in": "query"
-                    },
                     {
                         "type": "string",
-                        "description": "if u want to find referredByUsernames starting with keyword",
-                        "name": "referredByUsernameKeyword",
+                        "description": "the type of the client calling this API. I.E. ` + "`" + `web` + "`" + `",
+                        "name": "x_client_type",
                         "in": "query"
                     }
                 ],
@@ -118,7 +59,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/coindistribution.CoinDistributionsForReview"
+                            "$ref": "#/definitions/coindistribution.CoinDistributionMerkleProofs"
                         }
                     },
                     "401": {
@@ -154,14 +95,15 @@ const docTemplate = `{
                 }
             }
         },
-        "/reviewDistributions": {
+        "/exportCoinDistributions": {
             "post": {
-                "description": "Reviews Coin Distributions.",
+                "description": "Streams all the coin distributions of a source matching the filter, as CSV or Parquet. If it fails after the first byte, the ` + "`" + `X-Export-Error` + "`" + ` trailer holds the reason and the file is incomplete.",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "text/csv",
+                    "application/vnd.apache.parquet"
                 ],
                 "tags": [
                     "CoinDistribution"
@@ -183,20 +125,67 @@ const docTemplate = `{
                     },
                     {
                         "enum": [
-                            "approve",
-                            "approve-and-process-immediately",
-                            "deny"
+                            "pendingReview",
+                            "reviewed",
+                            "settled"
                         ],
                         "type": "string",
-                        "description": "the decision for the current coin distributions",
-                        "name": "decision",
+                        "description": "the coin distributions to export",
+                        "name": "source",
                         "in": "query",
                         "required": true
+                    },
+                    {
+                        "enum": [
+                            "csv",
+                            "parquet"
+                        ],
+                        "type": "string",
+                        "description": "the format of the export, csv by default",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "if u want to find usernames starting with keyword",
+                        "name": "usernameKeyword",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "if u want to find referredByUsernames starting with keyword",
+                        "name": "referredByUsernameKeyword",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "if u want to find specific users",
+                        "name": "userIds",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "if u want to find distributions with at least this amount of ice",
+                        "name": "minIce",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "if u want to find distributions with at most this amount of ice",
+                        "name": "maxIce",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK"
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
                     },
                     "401": {
                         "description": "if not authorized",
@@ -231,9 +220,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/tokenomics/{userId}/extra-bonus-claims": {
+        "/getCoinDistributionHolds": {
             "post": {
-                "description": "Claims an extra bonus for the user.",
+                "description": "Fetches the users whose coin distributions are on hold, the latest first.",
                 "consumes": [
                     "application/json"
                 ],
@@ -241,7 +230,7 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Tokenomics"
+                    "CoinDistribution"
                 ],
                 "parameters": [
                     {
@@ -254,23 +243,36 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "ID of the user",
-                        "name": "userId",
-                        "in": "path",
+                        "description": "the type of the client calling this API. I.E. ` + "`" + `web` + "`" + `",
+                        "name": "x_client_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "if u want the released holds as well",
+                        "name": "includeReleased",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "current cursor to fetch data from",
+                        "name": "cursor",
+                        "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "count of records in response, 5000 by default",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
-                        "schema": {
-                            "$ref": "#/definitions/tokenomics.ExtraBonusSummary"
-                        }
-                    },
-                    "400": {
-                        "description": "if validations fail",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/server.ErrorResponse"
+                            "$ref": "#/definitions/coindistribution.CoinDistributionHolds"
                         }
                     },
                     "401": {
@@ -285,18 +287,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "if user not found or no extra bonus available",
-                        "schema": {
-                            "$ref": "#/definitions/server.ErrorResponse"
-                        }
-                    },
-                    "409": {
-                        "description": "if already claimed",
-                        "schema": {
-                            "$ref": "#/definitions/server.ErrorResponse"
-                        }
-                    },
                     "422": {
                         "description": "if syntax fails",
                         "schema": {
@@ -318,9 +308,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/tokenomics/{userId}/mining-sessions": {
+        "/getCoinDistributionPayouts": {
             "post": {
-                "description": "Starts a new mining session for the user, if not already in progress with another one.",
+                "description": "Fetches the settled (mined on-chain) coin distributions of an user or of an eth address, newest first.",
                 "consumes": [
                     "application/json"
                 ],
@@ -328,7 +318,7 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Tokenomics"
+                    "CoinDistribution"
                 ],
                 "parameters": [
                     {
@@ -341,38 +331,42 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "ID of the user",
+                        "description": "the type of the client calling this API. I.E. ` + "`" + `web` + "`" + `",
+                        "name": "x_client_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "the id of the user. Required if ` + "`" + `ethAddress` + "`" + ` is not provided",
                         "name": "userId",
-                        "in": "path",
-                        "required": true
+                        "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "the type of the client calling this API. I.E. ` + "`" + `web` + "`" + `",
-                        "name": "x_client_type",
+                        "description": "the eth address the coins were sent to. Required if ` + "`" + `userId` + "`" + ` is not provided",
+                        "name": "ethAddress",
                         "in": "query"
                     },
                     {
-                        "description": "Request params",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/main.StartNewMiningSessionRequestBody"
-                        }
+                        "type": "integer",
+                        "default": 0,
+                        "description": "current cursor to fetch data from",
+                        "name": "cursor",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "count of records in response, 5000 by default",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
-                        "schema": {
-                            "$ref": "#/definitions/tokenomics.MiningSummary"
-                        }
-                    },
-                    "400": {
-                        "description": "if validations fail",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/server.ErrorResponse"
+                            "$ref": "#/definitions/coindistribution.CoinDistributionPayouts"
                         }
                     },
                     "401": {
@@ -387,18 +381,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "if user not found",
-                        "schema": {
-                            "$ref": "#/definitions/server.ErrorResponse"
-                        }
-                    },
-                    "409": {
-                        "description": "if mining is in progress or if a decision about negative mining progress or kyc is required",
-                        "schema": {
-                            "$ref": "#/definitions/server.ErrorResponse"
-                        }
-                    },
                     "422": {
                         "description": "if syntax fails",
                         "schema": {
@@ -420,9 +402,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/tokenomics/{userId}/pre-staking": {
-            "put": {
-                "description": "Starts or updates pre-staking for the user.",
+        "/getCoinDistributionsForReview": {
+            "post": {
+                "description": "Fetches data of pending coin distributions for review.",
                 "consumes": [
                     "application/json"
                 ],
@@ -430,7 +412,7 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Tokenomics"
+                    "CoinDistribution"
                 ],
                 "parameters": [
                     {
@@ -443,30 +425,955 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "ID of the user",
-                        "name": "userId",
-                        "in": "path",
-                        "required": true
+                        "description": "the type of the client calling this API. I.E. ` + "`" + `web` + "`" + `",
+                        "name": "x_client_type",
+                        "in": "query"
                     },
                     {
-                        "description": "Request params",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/main.StartOrUpdatePreStakingRequestBody"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/tokenomics.PreStakingSummary"
-                        }
+                        "type": "integer",
+                        "default": 0,
+                        "description": "current cursor to fetch data from",
+                        "name": "cursor",
+                        "in": "query",
+                        "required": true
                     },
-                    "400": {
-                        "description": "if validations fail",
+                    {
+                        "type": "integer",
+                        "description": "count of records in response, 5000 by default",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "description": "if u want to order by createdAt",
+                        "name": "createdAtOrderBy",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "description": "if u want to order by ice amount",
+                        "name": "iceOrderBy",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "description": "if u want to order by username lexicographically",
+                        "name": "usernameOrderBy",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "description": "if u want to order by referredByUsername lexicographically",
+                        "name": "referredByUsernameOrderBy",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "if u want to find usernames starting with keyword",
+                        "name": "usernameKeyword",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "if u want to find referredByUsernames starting with keyword",
+                        "name": "referredByUsernameKeyword",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "if u want to find specific users",
+                        "name": "userIds",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "if u want to find distributions with at least this amount of ice",
+                        "name": "minIce",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "if u want to find distributions with at most this amount of ice",
+                        "name": "maxIce",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/coindistribution.CoinDistributionsForReview"
+                        }
+                    },
+                    "401": {
+                        "description": "if not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "if not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "if syntax fails",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "if request times out",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/getCoinDistributionsReviewDiff": {
+            "post": {
+                "description": "Compares the pending coin distributions with the ones approved in the previous cycle: new and changed eth addresses, amount jumps, eth addresses shared by several users and the aggregates by country and by referredByUsername.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "CoinDistribution"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "the type of the client calling this API. I.E. ` + "`" + `web` + "`" + `",
+                        "name": "x_client_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "the increase, compared to the previous cycle, above which an user is reported in ` + "`" + `amountJumps` + "`" + `, 100 by default",
+                        "name": "jumpPercent",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "count of records in each section of the response, 100 by default",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/coindistribution.CoinDistributionsReviewDiff"
+                        }
+                    },
+                    "401": {
+                        "description": "if not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "if not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "if syntax fails",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "if request times out",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/getRejectedCoinDistributions": {
+            "post": {
+                "description": "Fetches the rejected coin distributions, grouped by their transaction (or batch, if they were never sent), the latest rejected first.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "CoinDistribution"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "the type of the client calling this API. I.E. ` + "`" + `web` + "`" + `",
+                        "name": "x_client_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "if u want to find only the ones of a specific target, i.e. ` + "`" + `ethereum` + "`" + `",
+                        "name": "target",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "current cursor to fetch data from",
+                        "name": "cursor",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "count of records in response, 5000 by default",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/coindistribution.RejectedCoinDistributions"
+                        }
+                    },
+                    "401": {
+                        "description": "if not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "if not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "if syntax fails",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "if request times out",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/holdCoinDistributions": {
+            "post": {
+                "description": "Puts the coin distributions of specific users on hold, instead of denying them. What they have pending review is carried into the next cycle and merged with its coin distributions, or into the one after the hold is released, if ` + "`" + `untilReleased` + "`" + ` is set.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "CoinDistribution"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "the type of the client calling this API. I.E. ` + "`" + `web` + "`" + `",
+                        "name": "x_client_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "the users to hold",
+                        "name": "userIds",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "if u want to hold them until they're released via ` + "`" + `releaseCoinDistributionHolds` + "`" + `, instead of until the next cycle",
+                        "name": "untilReleased",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "why they're on hold",
+                        "name": "reason",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/coindistribution.HeldCoinDistributions"
+                        }
+                    },
+                    "401": {
+                        "description": "if not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "if not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "if syntax fails",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "if request times out",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/releaseCoinDistributionHolds": {
+            "post": {
+                "description": "Releases the holds of specific users, what they have on hold is carried into the next cycle.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "CoinDistribution"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "the type of the client calling this API. I.E. ` + "`" + `web` + "`" + `",
+                        "name": "x_client_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "the users to release",
+                        "name": "userIds",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "401": {
+                        "description": "if not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "if not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "if none of the users is on hold",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "if syntax fails",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "if request times out",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/resolveRejectedCoinDistributions": {
+            "post": {
+                "description": "Requeues the rejected coin distributions of a transaction (or batch), after checking it really failed on-chain, or moves them to the permanent failures ledger. Every decision is audited.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "CoinDistribution"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "the type of the client calling this API. I.E. ` + "`" + `web` + "`" + `",
+                        "name": "x_client_type",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "requeue",
+                            "fail"
+                        ],
+                        "type": "string",
+                        "description": "` + "`" + `requeue` + "`" + ` puts them back to be distributed again, ` + "`" + `fail` + "`" + ` gives up on them for good",
+                        "name": "decision",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "the target of the rejected coin distributions, as returned by ` + "`" + `getRejectedCoinDistributions` + "`" + `",
+                        "name": "target",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "the batch of the rejected coin distributions, as returned by ` + "`" + `getRejectedCoinDistributions` + "`" + `",
+                        "name": "batchId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "the transaction of the rejected coin distributions, as returned by ` + "`" + `getRejectedCoinDistributions` + "`" + `",
+                        "name": "txHash",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "401": {
+                        "description": "if not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "if not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "if there are no such rejected coin distributions",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "if the transaction was mined successfully or it's not known to have failed",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "if syntax fails",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "if request times out",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reviewDistributions": {
+            "post": {
+                "description": "Reviews Coin Distributions. If any filter is provided, the decision is applied only to the pending coin distributions matching it. Approving a cycle that breaches any budget guard requires ` + "`" + `overrideBudgetGuards` + "`" + `. An approval is executed only once ` + "`" + `quorum` + "`" + ` distinct reviewers approved the same snapshot, a deny is executed right away.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "CoinDistribution"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "the type of the client calling this API. I.E. ` + "`" + `web` + "`" + `",
+                        "name": "x_client_type",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "approve",
+                            "approve-and-process-immediately",
+                            "deny"
+                        ],
+                        "type": "string",
+                        "description": "the decision for the current coin distributions",
+                        "name": "decision",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "if u want to review only usernames starting with keyword",
+                        "name": "usernameKeyword",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "if u want to review only referredByUsernames starting with keyword",
+                        "name": "referredByUsernameKeyword",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "if u want to review only specific users",
+                        "name": "userIds",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "if u want to review only distributions with at least this amount of ice",
+                        "name": "minIce",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "if u want to review only distributions with at most this amount of ice",
+                        "name": "maxIce",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "if u want to approve even though the cycle breaches the budget guards, see ` + "`" + `budgetBreaches` + "`" + ` of ` + "`" + `getCoinDistributionsForReview` + "`" + `",
+                        "name": "overrideBudgetGuards",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "the ` + "`" + `snapshot` + "`" + ` of ` + "`" + `getCoinDistributionsForReview` + "`" + ` u have reviewed, the vote is rejected if the distributions changed since",
+                        "name": "snapshot",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/coindistribution.CoinDistributionsReviewVotes"
+                        }
+                    },
+                    "401": {
+                        "description": "if not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "if not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "if the cycle breaches any budget guard and they're not overridden, the snapshot changed or the screening is in progress",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "if syntax fails",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "if request times out",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tokenomics/{userId}/extra-bonus-claims": {
+            "post": {
+                "description": "Claims an extra bonus for the user.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tokenomics"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the user",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/tokenomics.ExtraBonusSummary"
+                        }
+                    },
+                    "400": {
+                        "description": "if validations fail",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "if not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "if not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "if user not found or no extra bonus available",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "if already claimed",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "if syntax fails",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "if request times out",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tokenomics/{userId}/mining-sessions": {
+            "post": {
+                "description": "Starts a new mining session for the user, if not already in progress with another one.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tokenomics"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the user",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "the type of the client calling this API. I.E. ` + "`" + `web` + "`" + `",
+                        "name": "x_client_type",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Request params",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/main.StartNewMiningSessionRequestBody"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/tokenomics.MiningSummary"
+                        }
+                    },
+                    "400": {
+                        "description": "if validations fail",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "if not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "if not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "if user not found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "if mining is in progress or if a decision about negative mining progress or kyc is required",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "if syntax fails",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "if request times out",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tokenomics/{userId}/pre-staking": {
+            "put": {
+                "description": "Starts or updates pre-staking for the user.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tokenomics"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the user",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Request params",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/main.StartOrUpdatePreStakingRequestBody"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/tokenomics.PreStakingSummary"
+                        }
+                    },
+                    "400": {
+                        "description": "if validations fail",
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
@@ -509,12 +1416,229 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
+        }
+    },
+    "definitions": {
+        "coindistribution.BudgetBreach": {
+            "type": "object",
+            "properties": {
+                "actual": {
+                    "type": "number",
+                    "example": 5065.3
+                },
+                "guard": {
+                    "type": "string",
+                    "enum": [
+                        "maxCycleIce",
+                        "maxUserIce",
+                        "maxCycleIncreasePercent"
+                    ],
+                    "example": "maxUserIce"
+                },
+                "limit": {
+                    "type": "number",
+                    "example": 1000
+                },
+                "users": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "coindistribution.CoinDistributionHold": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string",
+                    "example": "2022-01-03T16:20:52.156534Z"
+                },
+                "heldIce": {
+                    "description": "HeldIce is waiting to be carried into the next cycle, once the hold is released.",
+                    "type": "number",
+                    "example": 100
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "suspected multi-accounting"
+                },
+                "releasedAt": {
+                    "type": "string",
+                    "example": "2022-01-03T16:20:52.156534Z"
+                },
+                "releasedByUserId": {
+                    "type": "string",
+                    "example": "system"
+                },
+                "reviewerUserId": {
+                    "type": "string",
+                    "example": "12746386-03de-44d7-91c7-856fa66b6ed6"
+                },
+                "untilReleased": {
+                    "description": "UntilReleased holds last until they're released manually, the others are released by the next cycle.",
+                    "type": "boolean",
+                    "example": true
+                },
+                "userId": {
+                    "type": "string",
+                    "example": "12746386-03de-44d7-91c7-856fa66b6ed6"
+                }
+            }
+        },
+        "coindistribution.CoinDistributionHolds": {
+            "type": "object",
+            "properties": {
+                "cursor": {
+                    "type": "integer",
+                    "example": 5065
+                },
+                "holds": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/coindistribution.CoinDistributionHold"
+                    }
+                }
+            }
+        },
+        "coindistribution.CoinDistributionMerkleProof": {
+            "type": "object",
+            "properties": {
+                "contractAddress": {
+                    "type": "string",
+                    "example": "0x43...."
+                },
+                "cycle": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "ethAddress": {
+                    "type": "string",
+                    "example": "0x43...."
+                },
+                "iceflakes": {
+                    "type": "string",
+                    "example": "100000000000000"
+                },
+                "proof": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "0x1b2c....",
+                        "0x3d4e...."
+                    ]
+                },
+                "publishedAt": {
+                    "type": "string",
+                    "example": "2022-01-03T16:20:52.156534Z"
+                },
+                "root": {
+                    "type": "string",
+                    "example": "0x9f2c...."
+                },
+                "target": {
+                    "type": "string",
+                    "example": "ethereum"
+                },
+                "txHash": {
+                    "type": "string",
+                    "example": "0x5c50...."
+                }
+            }
+        },
+        "coindistribution.CoinDistributionMerkleProofs": {
+            "type": "object",
+            "properties": {
+                "proofs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/coindistribution.CoinDistributionMerkleProof"
+                    }
+                }
+            }
+        },
+        "coindistribution.CoinDistributionPayout": {
+            "type": "object",
+            "properties": {
+                "batchId": {
+                    "type": "string",
+                    "example": "01HN7W4RQ0JXZ8K3GSM4YJ1D2V"
+                },
+                "blockHash": {
+                    "type": "string",
+                    "example": "0x8f1e...."
+                },
+                "blockNumber": {
+                    "type": "integer",
+                    "example": 35800000
+                },
+                "day": {
+                    "type": "string",
+                    "example": "2022-01-03T00:00:00Z"
+                },
+                "effectiveGasPrice": {
+                    "type": "string",
+                    "example": "3000000000"
+                },
+                "ethAddress": {
+                    "type": "string",
+                    "example": "0x43...."
+                },
+                "gasUsed": {
+                    "type": "integer",
+                    "example": 21000000
+                },
+                "iceflakes": {
+                    "type": "string",
+                    "example": "100000000000000"
+                },
+                "settledAt": {
+                    "type": "string",
+                    "example": "2022-01-03T16:20:52.156534Z"
+                },
+                "target": {
+                    "type": "string",
+                    "example": "ethereum"
+                },
+                "time": {
+                    "type": "string",
+                    "example": "2022-01-03T16:20:52.156534Z"
+                },
+                "txHash": {
+                    "type": "string",
+                    "example": "0x5c50...."
+                },
+                "userId": {
+                    "type": "string",
+                    "example": "12746386-03de-44d7-91c7-856fa66b6ed6"
+                }
+            }
+        },
+        "coindistribution.CoinDistributionPayouts": {
+            "type": "object",
+            "properties": {
+                "cursor": {
+                    "type": "integer",
+                    "example": 5065
+                },
+                "payouts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/coindistribution.CoinDistributionPayout"
+                    }
+                }
+            }
+        },
         "coindistribution.CoinDistributionsForReview": {
             "type": "object",
             "properties": {
+                "budgetBreaches": {
+                    "description": "BudgetBreaches are the budget guards the whole cycle breaches, it can't be approved without overriding them.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/coindistribution.BudgetBreach"
+                    }
+                },
                 "cursor": {
                     "type": "integer",
                     "example": 5065
@@ -525,6 +1649,15 @@ const docTemplate = `{
                         "$ref": "#/definitions/coindistribution.PendingReview"
                     }
                 },
+                "quorum": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "snapshot": {
+                    "description": "Snapshot identifies the distributions matching the filter, the approvals are counted per snapshot.",
+                    "type": "string",
+                    "example": "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+                },
                 "totalIce": {
                     "type": "number",
                     "example": 5065.3
@@ -532,16 +1665,227 @@ const docTemplate = `{
                 "totalRows": {
                     "type": "integer",
                     "example": 5065
+                },
+                "votes": {
+                    "description": "Votes are all the open votes, of any snapshot.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/coindistribution.CoinDistributionsReviewVote"
+                    }
+                }
+            }
+        },
+        "coindistribution.CoinDistributionsReviewDiff": {
+            "type": "object",
+            "properties": {
+                "amountJumps": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/coindistribution.CoinDistributionsReviewDiffRecord"
+                    }
+                },
+                "byCountry": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/coindistribution.CoinDistributionsReviewDiffAggregate"
+                    }
+                },
+                "byReferredByUsername": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/coindistribution.CoinDistributionsReviewDiffAggregate"
+                    }
+                },
+                "changedEthAddresses": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/coindistribution.CoinDistributionsReviewDiffRecord"
+                    }
+                },
+                "newEthAddresses": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/coindistribution.CoinDistributionsReviewDiffRecord"
+                    }
+                },
+                "previousReviewDay": {
+                    "type": "string",
+                    "example": "2022-01-03"
+                },
+                "sharedEthAddresses": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/coindistribution.CoinDistributionsReviewDiffSharedEthAddress"
+                    }
+                }
+            }
+        },
+        "coindistribution.CoinDistributionsReviewDiffAggregate": {
+            "type": "object",
+            "properties": {
+                "ice": {
+                    "type": "number",
+                    "example": 1000
+                },
+                "key": {
+                    "type": "string",
+                    "example": "US"
+                },
+                "previousIce": {
+                    "type": "number",
+                    "example": 400
+                },
+                "previousUsers": {
+                    "type": "integer",
+                    "example": 25
+                },
+                "users": {
+                    "type": "integer",
+                    "example": 30
+                }
+            }
+        },
+        "coindistribution.CoinDistributionsReviewDiffRecord": {
+            "type": "object",
+            "properties": {
+                "ethAddress": {
+                    "type": "string",
+                    "example": "0x43...."
+                },
+                "ice": {
+                    "type": "number",
+                    "example": 1000
+                },
+                "increasePercent": {
+                    "type": "number",
+                    "example": 150
+                },
+                "previousEthAddress": {
+                    "type": "string",
+                    "example": "0x12...."
+                },
+                "previousIce": {
+                    "type": "number",
+                    "example": 400
+                },
+                "userId": {
+                    "type": "string",
+                    "example": "12746386-03de-44d7-91c7-856fa66b6ed6"
+                },
+                "username": {
+                    "type": "string",
+                    "example": "myusername"
+                }
+            }
+        },
+        "coindistribution.CoinDistributionsReviewDiffSharedEthAddress": {
+            "type": "object",
+            "properties": {
+                "ethAddress": {
+                    "type": "string",
+                    "example": "0x43...."
+                },
+                "ice": {
+                    "type": "number",
+                    "example": 1000
+                },
+                "userIds": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "12746386-03de-44d7-91c7-856fa66b6ed6"
+                    ]
+                },
+                "users": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "coindistribution.CoinDistributionsReviewVote": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string",
+                    "example": "2022-01-03T16:20:52.156534Z"
+                },
+                "decision": {
+                    "type": "string",
+                    "example": "approve"
+                },
+                "filter": {
+                    "type": "string",
+                    "example": "minIce=10.00"
+                },
+                "reviewerUserId": {
+                    "type": "string",
+                    "example": "12746386-03de-44d7-91c7-856fa66b6ed6"
+                },
+                "snapshot": {
+                    "type": "string",
+                    "example": "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+                }
+            }
+        },
+        "coindistribution.CoinDistributionsReviewVotes": {
+            "type": "object",
+            "properties": {
+                "executed": {
+                    "description": "Executed is false while the snapshot is waiting for more approvals.",
+                    "type": "boolean",
+                    "example": false
+                },
+                "quorum": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "snapshot": {
+                    "type": "string",
+                    "example": "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+                },
+                "votes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/coindistribution.CoinDistributionsReviewVote"
+                    }
+                }
+            }
+        },
+        "coindistribution.HeldCoinDistributions": {
+            "type": "object",
+            "properties": {
+                "ice": {
+                    "type": "number",
+                    "example": 1000
+                },
+                "rows": {
+                    "type": "integer",
+                    "example": 3
                 }
             }
         },
         "coindistribution.PendingReview": {
             "type": "object",
             "properties": {
+                "country": {
+                    "type": "string",
+                    "example": "US"
+                },
                 "ethAddress": {
                     "type": "string",
                     "example": "0x43...."
                 },
+                "exceedsMaxUserIce": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "heldIce": {
+                    "description": "HeldIce is the part of ice that was on hold and is carried over from a previous cycle.",
+                    "type": "number",
+                    "example": 100
+                },
                 "ice": {
                     "type": "number",
                     "example": 1000
@@ -568,6 +1912,72 @@ const docTemplate = `{
                 }
             }
         },
+        "coindistribution.RejectedCoinDistributionBatch": {
+            "type": "object",
+            "properties": {
+                "batchId": {
+                    "type": "string",
+                    "example": "01HN7W4RQ0JXZ8K3GSM4YJ1D2V"
+                },
+                "error": {
+                    "type": "string",
+                    "example": "transaction 0x5c50.... failed"
+                },
+                "iceflakes": {
+                    "type": "string",
+                    "example": "100000000000000"
+                },
+                "records": {
+                    "type": "integer",
+                    "example": 700
+                },
+                "rejectedAt": {
+                    "type": "string",
+                    "example": "2022-01-03T16:20:52.156534Z"
+                },
+                "replacedTxs": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "0x1b2c...."
+                    ]
+                },
+                "target": {
+                    "type": "string",
+                    "example": "ethereum"
+                },
+                "txHash": {
+                    "type": "string",
+                    "example": "0x5c50...."
+                },
+                "userIds": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "12746386-03de-44d7-91c7-856fa66b6ed6"
+                    ]
+                }
+            }
+        },
+        "coindistribution.RejectedCoinDistributions": {
+            "type": "object",
+            "properties": {
+                "batches": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/coindistribution.RejectedCoinDistributionBatch"
+                    }
+                },
+                "cursor": {
+                    "type": "integer",
+                    "example": 5065
+                }
+            }
+        },
         "main.StartNewMiningSessionRequestBody": {
             "type": "object",
             "properties": {
@@ -4,19 +4,34 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"strings"
+	stdlibtime "time"
+
+	"github.com/gin-gonic/gin"
 
 	"github.com/pkg/errors"
 
 	coindistribution "github.com/ice-blockchain/freezer/coin-distribution"
+	"github.com/ice-blockchain/wintr/log"
 	"github.com/ice-blockchain/wintr/server"
+	"github.com/ice-blockchain/wintr/time"
 )
 
 func (s *service) setupCoinDistributionRoutes(router *server.Router) {
 	router.
 		Group("/v1w").
 		POST("/getCoinDistributionsForReview", server.RootHandler(s.GetCoinDistributionsForReview)).
-		POST("/reviewDistributions", server.RootHandler(s.ReviewCoinDistributions))
+		POST("/getCoinDistributionsReviewDiff", server.RootHandler(s.GetCoinDistributionsReviewDiff)).
+		POST("/reviewDistributions", server.RootHandler(s.ReviewCoinDistributions)).
+		POST("/getCoinDistributionPayouts", server.RootHandler(s.GetCoinDistributionPayouts)).
+		POST("/exportCoinDistributions", withResponseWriter(server.RootHandler(s.ExportCoinDistributions))).
+		GET("/coin-distributions/:userId/merkle-proofs", server.RootHandler(s.GetCoinDistributionMerkleProofs)).
+		POST("/getRejectedCoinDistributions", server.RootHandler(s.GetRejectedCoinDistributions)).
+		POST("/resolveRejectedCoinDistributions", server.RootHandler(s.ResolveRejectedCoinDistributions)).
+		POST("/getCoinDistributionHolds", server.RootHandler(s.GetCoinDistributionHolds)).
+		POST("/holdCoinDistributions", server.RootHandler(s.HoldCoinDistributions)).
+		POST("/releaseCoinDistributionHolds", server.RootHandler(s.ReleaseCoinDistributionHolds))
 }
 
 // GetCoinDistributionsForReview godoc
@@ -26,16 +41,19 @@ func (s *service) setupCoinDistributionRoutes(router *server.Router) {
 //	@Tags			CoinDistribution
 //	@Accept			json
 //	@Produce		json
-//	@Param			Authorization				header		string	true	"Insert your access token"	default(Bearer <Add access token here>)
-//	@Param			x_client_type				query		string	false	"the type of the client calling this API. I.E. `web`"
-//	@Param			cursor						query		uint64	true	"current cursor to fetch data from"	default(0)
-//	@Param			limit						query		uint64	false	"count of records in response, 5000 by default"
-//	@Param			createdAtOrderBy			query		string	false	"if u want to order by createdAt"								Enums(asc,desc)
-//	@Param			iceOrderBy					query		string	false	"if u want to order by ice amount"								Enums(asc,desc)
-//	@Param			usernameOrderBy				query		string	false	"if u want to order by username lexicographically"				Enums(asc,desc)
-//	@Param			referredByUsernameOrderBy	query		string	false	"if u want to order by referredByUsername lexicographically"	Enums(asc,desc)
-//	@Param			usernameKeyword				query		string	false	"if u want to find usernames starting with keyword"
-//	@Param			referredByUsernameKeyword	query		string	false	"if u want to find referredByUsernames starting with keyword"
+//	@Param			Authorization				header		string		true	"Insert your access token"	default(Bearer <Add access token here>)
+//	@Param			x_client_type				query		string		false	"the type of the client calling this API. I.E. `web`"
+//	@Param			cursor						query		uint64		true	"current cursor to fetch data from"	default(0)
+//	@Param			limit						query		uint64		false	"count of records in response, 5000 by default"
+//	@Param			createdAtOrderBy			query		string		false	"if u want to order by createdAt"								Enums(asc,desc)
+//	@Param			iceOrderBy					query		string		false	"if u want to order by ice amount"								Enums(asc,desc)
+//	@Param			usernameOrderBy				query		string		false	"if u want to order by username lexicographically"				Enums(asc,desc)
+//	@Param			referredByUsernameOrderBy	query		string		false	"if u want to order by referredByUsername lexicographically"	Enums(asc,desc)
+//	@Param			usernameKeyword				query		string		false	"if u want to find usernames starting with keyword"
+//	@Param			referredByUsernameKeyword	query		string		false	"if u want to find referredByUsernames starting with keyword"
+//	@Param			userIds						query		[]string	false	"if u want to find specific users"	collectionFormat(multi)
+//	@Param			minIce						query		number		false	"if u want to find distributions with at least this amount of ice"
+//	@Param			maxIce						query		number		false	"if u want to find distributions with at most this amount of ice"
 //	@Success		200							{object}	coindistribution.CoinDistributionsForReview
 //	@Failure		401							{object}	server.ErrorResponse	"if not authorized"
 //	@Failure		403							{object}	server.ErrorResponse	"if not allowed"
@@ -65,6 +83,9 @@ func (s *service) GetCoinDistributionsForReview( //nolint:gocritic // .
 	if req.Data.ReferredByUsernameOrderBy != "" && !strings.EqualFold(req.Data.ReferredByUsernameOrderBy, "desc") && !strings.EqualFold(req.Data.ReferredByUsernameOrderBy, "asc") { //nolint:lll // .
 		return nil, server.UnprocessableEntity(errors.Errorf("`referredByUsernameOrderBy` has to be `asc` or `desc`"), "invalid params")
 	}
+	if err := validateCoinDistributionsForReviewFilter(&req.Data.CoinDistributionsForReviewFilter); err != nil {
+		return nil, server.UnprocessableEntity(err, "invalid params")
+	}
 	resp, err := s.coinDistributionRepository.GetCoinDistributionsForReview(ctx, req.Data)
 	if err != nil {
 		return nil, server.Unexpected(errors.Wrapf(err, "failed to GetCoinDistributionsForReview for %#v", req.Data))
@@ -73,29 +94,71 @@ func (s *service) GetCoinDistributionsForReview( //nolint:gocritic // .
 	return server.OK(resp), nil
 }
 
-// ReviewCoinDistributions godoc
+// GetCoinDistributionsReviewDiff godoc
 //
 //	@Schemes
-//	@Description	Reviews Coin Distributions.
+//	@Description	Compares the pending coin distributions with the ones approved in the previous cycle: new and changed eth addresses, amount jumps, eth addresses shared by several users and the aggregates by country and by referredByUsername.
 //	@Tags			CoinDistribution
 //	@Accept			json
 //	@Produce		json
-//	@Param			Authorization	header	string	true	"Insert your access token"	default(Bearer <Add access token here>)
-//	@Param			x_client_type	query	string	false	"the type of the client calling this API. I.E. `web`"
-//	@Param			decision		query	string	true	"the decision for the current coin distributions"	Enums(approve,approve-and-process-immediately,deny)
-//	@Success		200				"OK"
+//	@Param			Authorization	header		string	true	"Insert your access token"	default(Bearer <Add access token here>)
+//	@Param			x_client_type	query		string	false	"the type of the client calling this API. I.E. `web`"
+//	@Param			jumpPercent		query		number	false	"the increase, compared to the previous cycle, above which an user is reported in `amountJumps`, 100 by default"
+//	@Param			limit			query		uint64	false	"count of records in each section of the response, 100 by default"
+//	@Success		200				{object}	coindistribution.CoinDistributionsReviewDiff
 //	@Failure		401				{object}	server.ErrorResponse	"if not authorized"
 //	@Failure		403				{object}	server.ErrorResponse	"if not allowed"
 //	@Failure		422				{object}	server.ErrorResponse	"if syntax fails"
 //	@Failure		500				{object}	server.ErrorResponse
 //	@Failure		504				{object}	server.ErrorResponse	"if request times out"
+//	@Router			/getCoinDistributionsReviewDiff [POST].
+func (s *service) GetCoinDistributionsReviewDiff( //nolint:gocritic // .
+	ctx context.Context,
+	req *server.Request[coindistribution.GetCoinDistributionsReviewDiffArg, coindistribution.CoinDistributionsReviewDiff],
+) (*server.Response[coindistribution.CoinDistributionsReviewDiff], *server.Response[server.ErrorResponse]) {
+	if req.AuthenticatedUser.Role != adminRole {
+		return nil, server.Forbidden(errors.Errorf("insufficient role: %v, admin role required", req.AuthenticatedUser.Role))
+	}
+	if req.Data.JumpPercent < 0 {
+		return nil, server.UnprocessableEntity(errors.Errorf("`jumpPercent` can't be negative"), "invalid params")
+	}
+	resp, err := s.coinDistributionRepository.GetCoinDistributionsReviewDiff(ctx, req.Data)
+	if err != nil {
+		return nil, server.Unexpected(errors.Wrapf(err, "failed to GetCoinDistributionsReviewDiff for %#v", req.Data))
+	}
+
+	return server.OK(resp), nil
+}
+
+// ReviewCoinDistributions godoc
+//
+//	@Schemes
+//	@Description	Reviews Coin Distributions. If any filter is provided, the decision is applied only to the pending coin distributions matching it. Approving a cycle that breaches any budget guard requires `overrideBudgetGuards`. An approval is executed only once `quorum` distinct reviewers approved the same snapshot, a deny is executed right away.
+//	@Tags			CoinDistribution
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization				header		string		true	"Insert your access token"	default(Bearer <Add access token here>)
+//	@Param			x_client_type				query		string		false	"the type of the client calling this API. I.E. `web`"
+//	@Param			decision					query		string		true	"the decision for the current coin distributions"	Enums(approve,approve-and-process-immediately,deny)
+//	@Param			usernameKeyword				query		string		false	"if u want to review only usernames starting with keyword"
+//	@Param			referredByUsernameKeyword	query		string		false	"if u want to review only referredByUsernames starting with keyword"
+//	@Param			userIds						query		[]string	false	"if u want to review only specific users"	collectionFormat(multi)
+//	@Param			minIce						query		number		false	"if u want to review only distributions with at least this amount of ice"
+//	@Param			maxIce						query		number		false	"if u want to review only distributions with at most this amount of ice"
+//	@Param			overrideBudgetGuards		query		boolean		false	"if u want to approve even though the cycle breaches the budget guards, see `budgetBreaches` of `getCoinDistributionsForReview`"
+//	@Param			snapshot					query		string		false	"the `snapshot` of `getCoinDistributionsForReview` u have reviewed, the vote is rejected if the distributions changed since"
+//	@Success		200							{object}	coindistribution.CoinDistributionsReviewVotes
+//	@Failure		401							{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403							{object}	server.ErrorResponse	"if not allowed"
+//	@Failure		409							{object}	server.ErrorResponse	"if the cycle breaches any budget guard and they're not overridden, the snapshot changed or the screening is in progress"
+//	@Failure		422							{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500							{object}	server.ErrorResponse
+//	@Failure		504							{object}	server.ErrorResponse	"if request times out"
 //	@Router			/reviewDistributions [POST].
 func (s *service) ReviewCoinDistributions( //nolint:gocritic // .
 	ctx context.Context,
-	req *server.Request[struct {
-		Decision string `form:"decision" required:"true" swaggerignore:"true" enums:"approve,approve-and-process-immediately,deny"`
-	}, any],
-) (*server.Response[any], *server.Response[server.ErrorResponse]) {
+	req *server.Request[coindistribution.ReviewCoinDistributionsArg, coindistribution.CoinDistributionsReviewVotes],
+) (*server.Response[coindistribution.CoinDistributionsReviewVotes], *server.Response[server.ErrorResponse]) {
 	if req.AuthenticatedUser.Role != adminRole {
 		return nil, server.Forbidden(errors.Errorf("insufficient role: %v, admin role required", req.AuthenticatedUser.Role))
 	}
@@ -104,9 +167,395 @@ func (s *service) ReviewCoinDistributions( //nolint:gocritic // .
 		!strings.EqualFold(req.Data.Decision, "deny") {
 		return nil, server.UnprocessableEntity(errors.Errorf("`decision` has to be `approve`, `approve-and-process-immediately` or `deny`"), "invalid params")
 	}
-	if err := s.coinDistributionRepository.ReviewCoinDistributions(ctx, req.AuthenticatedUser.UserID, req.Data.Decision); err != nil {
-		return nil, server.Unexpected(errors.Wrapf(err, "failed to ReviewCoinDistributions for adminUserID:%v,decision:%v", req.AuthenticatedUser.UserID, req.Data.Decision)) //nolint:lll // .
+	if err := validateCoinDistributionsForReviewFilter(&req.Data.CoinDistributionsForReviewFilter); err != nil {
+		return nil, server.UnprocessableEntity(err, "invalid params")
+	}
+	votes, err := s.coinDistributionRepository.ReviewCoinDistributions(ctx, req.AuthenticatedUser.UserID, req.Data)
+	if err != nil {
+		err = errors.Wrapf(err, "failed to ReviewCoinDistributions for adminUserID:%v,arg:%#v", req.AuthenticatedUser.UserID, req.Data)
+		switch {
+		case errors.Is(err, coindistribution.ErrBudgetGuardsBreached):
+			return nil, server.Conflict(err, budgetGuardsBreachedErrorCode)
+		case errors.Is(err, coindistribution.ErrReviewSnapshotChanged):
+			return nil, server.Conflict(err, reviewSnapshotChangedErrorCode)
+		case errors.Is(err, coindistribution.ErrScreeningInProgress):
+			return nil, server.Conflict(err, screeningInProgressErrorCode)
+		}
+
+		return nil, server.Unexpected(err)
+	}
+
+	return server.OK(votes), nil
+}
+
+// GetCoinDistributionPayouts godoc
+//
+//	@Schemes
+//	@Description	Fetches the settled (mined on-chain) coin distributions of an user or of an eth address, newest first.
+//	@Tags			CoinDistribution
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string	true	"Insert your access token"	default(Bearer <Add access token here>)
+//	@Param			x_client_type	query		string	false	"the type of the client calling this API. I.E. `web`"
+//	@Param			userId			query		string	false	"the id of the user. Required if `ethAddress` is not provided"
+//	@Param			ethAddress		query		string	false	"the eth address the coins were sent to. Required if `userId` is not provided"
+//	@Param			cursor			query		uint64	true	"current cursor to fetch data from"	default(0)
+//	@Param			limit			query		uint64	false	"count of records in response, 5000 by default"
+//	@Success		200				{object}	coindistribution.CoinDistributionPayouts
+//	@Failure		401				{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403				{object}	server.ErrorResponse	"if not allowed"
+//	@Failure		422				{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500				{object}	server.ErrorResponse
+//	@Failure		504				{object}	server.ErrorResponse	"if request times out"
+//	@Router			/getCoinDistributionPayouts [POST].
+func (s *service) GetCoinDistributionPayouts( //nolint:gocritic // .
+	ctx context.Context,
+	req *server.Request[coindistribution.GetCoinDistributionPayoutsArg, coindistribution.CoinDistributionPayouts],
+) (*server.Response[coindistribution.CoinDistributionPayouts], *server.Response[server.ErrorResponse]) {
+	if req.AuthenticatedUser.Role != adminRole {
+		return nil, server.Forbidden(errors.Errorf("insufficient role: %v, admin role required", req.AuthenticatedUser.Role))
+	}
+	if req.Data.UserID == "" && req.Data.EthAddress == "" {
+		return nil, server.UnprocessableEntity(errors.Errorf("`userId` or `ethAddress` is required"), "invalid params")
+	}
+	if req.Data.Limit == 0 {
+		req.Data.Limit = defaultDistributionLimit
+	}
+	resp, err := s.coinDistributionRepository.GetCoinDistributionPayouts(ctx, req.Data)
+	if err != nil {
+		return nil, server.Unexpected(errors.Wrapf(err, "failed to GetCoinDistributionPayouts for %#v", req.Data))
+	}
+
+	return server.OK(resp), nil
+}
+
+// GetCoinDistributionMerkleProofs godoc
+//
+//	@Schemes
+//	@Description	Fetches the Merkle proofs the user needs to claim the coins of the cycles published in the Merkle-claim mode, newest cycle first.
+//	@Tags			CoinDistribution
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string	true	"Insert your access token"	default(Bearer <Add access token here>)
+//	@Param			userId			path		string	true	"ID of the user"
+//	@Param			x_client_type	query		string	false	"the type of the client calling this API. I.E. `web`"
+//	@Success		200				{object}	coindistribution.CoinDistributionMerkleProofs
+//	@Failure		401				{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403				{object}	server.ErrorResponse	"if not allowed"
+//	@Failure		422				{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500				{object}	server.ErrorResponse
+//	@Failure		504				{object}	server.ErrorResponse	"if request times out"
+//	@Router			/coin-distributions/{userId}/merkle-proofs [GET].
+func (s *service) GetCoinDistributionMerkleProofs( //nolint:gocritic // .
+	ctx context.Context,
+	req *server.Request[coindistribution.GetCoinDistributionMerkleProofsArg, coindistribution.CoinDistributionMerkleProofs],
+) (*server.Response[coindistribution.CoinDistributionMerkleProofs], *server.Response[server.ErrorResponse]) {
+	resp, err := s.coinDistributionRepository.GetCoinDistributionMerkleProofs(ctx, req.Data)
+	if err != nil {
+		return nil, server.Unexpected(errors.Wrapf(err, "failed to GetCoinDistributionMerkleProofs for userID:%v", req.Data.UserID))
+	}
+
+	return server.OK(resp), nil
+}
+
+// GetRejectedCoinDistributions godoc
+//
+//	@Schemes
+//	@Description	Fetches the rejected coin distributions, grouped by their transaction (or batch, if they were never sent), the latest rejected first.
+//	@Tags			CoinDistribution
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string	true	"Insert your access token"	default(Bearer <Add access token here>)
+//	@Param			x_client_type	query		string	false	"the type of the client calling this API. I.E. `web`"
+//	@Param			target			query		string	false	"if u want to find only the ones of a specific target, i.e. `ethereum`"
+//	@Param			cursor			query		uint64	true	"current cursor to fetch data from"	default(0)
+//	@Param			limit			query		uint64	false	"count of records in response, 5000 by default"
+//	@Success		200				{object}	coindistribution.RejectedCoinDistributions
+//	@Failure		401				{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403				{object}	server.ErrorResponse	"if not allowed"
+//	@Failure		422				{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500				{object}	server.ErrorResponse
+//	@Failure		504				{object}	server.ErrorResponse	"if request times out"
+//	@Router			/getRejectedCoinDistributions [POST].
+func (s *service) GetRejectedCoinDistributions( //nolint:gocritic // .
+	ctx context.Context,
+	req *server.Request[coindistribution.GetRejectedCoinDistributionsArg, coindistribution.RejectedCoinDistributions],
+) (*server.Response[coindistribution.RejectedCoinDistributions], *server.Response[server.ErrorResponse]) {
+	if req.AuthenticatedUser.Role != adminRole {
+		return nil, server.Forbidden(errors.Errorf("insufficient role: %v, admin role required", req.AuthenticatedUser.Role))
+	}
+	if req.Data.Limit == 0 {
+		req.Data.Limit = defaultDistributionLimit
+	}
+	resp, err := s.coinDistributionRepository.GetRejectedCoinDistributions(ctx, req.Data)
+	if err != nil {
+		return nil, server.Unexpected(errors.Wrapf(err, "failed to GetRejectedCoinDistributions for %#v", req.Data))
+	}
+
+	return server.OK(resp), nil
+}
+
+// ResolveRejectedCoinDistributions godoc
+//
+//	@Schemes
+//	@Description	Requeues the rejected coin distributions of a transaction (or batch), after checking it really failed on-chain, or moves them to the permanent failures ledger. Every decision is audited.
+//	@Tags			CoinDistribution
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header	string	true	"Insert your access token"	default(Bearer <Add access token here>)
+//	@Param			x_client_type	query	string	false	"the type of the client calling this API. I.E. `web`"
+//	@Param			decision		query	string	true	"`requeue` puts them back to be distributed again, `fail` gives up on them for good"	Enums(requeue,fail)
+//	@Param			target			query	string	true	"the target of the rejected coin distributions, as returned by `getRejectedCoinDistributions`"
+//	@Param			batchId			query	string	false	"the batch of the rejected coin distributions, as returned by `getRejectedCoinDistributions`"
+//	@Param			txHash			query	string	false	"the transaction of the rejected coin distributions, as returned by `getRejectedCoinDistributions`"
+//	@Success		200				"OK"
+//	@Failure		401				{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403				{object}	server.ErrorResponse	"if not allowed"
+//	@Failure		404				{object}	server.ErrorResponse	"if there are no such rejected coin distributions"
+//	@Failure		409				{object}	server.ErrorResponse	"if the transaction was mined successfully or it's not known to have failed"
+//	@Failure		422				{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500				{object}	server.ErrorResponse
+//	@Failure		504				{object}	server.ErrorResponse	"if request times out"
+//	@Router			/resolveRejectedCoinDistributions [POST].
+func (s *service) ResolveRejectedCoinDistributions( //nolint:gocritic // .
+	ctx context.Context,
+	req *server.Request[coindistribution.ResolveRejectedCoinDistributionsArg, any],
+) (*server.Response[any], *server.Response[server.ErrorResponse]) {
+	if req.AuthenticatedUser.Role != adminRole {
+		return nil, server.Forbidden(errors.Errorf("insufficient role: %v, admin role required", req.AuthenticatedUser.Role))
+	}
+	if !strings.EqualFold(req.Data.Decision, "requeue") && !strings.EqualFold(req.Data.Decision, "fail") {
+		return nil, server.UnprocessableEntity(errors.Errorf("`decision` has to be `requeue` or `fail`"), "invalid params")
+	}
+	if req.Data.Target == "" {
+		return nil, server.UnprocessableEntity(errors.Errorf("`target` is required"), "invalid params")
+	}
+	if err := s.coinDistributionRepository.ResolveRejectedCoinDistributions(ctx, req.AuthenticatedUser.UserID, req.Data); err != nil {
+		err = errors.Wrapf(err, "failed to ResolveRejectedCoinDistributions for adminUserID:%v,arg:%#v", req.AuthenticatedUser.UserID, req.Data)
+		switch {
+		case errors.Is(err, coindistribution.ErrNotFound):
+			return nil, server.NotFound(err, rejectedCoinDistributionsNotFoundErrorCode)
+		case errors.Is(err, coindistribution.ErrRequeueNotAllowed):
+			return nil, server.Conflict(err, requeueNotAllowedErrorCode)
+		}
+
+		return nil, server.Unexpected(err)
+	}
+
+	return server.OK[any](), nil
+}
+
+// GetCoinDistributionHolds godoc
+//
+//	@Schemes
+//	@Description	Fetches the users whose coin distributions are on hold, the latest first.
+//	@Tags			CoinDistribution
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string	true	"Insert your access token"	default(Bearer <Add access token here>)
+//	@Param			x_client_type	query		string	false	"the type of the client calling this API. I.E. `web`"
+//	@Param			includeReleased	query		boolean	false	"if u want the released holds as well"
+//	@Param			cursor			query		uint64	true	"current cursor to fetch data from"	default(0)
+//	@Param			limit			query		uint64	false	"count of records in response, 5000 by default"
+//	@Success		200				{object}	coindistribution.CoinDistributionHolds
+//	@Failure		401				{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403				{object}	server.ErrorResponse	"if not allowed"
+//	@Failure		422				{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500				{object}	server.ErrorResponse
+//	@Failure		504				{object}	server.ErrorResponse	"if request times out"
+//	@Router			/getCoinDistributionHolds [POST].
+func (s *service) GetCoinDistributionHolds( //nolint:gocritic // .
+	ctx context.Context,
+	req *server.Request[coindistribution.GetCoinDistributionHoldsArg, coindistribution.CoinDistributionHolds],
+) (*server.Response[coindistribution.CoinDistributionHolds], *server.Response[server.ErrorResponse]) {
+	if req.AuthenticatedUser.Role != adminRole {
+		return nil, server.Forbidden(errors.Errorf("insufficient role: %v, admin role required", req.AuthenticatedUser.Role))
+	}
+	if req.Data.Limit == 0 {
+		req.Data.Limit = defaultDistributionLimit
+	}
+	resp, err := s.coinDistributionRepository.GetCoinDistributionHolds(ctx, req.Data)
+	if err != nil {
+		return nil, server.Unexpected(errors.Wrapf(err, "failed to GetCoinDistributionHolds for %#v", req.Data))
+	}
+
+	return server.OK(resp), nil
+}
+
+// HoldCoinDistributions godoc
+//
+//	@Schemes
+//	@Description	Puts the coin distributions of specific users on hold, instead of denying them. What they have pending review is carried into the next cycle and merged with its coin distributions, or into the one after the hold is released, if `untilReleased` is set.
+//	@Tags			CoinDistribution
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string		true	"Insert your access token"	default(Bearer <Add access token here>)
+//	@Param			x_client_type	query		string		false	"the type of the client calling this API. I.E. `web`"
+//	@Param			userIds			query		[]string	true	"the users to hold"	collectionFormat(multi)
+//	@Param			untilReleased	query		boolean		false	"if u want to hold them until they're released via `releaseCoinDistributionHolds`, instead of until the next cycle"
+//	@Param			reason			query		string		false	"why they're on hold"
+//	@Success		200				{object}	coindistribution.HeldCoinDistributions
+//	@Failure		401				{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403				{object}	server.ErrorResponse	"if not allowed"
+//	@Failure		422				{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500				{object}	server.ErrorResponse
+//	@Failure		504				{object}	server.ErrorResponse	"if request times out"
+//	@Router			/holdCoinDistributions [POST].
+func (s *service) HoldCoinDistributions( //nolint:gocritic // .
+	ctx context.Context,
+	req *server.Request[coindistribution.HoldCoinDistributionsArg, coindistribution.HeldCoinDistributions],
+) (*server.Response[coindistribution.HeldCoinDistributions], *server.Response[server.ErrorResponse]) {
+	if req.AuthenticatedUser.Role != adminRole {
+		return nil, server.Forbidden(errors.Errorf("insufficient role: %v, admin role required", req.AuthenticatedUser.Role))
+	}
+	if len(req.Data.UserIDs) == 0 {
+		return nil, server.UnprocessableEntity(errors.Errorf("`userIds` is required"), "invalid params")
+	}
+	resp, err := s.coinDistributionRepository.HoldCoinDistributions(ctx, req.AuthenticatedUser.UserID, req.Data)
+	if err != nil {
+		return nil, server.Unexpected(errors.Wrapf(err, "failed to HoldCoinDistributions for reviewerUserID:%v,arg:%#v", req.AuthenticatedUser.UserID, req.Data))
+	}
+
+	return server.OK(resp), nil
+}
+
+// ReleaseCoinDistributionHolds godoc
+//
+//	@Schemes
+//	@Description	Releases the holds of specific users, what they have on hold is carried into the next cycle.
+//	@Tags			CoinDistribution
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header	string		true	"Insert your access token"	default(Bearer <Add access token here>)
+//	@Param			x_client_type	query	string		false	"the type of the client calling this API. I.E. `web`"
+//	@Param			userIds			query	[]string	true	"the users to release"	collectionFormat(multi)
+//	@Success		200				"OK"
+//	@Failure		401				{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403				{object}	server.ErrorResponse	"if not allowed"
+//	@Failure		404				{object}	server.ErrorResponse	"if none of the users is on hold"
+//	@Failure		422				{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500				{object}	server.ErrorResponse
+//	@Failure		504				{object}	server.ErrorResponse	"if request times out"
+//	@Router			/releaseCoinDistributionHolds [POST].
+func (s *service) ReleaseCoinDistributionHolds( //nolint:gocritic // .
+	ctx context.Context,
+	req *server.Request[coindistribution.ReleaseCoinDistributionHoldsArg, any],
+) (*server.Response[any], *server.Response[server.ErrorResponse]) {
+	if req.AuthenticatedUser.Role != adminRole {
+		return nil, server.Forbidden(errors.Errorf("insufficient role: %v, admin role required", req.AuthenticatedUser.Role))
+	}
+	if len(req.Data.UserIDs) == 0 {
+		return nil, server.UnprocessableEntity(errors.Errorf("`userIds` is required"), "invalid params")
+	}
+	if err := s.coinDistributionRepository.ReleaseCoinDistributionHolds(ctx, req.AuthenticatedUser.UserID, req.Data); err != nil {
+		err = errors.Wrapf(err, "failed to ReleaseCoinDistributionHolds for reviewerUserID:%v,arg:%#v", req.AuthenticatedUser.UserID, req.Data)
+		if errors.Is(err, coindistribution.ErrNotFound) {
+			return nil, server.NotFound(err, coinDistributionHoldsNotFoundErrorCode)
+		}
+
+		return nil, server.Unexpected(err)
+	}
+
+	return server.OK[any](), nil
+}
+
+// ExportCoinDistributions godoc
+//
+//	@Schemes
+//	@Description	Streams all the coin distributions of a source matching the filter, as CSV or Parquet. If it fails after the first byte, the `X-Export-Error` trailer holds the reason and the file is incomplete.
+//	@Tags			CoinDistribution
+//	@Accept			json
+//	@Produce		text/csv
+//	@Produce		application/vnd.apache.parquet
+//	@Param			Authorization				header		string		true	"Insert your access token"	default(Bearer <Add access token here>)
+//	@Param			x_client_type				query		string		false	"the type of the client calling this API. I.E. `web`"
+//	@Param			source						query		string		true	"the coin distributions to export"			Enums(pendingReview,reviewed,settled)
+//	@Param			format						query		string		false	"the format of the export, csv by default"	Enums(csv,parquet)
+//	@Param			usernameKeyword				query		string		false	"if u want to find usernames starting with keyword"
+//	@Param			referredByUsernameKeyword	query		string		false	"if u want to find referredByUsernames starting with keyword"
+//	@Param			userIds						query		[]string	false	"if u want to find specific users"	collectionFormat(multi)
+//	@Param			minIce						query		number		false	"if u want to find distributions with at least this amount of ice"
+//	@Param			maxIce						query		number		false	"if u want to find distributions with at most this amount of ice"
+//	@Success		200							{file}		file
+//	@Failure		401							{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403							{object}	server.ErrorResponse	"if not allowed"
+//	@Failure		422							{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500							{object}	server.ErrorResponse
+//	@Failure		504							{object}	server.ErrorResponse	"if request times out"
+//	@Router			/exportCoinDistributions [POST].
+func (s *service) ExportCoinDistributions( //nolint:gocritic // .
+	ctx context.Context,
+	req *server.Request[coindistribution.ExportCoinDistributionsArg, any],
+) (*server.Response[any], *server.Response[server.ErrorResponse]) {
+	if req.AuthenticatedUser.Role != adminRole {
+		return nil, server.Forbidden(errors.Errorf("insufficient role: %v, admin role required", req.AuthenticatedUser.Role))
+	}
+	contentType, ok := exportContentTypes[req.Data.Format]
+	if !ok {
+		return nil, server.UnprocessableEntity(errors.Errorf("`format` has to be `csv` or `parquet`"), "invalid params")
+	}
+	if req.Data.Source != "pendingReview" && req.Data.Source != "reviewed" && req.Data.Source != "settled" {
+		return nil, server.UnprocessableEntity(errors.Errorf("`source` has to be `pendingReview`, `reviewed` or `settled`"), "invalid params")
+	}
+	if err := validateCoinDistributionsForReviewFilter(&req.Data.CoinDistributionsForReviewFilter); err != nil {
+		return nil, server.UnprocessableEntity(err, "invalid params")
+	}
+	responseWriter, ok := ctx.Value(responseWriterCtxValueKey{}).(gin.ResponseWriter)
+	if !ok {
+		return nil, server.Unexpected(errors.New("response writer not found in context"))
+	}
+	extension := req.Data.Format
+	if extension == "" {
+		extension = "csv"
+	}
+	w := &exportResponseWriter{
+		ResponseWriter: responseWriter,
+		contentType:    contentType,
+		filename:       fmt.Sprintf("coin-distributions-%v-%v.%v", req.Data.Source, time.Now().Format(stdlibtime.DateOnly), extension),
+	}
+	// The export outlives the default endpoint timeout, a client that goes away fails the next write anyway.
+	exportCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), exportDeadline)
+	defer cancel()
+	if err := s.coinDistributionRepository.ExportCoinDistributions(exportCtx, req.Data, w); err != nil {
+		err = errors.Wrapf(err, "failed to ExportCoinDistributions for %#v", req.Data)
+		if !w.Written() {
+			return nil, server.Unexpected(err)
+		}
+		log.Error(err)
+		w.Header().Set(exportErrorTrailer, err.Error())
 	}
 
 	return server.OK[any](), nil
 }
+
+// withResponseWriter makes the response writer available to the handlers that stream their response instead of returning it.
+func withResponseWriter(handler gin.HandlerFunc) gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		ginCtx.Request = ginCtx.Request.WithContext(context.WithValue(ginCtx.Request.Context(), responseWriterCtxValueKey{}, ginCtx.Writer))
+		handler(ginCtx)
+	}
+}
+
+// Write sets the headers of the file before its first byte, so that the errors before it are still sent as JSON.
+func (w *exportResponseWriter) Write(data []byte) (int, error) {
+	if !w.Written() {
+		w.Header().Set("Content-Type", w.contentType)
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", w.filename))
+		w.Header().Set("Trailer", exportErrorTrailer)
+	}
+	n, err := w.ResponseWriter.Write(data)
+	w.Flush()
+
+	return n, err //nolint:wrapcheck // It's the writer of the response.
+}
+
+func validateCoinDistributionsForReviewFilter(filter *coindistribution.CoinDistributionsForReviewFilter) error {
+	if filter.MinIce < 0 || filter.MaxIce < 0 {
+		return errors.Errorf("`minIce` and `maxIce` have to be positive")
+	}
+	if filter.MaxIce > 0 && filter.MinIce > filter.MaxIce {
+		return errors.Errorf("`minIce` has to be less than or equal to `maxIce`")
+	}
+
+	return nil
+}
@@ -3,6 +3,10 @@
 package main
 
 import (
+	stdlibtime "time"
+
+	"github.com/gin-gonic/gin"
+
 	"github.com/ice-blockchain/eskimo/users"
 	coindistribution "github.com/ice-blockchain/freezer/coin-distribution"
 	"github.com/ice-blockchain/freezer/tokenomics"
@@ -53,16 +57,39 @@ const (
 	miningDisabledErrorCode                                  = "MINING_DISABLED"
 	noExtraBonusAvailableErrorCode                           = "NO_EXTRA_BONUS_AVAILABLE"
 	extraBonusAlreadyClaimedErrorCode                        = "EXTRA_BONUS_ALREADY_CLAIMED"
+	rejectedCoinDistributionsNotFoundErrorCode               = "REJECTED_COIN_DISTRIBUTIONS_NOT_FOUND"
+	requeueNotAllowedErrorCode                               = "REQUEUE_NOT_ALLOWED"
+	budgetGuardsBreachedErrorCode                            = "BUDGET_GUARDS_BREACHED"
+	reviewSnapshotChangedErrorCode                           = "REVIEW_SNAPSHOT_CHANGED"
+	screeningInProgressErrorCode                             = "SCREENING_IN_PROGRESS"
+	coinDistributionHoldsNotFoundErrorCode                   = "COIN_DISTRIBUTION_HOLDS_NOT_FOUND"
 
 	defaultDistributionLimit = 5000
+
+	exportDeadline     = stdlibtime.Hour
+	exportErrorTrailer = "X-Export-Error"
 )
 
+//nolint:gochecknoglobals // .
+var exportContentTypes = map[string]string{
+	"":        "text/csv",
+	"csv":     "text/csv",
+	"parquet": "application/vnd.apache.parquet",
+}
+
 type (
 	// | service implements server.State and is responsible for managing the state and lifecycle of the package.
 	service struct {
 		tokenomicsProcessor        tokenomics.Processor
 		coinDistributionRepository coindistribution.Repository
 	}
+	responseWriterCtxValueKey struct{}
+	// | exportResponseWriter streams an export as a file download.
+	exportResponseWriter struct {
+		gin.ResponseWriter
+		contentType string
+		filename    string
+	}
 	config struct {
 		Host    string `yaml:"host"`
 		Version string `yaml:"version"`
@@ -0,0 +1,217 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	stdlibtime "time"
+
+	"github.com/goccy/go-json"
+	"github.com/pkg/errors"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/ice-blockchain/freezer/miner"
+	"github.com/ice-blockchain/freezer/model"
+	"github.com/ice-blockchain/wintr/connectors/storage/v3"
+	"github.com/ice-blockchain/wintr/log"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+const (
+	jsonFormat       = "json"
+	clickhouseFormat = "clickhouse"
+)
+
+func main() {
+	var (
+		input                = flag.String("input", "", "path to the snapshot of users")
+		format               = flag.String("format", jsonFormat, "`json` (an array of objects) or `clickhouse` (a `freezer_user_history` export in the JSONEachRow format)")
+		output               = flag.String("output", "", "path to write the report to, stdout if empty")
+		startedAt            = flag.String("started-at", "", "RFC3339 moment the snapshot was taken at, the latest `balance_last_updated_at` in the snapshot if empty")
+		iterations           = flag.Uint64("iterations", 24*30, "number of mining iterations to run, 1 per hour (1 per minute in development mode)") //nolint:gomnd // 30 days.
+		baseMiningRate       = flag.Float64("base-mining-rate", 0, "base mining rate to use instead of the `adoptionMilestoneSwitch` config")
+		startingMilestone    = flag.Uint64("starting-milestone", 1, "adoption milestone to start with, if `base-mining-rate` is not set")
+		resumeMiningSessions = flag.Bool("resume-mining-sessions", false, "users that are mining when the snapshot was taken never stop mining")
+	)
+	flag.Parse()
+
+	snapshot, err := loadSnapshot(*input, *format)
+	log.Panic(errors.Wrapf(err, "failed to load snapshot from %v", *input)) //nolint:revive // That's intended.
+	arg := &miner.SimulationArg{
+		StartedAt:            latestBalanceUpdate(snapshot),
+		BaseMiningRate:       *baseMiningRate,
+		StartingMilestone:    *startingMilestone,
+		Iterations:           *iterations,
+		ResumeMiningSessions: *resumeMiningSessions,
+	}
+	if *startedAt != "" {
+		at, pErr := stdlibtime.Parse(stdlibtime.RFC3339, *startedAt)
+		log.Panic(errors.Wrapf(pErr, "invalid started-at %v", *startedAt))
+		arg.StartedAt = time.New(at)
+	}
+	log.Info(fmt.Sprintf("simulating %v iterations for %v users, starting at %v...", arg.Iterations, len(snapshot), arg.StartedAt.Format(stdlibtime.RFC3339)))
+	report := miner.Simulate(arg, snapshot)
+	log.Panic(errors.Wrap(writeReport(*output, report), "failed to write report"))
+	if len(report.Iterations) != 0 {
+		last := report.Iterations[len(report.Iterations)-1]
+		log.Info(fmt.Sprintf("simulation finished at %v: standard=%.2f, preStaking=%.2f, baseMiningRate=%v",
+			report.EndedAt.Format(stdlibtime.RFC3339), last.BalanceTotalStandard, last.BalanceTotalPreStaking, last.BaseMiningRate))
+	}
+}
+
+func loadSnapshot(path, format string) ([]*model.User, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open %v", path)
+	}
+	defer func() {
+		log.Error(errors.Wrapf(file.Close(), "failed to close %v", path))
+	}()
+	var rows []map[string]any
+	switch format {
+	case jsonFormat:
+		decoder := json.NewDecoder(bufio.NewReader(file))
+		decoder.UseNumber()
+		if err = decoder.Decode(&rows); err != nil {
+			return nil, errors.Wrapf(err, "failed to decode %v", path)
+		}
+	case clickhouseFormat:
+		if rows, err = readJSONEachRow(file); err != nil {
+			return nil, errors.Wrapf(err, "failed to read %v", path)
+		}
+	default:
+		return nil, errors.Errorf("unsupported format %v", format)
+	}
+
+	return deserializeUsers(rows)
+}
+
+func readJSONEachRow(reader io.Reader) ([]map[string]any, error) {
+	rows := make([]map[string]any, 0)
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024) //nolint:gomnd // A row is way smaller than that.
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		row := make(map[string]any)
+		decoder := json.NewDecoder(bytes.NewReader(line))
+		decoder.UseNumber()
+		if err := decoder.Decode(&row); err != nil {
+			return nil, errors.Wrapf(err, "failed to decode row %v", string(line))
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, errors.Wrap(scanner.Err(), "failed to scan rows")
+}
+
+// The history has a row per user per hour, so we keep only the latest one for each user.
+func deserializeUsers(rows []map[string]any) ([]*model.User, error) {
+	users := make([]*model.User, 0, len(rows))
+	latest := make(map[int64]int, len(rows))
+	for _, row := range rows {
+		fields := make(map[string]string, len(row))
+		for column, value := range row {
+			if field, ok := serializeField(column, value); ok {
+				fields[column] = field
+			}
+		}
+		usr := new(model.User)
+		if err := storage.DeserializeValue(usr, redis.NewMapStringStringResult(fields, nil).Scan); err != nil {
+			return nil, errors.Wrapf(err, "failed to deserialize %#v", fields)
+		}
+		if _, err := fmt.Sscan(fields["id"], &usr.ID); err != nil {
+			return nil, errors.Wrapf(err, "invalid id for %#v", fields)
+		}
+		if usr.BalanceLastUpdatedAt.IsNil() && fields["created_at"] != "" {
+			usr.BalanceLastUpdatedAt = new(time.Time)
+			if err := usr.BalanceLastUpdatedAt.UnmarshalText([]byte(fields["created_at"])); err != nil {
+				return nil, errors.Wrapf(err, "invalid created_at for %#v", fields)
+			}
+		}
+		if ix, found := latest[usr.ID]; found {
+			if !users[ix].BalanceLastUpdatedAt.IsNil() && !usr.BalanceLastUpdatedAt.IsNil() && usr.BalanceLastUpdatedAt.After(*users[ix].BalanceLastUpdatedAt.Time) {
+				users[ix] = usr
+			}
+
+			continue
+		}
+		latest[usr.ID] = len(users)
+		users = append(users, usr)
+	}
+
+	return users, nil
+}
+
+func serializeField(column string, value any) (string, bool) {
+	switch val := value.(type) {
+	case nil, []any, map[string]any:
+		return "", false
+	case bool:
+		return fmt.Sprint(val), true
+	case json.Number:
+		return val.String(), true
+	case string:
+		if strings.HasSuffix(column, "_at") {
+			return serializeTime(val)
+		}
+
+		return val, true
+	default:
+		return fmt.Sprint(val), true
+	}
+}
+
+// ClickHouse exports DateTime64 as `2006-01-02 15:04:05.999999999` and uses the epoch for the empty ones.
+func serializeTime(value string) (string, bool) {
+	for _, layout := range []string{stdlibtime.RFC3339Nano, stdlibtime.DateTime + ".999999999", stdlibtime.DateTime} {
+		if at, err := stdlibtime.ParseInLocation(layout, value, stdlibtime.UTC); err == nil {
+			if at.UnixNano() == 0 {
+				return "", false
+			}
+
+			return at.UTC().Format(stdlibtime.RFC3339Nano), true
+		}
+	}
+
+	return "", false
+}
+
+func latestBalanceUpdate(snapshot []*model.User) *time.Time {
+	latest := time.Now()
+	if len(snapshot) != 0 {
+		latest = new(time.Time)
+	}
+	for _, usr := range snapshot {
+		if !usr.BalanceLastUpdatedAt.IsNil() && (latest.IsNil() || usr.BalanceLastUpdatedAt.After(*latest.Time)) {
+			latest = usr.BalanceLastUpdatedAt
+		}
+	}
+	if latest.IsNil() {
+		return time.Now()
+	}
+
+	return latest
+}
+
+func writeReport(path string, report *miner.SimulationReport) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal %#v", report)
+	}
+	if path == "" {
+		_, err = os.Stdout.Write(data)
+
+		return errors.Wrap(err, "failed to write to stdout")
+	}
+
+	return errors.Wrapf(os.WriteFile(path, data, 0o600), "failed to write %v", path) //nolint:gomnd // .
+}
@@ -78,7 +78,7 @@ const docTemplate = `{
         },
         "/tokenomics-statistics/top-miners": {
             "get": {
-                "description": "Returns the paginated leaderboard with top miners.",
+                "description": "Returns the paginated leaderboard with top miners, by total balance, by total balance within a country, by active T1 referrals or by mining streak.",
                 "consumes": [
                     "application/json"
                 ],
@@ -103,6 +103,24 @@ const docTemplate = `{
                         "name": "keyword",
                         "in": "query"
                     },
+                    {
+                        "enum": [
+                            "balance",
+                            "country",
+                            "referrals",
+                            "miningStreak"
+                        ],
+                        "type": "string",
+                        "description": "what to rank the miners by. Default is ` + "`" + `balance` + "`" + `. It can't be used with ` + "`" + `keyword` + "`" + `.",
+                        "name": "dimension",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "the country to rank the miners of. Required if the dimension is ` + "`" + `country` + "`" + `.",
+                        "name": "country",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
                         "description": "max number of elements to return. Default is ` + "`" + `10` + "`" + `.",
@@ -354,6 +372,87 @@ const docTemplate = `{
                 }
             }
         },
+        "/tokenomics/{userId}/balance-stream": {
+            "get": {
+                "description": "Streams the balance and mining state updates of the user as Server-Sent Events, instead of polling the summaries.\nIt starts with a ` + "`" + `snapshot` + "`" + ` event, followed by ` + "`" + `balance` + "`" + ` events, interpolated from the current mining rate between the actual updates,\nand ` + "`" + `miningSession` + "`" + ` events when a mining session is started or extended. Every event's data is a ` + "`" + `tokenomics.BalanceStreamState` + "`" + `.\nOnly the actual updates have an ` + "`" + `id` + "`" + `, to resume from after reconnecting. The stream is closed after a while, so that the token is checked again.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "Tokenomics"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "the id of the last event received, to resume from it",
+                        "name": "Last-Event-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the user",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/tokenomics.BalanceStreamState"
+                        }
+                    },
+                    "400": {
+                        "description": "if validations fail",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "if not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "if not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "if not found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "if syntax fails",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/tokenomics/{userId}/balance-summary": {
             "get": {
                 "description": "Returns the balance related information.",
@@ -429,6 +528,87 @@ const docTemplate = `{
                 }
             }
         },
+        "/tokenomics/{userId}/mining-rate-explanation": {
+            "get": {
+                "description": "Returns what the current mining rate is made of and the projected balance in 24h, 7d and 30d, if nothing changes.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tokenomics"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the user",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/tokenomics.MiningRateExplanation"
+                        }
+                    },
+                    "400": {
+                        "description": "if validations fail",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "if not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "if not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "if not found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "if syntax fails",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "if request times out",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/tokenomics/{userId}/mining-summary": {
             "get": {
                 "description": "Returns the mining related information.",
@@ -591,9 +771,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/tokenomics/{userId}/ranking-summary": {
+        "/tokenomics/{userId}/rank-history": {
             "get": {
-                "description": "Returns the ranking related information.",
+                "description": "Returns the global rank history for the provided params, with how many places the user climbed or dropped between the entries.\nIf ` + "`" + `startDate` + "`" + ` is after ` + "`" + `endDate` + "`" + `, we go backwards in time: I.E. today, yesterday, etc.\nIf ` + "`" + `startDate` + "`" + ` is before ` + "`" + `endDate` + "`" + `, we go forwards in time: I.E. today, tomorrow, etc.",
                 "consumes": [
                     "application/json"
                 ],
@@ -618,13 +798,46 @@ const docTemplate = `{
                         "name": "userId",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "The start date in RFC3339 or ISO8601 formats. Default is ` + "`" + `now` + "`" + ` in UTC.",
+                        "name": "startDate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "The start date in RFC3339 or ISO8601 formats. Default is ` + "`" + `end of day, relative to startDate` + "`" + `.",
+                        "name": "endDate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "The user's timezone. I.E. ` + "`" + `+03:00` + "`" + `, ` + "`" + `-1:30` + "`" + `. Default is UTC.",
+                        "name": "tz",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "max number of elements to return. Default is ` + "`" + `24` + "`" + `.",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "number of elements to skip before starting to fetch data",
+                        "name": "offset",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/tokenomics.RankingSummary"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tokenomics.RankHistoryEntry"
+                            }
                         }
                     },
                     "400": {
@@ -665,66 +878,266 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "server.ErrorResponse": {
-            "type": "object",
-            "properties": {
-                "code": {
-                    "type": "string",
-                    "example": "SOMETHING_NOT_FOUND"
-                },
-                "data": {
-                    "type": "object",
-                    "additionalProperties": {}
-                },
-                "error": {
-                    "type": "string",
-                    "example": "something is missing"
-                }
-            }
-        },
-        "tokenomics.AdoptionSummary": {
-            "type": "object",
-            "properties": {
-                "milestones": {
-                    "type": "array",
-                    "items": {
-                        "type": "object",
-                        "properties": {
-                            "achievedAt": {
-                                "type": "string",
-                                "example": "2022-01-03T16:20:52.156534Z"
-                            },
-                            "baseMiningRate": {
-                                "type": "string",
-                                "example": "1,243.02"
-                            },
-                            "milestone": {
-                                "type": "integer",
-                                "example": 1
-                            },
-                            "totalActiveUsers": {
-                                "type": "integer",
-                                "example": 1
-                            }
-                        }
-                    }
-                },
-                "totalActiveUsers": {
-                    "type": "integer",
-                    "example": 11
-                }
-            }
         },
-        "tokenomics.BalanceHistoryBalanceDiff": {
-            "type": "object",
-            "properties": {
-                "amount": {
-                    "type": "string",
-                    "example": "1,243.02"
-                },
+        "/tokenomics/{userId}/ranking-summary": {
+            "get": {
+                "description": "Returns the ranking related information.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tokenomics"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the user",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/tokenomics.RankingSummary"
+                        }
+                    },
+                    "400": {
+                        "description": "if validations fail",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "if not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "if hidden by the user",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "if syntax fails",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "if request times out",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tokenomics/{userId}/referrals": {
+            "get": {
+                "description": "Returns the paginated T1 or T2 referrals of the user, the newest first, with how much each of them contributes to the user's balance.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tokenomics"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the user",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "t1",
+                            "t2"
+                        ],
+                        "type": "string",
+                        "description": "the tier of the referrals",
+                        "name": "tier",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "max number of elements to return. Default is ` + "`" + `10` + "`" + `.",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "number of elements to skip before starting to fetch data",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/tokenomics.Referrals"
+                        },
+                        "headers": {
+                            "X-Next-Offset": {
+                                "type": "integer",
+                                "description": "if this value is 0, pagination stops, if not, use it in the ` + "`" + `offset` + "`" + ` query param for the next call. "
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "if validations fail",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "if not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "if not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "if syntax fails",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "if request times out",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "server.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "SOMETHING_NOT_FOUND"
+                },
+                "data": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "error": {
+                    "type": "string",
+                    "example": "something is missing"
+                }
+            }
+        },
+        "tokenomics.Adoption-string": {
+            "type": "object",
+            "properties": {
+                "achievedAt": {
+                    "type": "string",
+                    "example": "2022-01-03T16:20:52.156534Z"
+                },
+                "baseMiningRate": {
+                    "type": "string",
+                    "example": "1,243.02"
+                },
+                "milestone": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "totalActiveUsers": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "tokenomics.AdoptionSummary": {
+            "type": "object",
+            "properties": {
+                "milestones": {
+                    "type": "array",
+                    "items": {
+                        "type": "object",
+                        "properties": {
+                            "achievedAt": {
+                                "type": "string",
+                                "example": "2022-01-03T16:20:52.156534Z"
+                            },
+                            "baseMiningRate": {
+                                "type": "string",
+                                "example": "1,243.02"
+                            },
+                            "milestone": {
+                                "type": "integer",
+                                "example": 1
+                            },
+                            "totalActiveUsers": {
+                                "type": "integer",
+                                "example": 1
+                            }
+                        }
+                    }
+                },
+                "totalActiveUsers": {
+                    "type": "integer",
+                    "example": 11
+                }
+            }
+        },
+        "tokenomics.BalanceHistoryBalanceDiff": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "1,243.02"
+                },
                 "bonus": {
                     "type": "number",
                     "example": 120
@@ -753,6 +1166,47 @@ const docTemplate = `{
                 }
             }
         },
+        "tokenomics.BalanceProjection": {
+            "type": "object",
+            "properties": {
+                "at": {
+                    "type": "string",
+                    "example": "2022-01-03T16:20:52.156534Z"
+                },
+                "balance": {
+                    "type": "string",
+                    "example": "1,243.02"
+                },
+                "horizon": {
+                    "type": "string",
+                    "example": "24h"
+                }
+            }
+        },
+        "tokenomics.BalanceStreamState": {
+            "type": "object",
+            "properties": {
+                "at": {
+                    "type": "string",
+                    "example": "2022-01-03T16:20:52.156534Z"
+                },
+                "balance": {
+                    "type": "string",
+                    "example": "1,243.02"
+                },
+                "interpolated": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "miningRate": {
+                    "$ref": "#/definitions/tokenomics.MiningRateBreakdown"
+                },
+                "miningSessionEndedAt": {
+                    "type": "string",
+                    "example": "2022-01-03T16:20:52.156534Z"
+                }
+            }
+        },
         "tokenomics.BalanceSummary": {
             "type": "object",
             "properties": {
@@ -797,10 +1251,18 @@ const docTemplate = `{
         "tokenomics.Miner": {
             "type": "object",
             "properties": {
+                "activeT1Referrals": {
+                    "type": "integer",
+                    "example": 11
+                },
                 "balance": {
                     "type": "string",
                     "example": "12345.6334"
                 },
+                "miningStreak": {
+                    "type": "integer",
+                    "example": 7
+                },
                 "profilePictureUrl": {
                     "type": "string",
                     "example": "https://somecdn.com/p1.jpg"
@@ -840,6 +1302,108 @@ const docTemplate = `{
                 }
             }
         },
+        "tokenomics.MiningRateBreakdown": {
+            "type": "object",
+            "properties": {
+                "activeT1Referrals": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "activeT2Referrals": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "adoptionMilestone": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "base": {
+                    "type": "string",
+                    "example": "16.00"
+                },
+                "extraBonus": {
+                    "type": "string",
+                    "example": "1.60"
+                },
+                "extraBonusEndsAt": {
+                    "type": "string",
+                    "example": "2022-01-03T16:20:52.156534Z"
+                },
+                "extraBonusPercentage": {
+                    "type": "number",
+                    "example": 10
+                },
+                "preStakingAllocation": {
+                    "type": "number",
+                    "example": 50
+                },
+                "preStakingBonus": {
+                    "type": "number",
+                    "example": 70
+                },
+                "preStakingMultiplier": {
+                    "type": "number",
+                    "example": 1.35
+                },
+                "slashing": {
+                    "type": "string",
+                    "example": "0.00"
+                },
+                "t0": {
+                    "type": "string",
+                    "example": "4.00"
+                },
+                "t0Active": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "t1": {
+                    "type": "string",
+                    "example": "20.00"
+                },
+                "t2": {
+                    "type": "string",
+                    "example": "5.00"
+                },
+                "total": {
+                    "type": "string",
+                    "example": "46.60"
+                },
+                "type": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/tokenomics.MiningRateType"
+                        }
+                    ],
+                    "example": "positive"
+                }
+            }
+        },
+        "tokenomics.MiningRateExplanation": {
+            "type": "object",
+            "properties": {
+                "breakdown": {
+                    "$ref": "#/definitions/tokenomics.MiningRateBreakdown"
+                },
+                "miningSessionEndedAt": {
+                    "type": "string",
+                    "example": "2022-01-03T16:20:52.156534Z"
+                },
+                "nextAdoptionMilestone": {
+                    "$ref": "#/definitions/tokenomics.Adoption-string"
+                },
+                "nextAdoptionMilestoneEarliestAt": {
+                    "type": "string",
+                    "example": "2022-01-03T16:20:52.156534Z"
+                },
+                "projections": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/tokenomics.BalanceProjection"
+                    }
+                }
+            }
+        },
         "tokenomics.MiningRateSummary-string": {
             "type": "object",
             "properties": {
@@ -968,6 +1532,30 @@ const docTemplate = `{
                 }
             }
         },
+        "tokenomics.RankHistoryEntry": {
+            "type": "object",
+            "properties": {
+                "change": {
+                    "description": "How many places the user climbed since the previous entry, negative if it dropped.",
+                    "type": "integer",
+                    "example": -3
+                },
+                "globalRank": {
+                    "type": "integer",
+                    "example": 12333
+                },
+                "time": {
+                    "type": "string",
+                    "example": "2022-01-03T16:20:52.156534Z"
+                },
+                "timeSeries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/tokenomics.RankHistoryEntry"
+                    }
+                }
+            }
+        },
         "tokenomics.RankingSummary": {
             "type": "object",
             "properties": {
@@ -977,6 +1565,50 @@ const docTemplate = `{
                 }
             }
         },
+        "tokenomics.Referral": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "contribution": {
+                    "type": "string",
+                    "example": "1,243.02"
+                },
+                "profilePictureUrl": {
+                    "type": "string",
+                    "example": "https://somecdn.com/p1.jpg"
+                },
+                "slashing": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "userId": {
+                    "type": "string",
+                    "example": "did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"
+                },
+                "username": {
+                    "type": "string",
+                    "example": "jdoe"
+                }
+            }
+        },
+        "tokenomics.Referrals": {
+            "type": "object",
+            "properties": {
+                "referrals": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/tokenomics.Referral"
+                    }
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 11
+                }
+            }
+        },
         "tokenomics.TotalCoinsSummary": {
             "type": "object",
             "properties": {
@@ -5,6 +5,8 @@ package main
 import (
 	stdlibtime "time"
 
+	"github.com/gin-gonic/gin"
+
 	"github.com/ice-blockchain/freezer/tokenomics"
 )
 
@@ -14,6 +16,9 @@ type (
 	GetMiningSummaryArg struct {
 		UserID string `uri:"userId" required:"true" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"`
 	}
+	GetMiningRateExplanationArg struct {
+		UserID string `uri:"userId" required:"true" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"`
+	}
 	GetPreStakingSummaryArg struct {
 		UserID string `uri:"userId" required:"true" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"`
 	}
@@ -31,11 +36,38 @@ type (
 		Limit  uint64 `form:"limit" maximum:"1000" example:"24"`
 		Offset uint64 `form:"offset" example:"0"`
 	}
+	GetReferralsArg struct {
+		UserID string                  `uri:"userId" required:"true" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"`
+		Tier   tokenomics.ReferralTier `form:"tier" required:"true" swaggertype:"string" enums:"t1,t2" example:"t1"`
+		// Default is 10.
+		Limit  uint64 `form:"limit" maximum:"1000" example:"10"`
+		Offset uint64 `form:"offset" example:"0"`
+	}
+	StreamBalanceArg struct {
+		UserID string `uri:"userId" required:"true" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"`
+		// The id of the last event received, to resume from it after reconnecting.
+		LastEventID string `header:"Last-Event-ID" example:"1697000000000-0"`
+	}
 	GetRankingSummaryArg struct {
 		UserID string `uri:"userId" allowForbiddenGet:"true" required:"true" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"`
 	}
+	GetRankHistoryArg struct {
+		// The start date in RFC3339 or ISO8601 formats. Default is `now` in UTC.
+		StartDate *stdlibtime.Time `form:"startDate" swaggertype:"string" example:"2022-01-03T16:20:52.156534Z"`
+		// The start date in RFC3339 or ISO8601 formats. Default is `end of day, relative to startDate`.
+		EndDate *stdlibtime.Time `form:"endDate" swaggertype:"string" example:"2022-01-03T16:20:52.156534Z"`
+		UserID  string           `uri:"userId" allowForbiddenGet:"true" required:"true" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"`
+		TZ      string           `form:"tz" example:"-03:00"`
+		// Default is 24.
+		Limit  uint64 `form:"limit" maximum:"1000" example:"24"`
+		Offset uint64 `form:"offset" example:"0"`
+	}
 	GetTopMinersArg struct {
 		Keyword string `form:"keyword" example:"jdoe"`
+		// Default is `balance`.
+		Dimension tokenomics.TopMinersDimension `form:"dimension" swaggertype:"string" enums:"balance,country,referrals,miningStreak" example:"balance"`
+		// Required if the dimension is `country`.
+		Country string `form:"country" example:"us"`
 		// Default is 10.
 		Limit  uint64 `form:"limit" maximum:"1000" example:"10"`
 		Offset uint64 `form:"offset" example:"0"`
@@ -71,4 +103,9 @@ type (
 		Host    string `yaml:"host"`
 		Version string `yaml:"version"`
 	}
+	// | balanceStreamWriter writes the balance stream events in the Server-Sent Events format.
+	balanceStreamWriter struct {
+		gin.ResponseWriter
+	}
+	responseWriterCtxValueKey struct{}
 )
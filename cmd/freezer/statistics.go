@@ -26,12 +26,14 @@ func (s *service) setupStatisticsRoutes(router *server.Router) {
 // GetTopMiners godoc
 //
 //	@Schemes
-//	@Description	Returns the paginated leaderboard with top miners.
+//	@Description	Returns the paginated leaderboard with top miners, by total balance, by total balance within a country, by active T1 referrals or by mining streak.
 //	@Tags			Statistics
 //	@Accept			json
 //	@Produce		json
 //	@Param			Authorization	header		string	true	"Insert your access token"	default(Bearer <Add access token here>)
 //	@Param			keyword			query		string	false	"a keyword to look for in the user's username or firstname/lastname"
+//	@Param			dimension		query		string	false	"what to rank the miners by. Default is `balance`. It can't be used with `keyword`."	Enums(balance,country,referrals,miningStreak)
+//	@Param			country			query		string	false	"the country to rank the miners of. Required if the dimension is `country`."
 //	@Param			limit			query		uint64	false	"max number of elements to return. Default is `10`."
 //	@Param			offset			query		uint64	false	"number of elements to skip before starting to fetch data"
 //	@Success		200				{array}		tokenomics.Miner
@@ -47,13 +49,29 @@ func (s *service) GetTopMiners( //nolint:gocritic // False negative.
 	req *server.Request[GetTopMinersArg, []*tokenomics.Miner],
 ) (*server.Response[[]*tokenomics.Miner], *server.Response[server.ErrorResponse]) {
 	const defaultLimit, maxLimit = 10, 1000
+	if req.Data.Dimension == "" {
+		req.Data.Dimension = tokenomics.BalanceTopMinersDimension
+	}
+	switch req.Data.Dimension {
+	case tokenomics.BalanceTopMinersDimension, tokenomics.ReferralsTopMinersDimension, tokenomics.MiningStreakTopMinersDimension:
+		req.Data.Country = ""
+	case tokenomics.CountryTopMinersDimension:
+		if req.Data.Country == "" {
+			return nil, server.UnprocessableEntity(errors.New("country is required for the country dimension"), invalidPropertiesErrorCode)
+		}
+	default:
+		return nil, server.UnprocessableEntity(errors.Errorf("invalid dimension:`%v`", req.Data.Dimension), invalidPropertiesErrorCode)
+	}
+	if req.Data.Keyword != "" && req.Data.Dimension != tokenomics.BalanceTopMinersDimension {
+		return nil, server.UnprocessableEntity(errors.New("keyword can't be used with a dimension"), invalidPropertiesErrorCode)
+	}
 	if req.Data.Limit == 0 {
 		req.Data.Limit = defaultLimit
 	}
 	if req.Data.Limit > maxLimit {
 		req.Data.Limit = maxLimit
 	}
-	resp, nextOffset, err := s.tokenomicsRepository.GetTopMiners(ctx, req.Data.Keyword, req.Data.Limit, req.Data.Offset)
+	resp, nextOffset, err := s.tokenomicsRepository.GetTopMiners(ctx, req.Data.Dimension, req.Data.Country, req.Data.Keyword, req.Data.Limit, req.Data.Offset) //nolint:lll // .
 	if err != nil {
 		return nil, server.Unexpected(errors.Wrapf(err, "failed to get top miners for userID:%v & req:%#v", req.AuthenticatedUser.UserID, req.Data))
 	}
@@ -3,14 +3,20 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"net/http"
+	"strconv"
 	"strings"
 	stdlibtime "time"
 
+	"github.com/gin-gonic/gin"
+	"github.com/goccy/go-json"
 	"github.com/pkg/errors"
 
 	"github.com/ice-blockchain/eskimo/users"
 	"github.com/ice-blockchain/freezer/tokenomics"
+	"github.com/ice-blockchain/wintr/log"
 	"github.com/ice-blockchain/wintr/server"
 	"github.com/ice-blockchain/wintr/time"
 )
@@ -19,10 +25,14 @@ func (s *service) setupTokenomicsRoutes(router *server.Router) {
 	router.
 		Group("/v1r").
 		GET("/tokenomics/:userId/mining-summary", server.RootHandler(s.GetMiningSummary)).
+		GET("/tokenomics/:userId/mining-rate-explanation", server.RootHandler(s.GetMiningRateExplanation)).
 		GET("/tokenomics/:userId/pre-staking-summary", server.RootHandler(s.GetPreStakingSummary)).
 		GET("/tokenomics/:userId/balance-summary", server.RootHandler(s.GetBalanceSummary)).
 		GET("/tokenomics/:userId/balance-history", server.RootHandler(s.GetBalanceHistory)).
-		GET("/tokenomics/:userId/ranking-summary", server.RootHandler(s.GetRankingSummary))
+		GET("/tokenomics/:userId/balance-stream", withResponseWriter(server.RootHandler(s.StreamBalance))).
+		GET("/tokenomics/:userId/ranking-summary", server.RootHandler(s.GetRankingSummary)).
+		GET("/tokenomics/:userId/rank-history", server.RootHandler(s.GetRankHistory)).
+		GET("/tokenomics/:userId/referrals", server.RootHandler(s.GetReferrals))
 }
 
 // GetMiningSummary godoc
@@ -60,6 +70,41 @@ func (s *service) GetMiningSummary( //nolint:gocritic // False negative.
 	return server.OK(mining), nil
 }
 
+// GetMiningRateExplanation godoc
+//
+//	@Schemes
+//	@Description	Returns what the current mining rate is made of and the projected balance in 24h, 7d and 30d, if nothing changes.
+//	@Tags			Tokenomics
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string	true	"Insert your access token"	default(Bearer <Add access token here>)
+//	@Param			userId			path		string	true	"ID of the user"
+//	@Success		200				{object}	tokenomics.MiningRateExplanation
+//	@Failure		400				{object}	server.ErrorResponse	"if validations fail"
+//	@Failure		401				{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403				{object}	server.ErrorResponse	"if not allowed"
+//	@Failure		404				{object}	server.ErrorResponse	"if not found"
+//	@Failure		422				{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500				{object}	server.ErrorResponse
+//	@Failure		504				{object}	server.ErrorResponse	"if request times out"
+//	@Router			/tokenomics/{userId}/mining-rate-explanation [GET].
+func (s *service) GetMiningRateExplanation( //nolint:gocritic // False negative.
+	ctx context.Context,
+	req *server.Request[GetMiningRateExplanationArg, tokenomics.MiningRateExplanation],
+) (*server.Response[tokenomics.MiningRateExplanation], *server.Response[server.ErrorResponse]) {
+	explanation, err := s.tokenomicsRepository.GetMiningRateExplanation(contextWithHashCode(ctx, req), req.Data.UserID)
+	if err != nil {
+		err = errors.Wrapf(err, "failed to get user's mining rate explanation for userID:%v", req.Data.UserID)
+		if errors.Is(err, tokenomics.ErrRelationNotFound) {
+			return nil, server.NotFound(err, userNotFoundErrorCode)
+		}
+
+		return nil, server.Unexpected(err)
+	}
+
+	return server.OK(explanation), nil
+}
+
 // GetPreStakingSummary godoc
 //
 //	@Schemes
@@ -189,6 +234,89 @@ func (s *service) GetBalanceHistory( //nolint:gocritic,funlen // False negative.
 	return server.OK(&hist), nil
 }
 
+// StreamBalance godoc
+//
+//	@Schemes
+//	@Description	Streams the balance and mining state updates of the user as Server-Sent Events, instead of polling the summaries.
+//	@Description	It starts with a `snapshot` event, followed by `balance` events, interpolated from the current mining rate between the actual updates,
+//	@Description	and `miningSession` events when a mining session is started or extended. Every event's data is a `tokenomics.BalanceStreamState`.
+//	@Description	Only the actual updates have an `id`, to resume from after reconnecting. The stream is closed after a while, so that the token is checked again.
+//	@Tags			Tokenomics
+//	@Accept			json
+//	@Produce		text/event-stream
+//	@Param			Authorization	header		string	true	"Insert your access token"	default(Bearer <Add access token here>)
+//	@Param			Last-Event-ID	header		string	false	"the id of the last event received, to resume from it"
+//	@Param			userId			path		string	true	"ID of the user"
+//	@Success		200				{object}	tokenomics.BalanceStreamState
+//	@Failure		400				{object}	server.ErrorResponse	"if validations fail"
+//	@Failure		401				{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403				{object}	server.ErrorResponse	"if not allowed"
+//	@Failure		404				{object}	server.ErrorResponse	"if not found"
+//	@Failure		422				{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500				{object}	server.ErrorResponse
+//	@Router			/tokenomics/{userId}/balance-stream [GET].
+func (s *service) StreamBalance( //nolint:gocritic // False negative.
+	ctx context.Context,
+	req *server.Request[StreamBalanceArg, any],
+) (*server.Response[any], *server.Response[server.ErrorResponse]) {
+	if req.Data.LastEventID != "" && !tokenomics.IsValidBalanceStreamEventID(req.Data.LastEventID) {
+		return nil, server.UnprocessableEntity(errors.Errorf("invalid Last-Event-ID:`%v`", req.Data.LastEventID), invalidPropertiesErrorCode)
+	}
+	responseWriter, ok := ctx.Value(responseWriterCtxValueKey{}).(gin.ResponseWriter)
+	if !ok {
+		return nil, server.Unexpected(errors.New("response writer not found in context"))
+	}
+	w := &balanceStreamWriter{ResponseWriter: responseWriter}
+	// The stream outlives the default endpoint timeout, it ends when the client goes away, failing the next write, or after `maxConnectionDuration`.
+	if err := s.tokenomicsRepository.StreamBalance(context.WithoutCancel(ctx), req.Data.UserID, req.Data.LastEventID, w.WriteEvent); err != nil {
+		err = errors.Wrapf(err, "failed to stream the balance of userID:%v", req.Data.UserID)
+		if !w.Written() {
+			if errors.Is(err, tokenomics.ErrRelationNotFound) {
+				return nil, server.NotFound(err, userNotFoundErrorCode)
+			}
+
+			return nil, server.Unexpected(err)
+		}
+		log.Error(err)
+	}
+
+	return server.OK[any](), nil
+}
+
+// withResponseWriter makes the response writer available to the handlers that stream their response instead of returning it.
+func withResponseWriter(handler gin.HandlerFunc) gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		ginCtx.Request = ginCtx.Request.WithContext(context.WithValue(ginCtx.Request.Context(), responseWriterCtxValueKey{}, ginCtx.Writer))
+		handler(ginCtx)
+	}
+}
+
+// WriteEvent sets the headers of the stream before its first event, so that the errors before it are still sent as JSON.
+func (w *balanceStreamWriter) WriteEvent(event *tokenomics.BalanceStreamEvent) error {
+	data, err := json.Marshal(event.Data)
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal %#v", event.Data)
+	}
+	if !w.Written() {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("X-Accel-Buffering", "no")
+	}
+	var buf bytes.Buffer
+	if event.ID != "" {
+		buf.WriteString("id: " + event.ID + "\n")
+	}
+	buf.WriteString("event: " + string(event.Type) + "\ndata: ")
+	buf.Write(data)
+	buf.WriteString("\n\n")
+	if _, err = w.Write(buf.Bytes()); err != nil {
+		return errors.Wrapf(err, "failed to write the %v event", event.Type)
+	}
+	w.Flush()
+
+	return nil
+}
+
 // GetRankingSummary godoc
 //
 //	@Schemes
@@ -222,3 +350,116 @@ func (s *service) GetRankingSummary( //nolint:gocritic // False negative.
 
 	return server.OK(ranking), nil
 }
+
+// GetRankHistory godoc
+//
+//	@Schemes
+//	@Description	Returns the global rank history for the provided params, with how many places the user climbed or dropped between the entries.
+//	@Description	If `startDate` is after `endDate`, we go backwards in time: I.E. today, yesterday, etc.
+//	@Description	If `startDate` is before `endDate`, we go forwards in time: I.E. today, tomorrow, etc.
+//	@Tags			Tokenomics
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string	true	"Insert your access token"	default(Bearer <Add access token here>)
+//	@Param			userId			path		string	true	"ID of the user"
+//	@Param			startDate		query		string	false	"The start date in RFC3339 or ISO8601 formats. Default is `now` in UTC."
+//	@Param			endDate			query		string	false	"The start date in RFC3339 or ISO8601 formats. Default is `end of day, relative to startDate`."
+//	@Param			tz				query		string	false	"The user's timezone. I.E. `+03:00`, `-1:30`. Default is UTC."
+//	@Param			limit			query		uint64	false	"max number of elements to return. Default is `24`."
+//	@Param			offset			query		uint64	false	"number of elements to skip before starting to fetch data"
+//	@Success		200				{array}		tokenomics.RankHistoryEntry
+//	@Failure		400				{object}	server.ErrorResponse	"if validations fail"
+//	@Failure		401				{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403				{object}	server.ErrorResponse	"if hidden by the user"
+//	@Failure		422				{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500				{object}	server.ErrorResponse
+//	@Failure		504				{object}	server.ErrorResponse	"if request times out"
+//	@Router			/tokenomics/{userId}/rank-history [GET].
+func (s *service) GetRankHistory( //nolint:gocritic,funlen // False negative.
+	ctx context.Context,
+	req *server.Request[GetRankHistoryArg, []*tokenomics.RankHistoryEntry],
+) (*server.Response[[]*tokenomics.RankHistoryEntry], *server.Response[server.ErrorResponse]) {
+	const defaultLimit, maxLimit = 24, 1000
+	if req.Data.Limit > maxLimit {
+		req.Data.Limit = maxLimit
+	}
+	if req.Data.Limit == 0 {
+		req.Data.Limit = defaultLimit
+	}
+	var startDate, endDate *time.Time
+	if req.Data.StartDate == nil {
+		startDate = time.Now()
+	} else {
+		startDate = time.New(*req.Data.StartDate)
+	}
+	if req.Data.EndDate == nil {
+		endDate = time.New(startDate.Add(-1 * users.NanosSinceMidnight(startDate)))
+	} else {
+		endDate = time.New(*req.Data.EndDate)
+	}
+	if req.Data.TZ == "" {
+		req.Data.TZ = "+00:00"
+	}
+	utcOffset, err := stdlibtime.ParseDuration(strings.Replace(req.Data.TZ+"m", ":", "h", 1))
+	if err != nil {
+		return nil, server.UnprocessableEntity(errors.Wrapf(err, "invalid timezone:`%v`", req.Data.TZ), invalidPropertiesErrorCode)
+	}
+	hist, err := s.tokenomicsRepository.GetRankHistory(contextWithHashCode(ctx, req), req.Data.UserID, startDate, endDate, utcOffset, req.Data.Limit, req.Data.Offset) //nolint:lll // .
+	if err != nil {
+		err = errors.Wrapf(err, "failed to get user's rank history for userID:%v, data:%#v", req.Data.UserID, req.Data)
+		if errors.Is(err, tokenomics.ErrGlobalRankHidden) {
+			return nil, server.ForbiddenWithCode(err, globalRankHiddenErrorCode)
+		}
+
+		return nil, server.Unexpected(err)
+	}
+
+	return server.OK(&hist), nil
+}
+
+// GetReferrals godoc
+//
+//	@Schemes
+//	@Description	Returns the paginated T1 or T2 referrals of the user, the newest first, with how much each of them contributes to the user's balance.
+//	@Tags			Tokenomics
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string	true	"Insert your access token"	default(Bearer <Add access token here>)
+//	@Param			userId			path		string	true	"ID of the user"
+//	@Param			tier			query		string	true	"the tier of the referrals"	Enums(t1,t2)
+//	@Param			limit			query		uint64	false	"max number of elements to return. Default is `10`."
+//	@Param			offset			query		uint64	false	"number of elements to skip before starting to fetch data"
+//	@Success		200				{object}	tokenomics.Referrals
+//	@Failure		400				{object}	server.ErrorResponse	"if validations fail"
+//	@Failure		401				{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403				{object}	server.ErrorResponse	"if not allowed"
+//	@Failure		422				{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500				{object}	server.ErrorResponse
+//	@Failure		504				{object}	server.ErrorResponse	"if request times out"
+//	@Header			200				{integer}	X-Next-Offset			"if this value is 0, pagination stops, if not, use it in the `offset` query param for the next call. "
+//	@Router			/tokenomics/{userId}/referrals [GET].
+func (s *service) GetReferrals( //nolint:gocritic // False negative.
+	ctx context.Context,
+	req *server.Request[GetReferralsArg, tokenomics.Referrals],
+) (*server.Response[tokenomics.Referrals], *server.Response[server.ErrorResponse]) {
+	const defaultLimit, maxLimit = 10, 1000
+	if req.Data.Tier != tokenomics.T1ReferralTier && req.Data.Tier != tokenomics.T2ReferralTier {
+		return nil, server.UnprocessableEntity(errors.Errorf("invalid tier:`%v`", req.Data.Tier), invalidPropertiesErrorCode)
+	}
+	if req.Data.Limit == 0 {
+		req.Data.Limit = defaultLimit
+	}
+	if req.Data.Limit > maxLimit {
+		req.Data.Limit = maxLimit
+	}
+	referrals, nextOffset, err := s.tokenomicsRepository.GetReferrals(ctx, req.Data.UserID, req.Data.Tier, req.Data.Limit, req.Data.Offset)
+	if err != nil {
+		return nil, server.Unexpected(errors.Wrapf(err, "failed to get user's referrals for userID:%v, data:%#v", req.Data.UserID, req.Data))
+	}
+
+	return &server.Response[tokenomics.Referrals]{
+		Code:    http.StatusOK,
+		Data:    referrals,
+		Headers: map[string]string{"X-Next-Offset": strconv.FormatUint(nextOffset, 10)},
+	}, nil
+}
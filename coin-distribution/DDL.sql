@@ -14,6 +14,7 @@ BEGIN
     END IF;
 END
 $$;
+ALTER TYPE pending_coin_distributions_status ADD VALUE IF NOT EXISTS 'REVERIFY';
 
 
 CREATE TABLE IF NOT EXISTS pending_coin_distributions  (
@@ -28,9 +29,102 @@ CREATE TABLE IF NOT EXISTS pending_coin_distributions  (
                     PRIMARY KEY(day, user_id))
                     WITH (FILLFACTOR = 70);
 
+ALTER TABLE pending_coin_distributions ADD COLUMN IF NOT EXISTS eth_nonce bigint;
+ALTER TABLE pending_coin_distributions ADD COLUMN IF NOT EXISTS eth_tx_gas_price uint256;
+ALTER TABLE pending_coin_distributions ADD COLUMN IF NOT EXISTS eth_tx_gas_tip_cap uint256;
+ALTER TABLE pending_coin_distributions ADD COLUMN IF NOT EXISTS eth_tx_sent_at timestamp;
+ALTER TABLE pending_coin_distributions ADD COLUMN IF NOT EXISTS eth_gas_estimate bigint;
+ALTER TABLE pending_coin_distributions ADD COLUMN IF NOT EXISTS eth_batch_id text;
+ALTER TABLE pending_coin_distributions ADD COLUMN IF NOT EXISTS eth_replaced_txs text[];
+ALTER TABLE pending_coin_distributions ADD COLUMN IF NOT EXISTS target text NOT NULL DEFAULT 'ethereum';
+ALTER TABLE pending_coin_distributions ADD COLUMN IF NOT EXISTS eth_mined_tx text;
+ALTER TABLE pending_coin_distributions ADD COLUMN IF NOT EXISTS eth_mined_block_number bigint;
+ALTER TABLE pending_coin_distributions ADD COLUMN IF NOT EXISTS eth_mined_block_hash text;
+ALTER TABLE pending_coin_distributions ADD COLUMN IF NOT EXISTS eth_rejected_at timestamp;
+ALTER TABLE pending_coin_distributions ADD COLUMN IF NOT EXISTS eth_error text;
+ALTER TABLE pending_coin_distributions ADD COLUMN IF NOT EXISTS eth_fencing_token bigint;
+
 CREATE INDEX IF NOT EXISTS pending_coin_distributions_worker_number_ix ON pending_coin_distributions (eth_status, (internal_id % 10), created_at ASC);
 CREATE INDEX IF NOT EXISTS pending_coin_distributions_eth_status_tx_ix ON pending_coin_distributions (eth_status, eth_tx);
 CREATE INDEX IF NOT EXISTS pending_coin_distributions_eth_status_ix ON pending_coin_distributions (eth_status);
+CREATE INDEX IF NOT EXISTS pending_coin_distributions_target_ix ON pending_coin_distributions (target, eth_status, created_at ASC);
+
+CREATE TABLE IF NOT EXISTS settled_coin_distributions  (
+                    settled_at                timestamp NOT NULL,
+                    created_at                timestamp NOT NULL,
+                    internal_id               bigint    NOT NULL,
+                    day                       date      NOT NULL,
+                    eth_block_number          bigint    NOT NULL,
+                    eth_gas_used              bigint    NOT NULL,
+                    iceflakes                 uint256,
+                    eth_effective_gas_price   uint256,
+                    user_id                   text      NOT NULL,
+                    eth_address               text      NOT NULL,
+                    eth_tx                    text      NOT NULL,
+                    batch_id                  text      NOT NULL,
+                    PRIMARY KEY(day, user_id));
+
+ALTER TABLE settled_coin_distributions ADD COLUMN IF NOT EXISTS target text NOT NULL DEFAULT 'ethereum';
+ALTER TABLE settled_coin_distributions ADD COLUMN IF NOT EXISTS eth_block_hash text NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS settled_coin_distributions_user_id_ix ON settled_coin_distributions (user_id, settled_at DESC);
+CREATE INDEX IF NOT EXISTS settled_coin_distributions_eth_address_ix ON settled_coin_distributions (lower(eth_address), settled_at DESC);
+CREATE INDEX IF NOT EXISTS settled_coin_distributions_eth_tx_ix ON settled_coin_distributions (eth_tx);
+
+CREATE TABLE IF NOT EXISTS failed_coin_distributions  (
+                    failed_at                 timestamp NOT NULL,
+                    created_at                timestamp NOT NULL,
+                    internal_id               bigint    NOT NULL,
+                    day                       date      NOT NULL,
+                    iceflakes                 uint256,
+                    user_id                   text      NOT NULL,
+                    eth_address               text      NOT NULL,
+                    eth_tx                    text      NOT NULL,
+                    eth_replaced_txs          text[],
+                    eth_error                 text      NOT NULL,
+                    batch_id                  text      NOT NULL,
+                    target                    text      NOT NULL,
+                    operator_user_id          text      NOT NULL,
+                    PRIMARY KEY(day, user_id));
+
+CREATE TABLE IF NOT EXISTS rejected_coin_distributions_audit  (
+                    created_at                timestamp NOT NULL,
+                    records                   bigint    NOT NULL,
+                    iceflakes                 uint256,
+                    operator_user_id          text      NOT NULL,
+                    decision                  text      NOT NULL,
+                    target                    text      NOT NULL,
+                    batch_id                  text      NOT NULL,
+                    eth_tx                    text      NOT NULL,
+                    eth_error                 text      NOT NULL,
+                    verification              text      NOT NULL,
+                    user_ids                  text[]    NOT NULL);
+
+CREATE INDEX IF NOT EXISTS rejected_coin_distributions_audit_created_at_ix ON rejected_coin_distributions_audit (created_at DESC);
+
+CREATE TABLE IF NOT EXISTS coin_distribution_merkle_trees  (
+                    created_at                timestamp NOT NULL,
+                    published_at              timestamp,
+                    cycle                     bigint    NOT NULL GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+                    leaves                    bigint    NOT NULL,
+                    iceflakes                 uint256,
+                    batch_id                  text      NOT NULL UNIQUE,
+                    target                    text      NOT NULL,
+                    root                      text      NOT NULL,
+                    contract_address          text      NOT NULL,
+                    eth_tx                    text);
+
+CREATE TABLE IF NOT EXISTS coin_distribution_merkle_proofs  (
+                    cycle                     bigint    NOT NULL REFERENCES coin_distribution_merkle_trees(cycle) ON DELETE CASCADE,
+                    leaf_index                bigint    NOT NULL,
+                    iceflakes                 uint256,
+                    eth_address               text      NOT NULL,
+                    leaf                      text      NOT NULL,
+                    user_ids                  text[]    NOT NULL,
+                    proof                     text[]    NOT NULL,
+                    PRIMARY KEY(cycle, eth_address));
+
+CREATE INDEX IF NOT EXISTS coin_distribution_merkle_proofs_user_ids_ix ON coin_distribution_merkle_proofs USING GIN (user_ids);
 
 CREATE TABLE IF NOT EXISTS global (
                     key       text NOT NULL primary key,
@@ -49,11 +143,73 @@ INSERT INTO global (key,value)
                    ('coin_collector_denied_countries',''),
                    ('coin_distributer_gas_limit_units','30000000'),
                    ('coin_distributer_gas_price_override','3000000000'),
+                   ('coin_distributer_dynamic_fees_enabled','true'),
+                   ('coin_distributer_max_fee_per_gas_cap','50000000000'),
+                   ('coin_distributer_max_priority_fee_per_gas_cap','5000000000'),
+                   ('coin_distributer_block_gas_limit_margin_percent','20'),
+                   ('coin_distributer_max_in_flight_transactions','5'),
+                   ('coin_distributer_tx_replacement_timeout_minutes','15'),
+                   ('coin_distributer_confirmation_blocks','12'),
+                   ('coin_distributer_leader_lease','{"holder": "", "token": 0, "expiresAt": "2023-01-01T00:00:00Z"}'),
+                   ('coin_distributer_min_token_balance_percent','100'),
+                   ('coin_distributer_min_gas_balance_gwei','50000000'),
                    ('coin_distributer_msg_sent_online_date', '2023-01-01T00:00:00Z'),
                    ('coin_distributer_msg_sent_offline_date', '2023-01-01T00:00:00Z'),
-                   ('coin_distributer_msg_sent_finished_date', '2023-01-01T00:00:00Z')
+                   ('coin_distributer_msg_sent_finished_date', '2023-01-01T00:00:00Z'),
+                   ('coin_distributer_msg_sent_low_balance_date', '2023-01-01T00:00:00Z'),
+                   ('coin_distributer_reconciliation_next_block','0'),
+                   ('coin_distributer_reconciliation_start_date','2024-01-01T00:00:00Z'),
+                   ('coin_distributer_reconciliation_start_block','0'),
+                   ('coin_distributer_msg_sent_reconciliation_date', '2023-01-01T00:00:00Z'),
+                   ('coin_distributer_cycle_target','ethereum'),
+                   ('coin_distributer_max_users_per_eth_address','3'),
+                   ('coin_distributer_review_max_cycle_ice','0'),
+                   ('coin_distributer_review_max_user_ice','0'),
+                   ('coin_distributer_review_max_cycle_increase_percent','0'),
+                   ('coin_distributer_review_quorum','1')
          ON CONFLICT(key) DO NOTHING;
 
+CREATE TABLE IF NOT EXISTS coin_distribution_user_targets (
+                    user_id                   text      NOT NULL primary key,
+                    target                    text      NOT NULL);
+
+create or replace function coin_distribution_target(user_id text)
+    returns text
+language sql
+stable
+    as $$
+    select coalesce((select t.target from coin_distribution_user_targets t where t.user_id = coin_distribution_target.user_id),
+                    nullif((select g.value from global g where g.key = 'coin_distributer_cycle_target'), ''),
+                    'ethereum');
+$$;
+
+create or replace function init_coin_distribution_target_globals(target text)
+    returns void
+language sql
+    as $$
+    INSERT INTO global (key,value)
+         SELECT key || '_' || init_coin_distribution_target_globals.target, value
+         FROM (VALUES ('coin_distributer_enabled','false'),
+                      ('coin_distributer_forced_execution','false'),
+                      ('coin_distributer_gas_limit_units','30000000'),
+                      ('coin_distributer_gas_price_override','3000000000'),
+                      ('coin_distributer_dynamic_fees_enabled','true'),
+                      ('coin_distributer_max_fee_per_gas_cap','50000000000'),
+                      ('coin_distributer_max_priority_fee_per_gas_cap','5000000000'),
+                      ('coin_distributer_block_gas_limit_margin_percent','20'),
+                      ('coin_distributer_max_in_flight_transactions','5'),
+                      ('coin_distributer_tx_replacement_timeout_minutes','15'),
+                      ('coin_distributer_confirmation_blocks','12'),
+                      ('coin_distributer_leader_lease','{"holder": "", "token": 0, "expiresAt": "2023-01-01T00:00:00Z"}'),
+                      ('coin_distributer_min_token_balance_percent','100'),
+                      ('coin_distributer_min_gas_balance_gwei','50000000'),
+                      ('coin_distributer_msg_sent_online_date', '2023-01-01T00:00:00Z'),
+                      ('coin_distributer_msg_sent_offline_date', '2023-01-01T00:00:00Z'),
+                      ('coin_distributer_msg_sent_finished_date', '2023-01-01T00:00:00Z'),
+                      ('coin_distributer_msg_sent_low_balance_date', '2023-01-01T00:00:00Z')) AS defaults(key, value)
+    ON CONFLICT(key) DO NOTHING;
+$$;
+
 CREATE TABLE IF NOT EXISTS coin_distributions_by_earner (
                     created_at                timestamp NOT NULL,
                     internal_id               bigint    NOT NULL,
@@ -67,6 +223,8 @@ CREATE TABLE IF NOT EXISTS coin_distributions_by_earner (
                     PRIMARY KEY(day, user_id, earner_user_id))
                     WITH (FILLFACTOR = 70);
 
+ALTER TABLE coin_distributions_by_earner ADD COLUMN IF NOT EXISTS country text NOT NULL DEFAULT '';
+
 CREATE TABLE IF NOT EXISTS coin_distributions_pending_review  (
                     created_at                timestamp ,
                     internal_id               bigint    ,
@@ -79,6 +237,9 @@ CREATE TABLE IF NOT EXISTS coin_distributions_pending_review  (
                     eth_address               text      NOT NULL,
                     PRIMARY KEY(day, user_id));
 
+ALTER TABLE coin_distributions_pending_review ADD COLUMN IF NOT EXISTS country text NOT NULL DEFAULT '';
+ALTER TABLE coin_distributions_pending_review ADD COLUMN IF NOT EXISTS held_ice bigint NOT NULL DEFAULT 0;
+
 CREATE INDEX IF NOT EXISTS coin_distributions_pending_review_internal_id_ix ON coin_distributions_pending_review (internal_id NULLS FIRST);
 CREATE INDEX IF NOT EXISTS coin_distributions_pending_review_created_at_ix ON coin_distributions_pending_review (created_at);
 CREATE INDEX IF NOT EXISTS coin_distributions_pending_review_ice_ix ON coin_distributions_pending_review (ice);
@@ -107,6 +268,80 @@ CREATE TABLE IF NOT EXISTS reviewed_coin_distributions  (
                     decision                  text      NOT NULL,
                     PRIMARY KEY(user_id, day, review_day));
 
+ALTER TABLE reviewed_coin_distributions ADD COLUMN IF NOT EXISTS screening_reason text;
+ALTER TABLE reviewed_coin_distributions ADD COLUMN IF NOT EXISTS country text NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS reviewed_coin_distributions_review_day_ix ON reviewed_coin_distributions (review_day, decision);
+
+CREATE TABLE IF NOT EXISTS coin_distribution_review_votes  (
+                    created_at                timestamp NOT NULL,
+                    closed_at                 timestamp,
+                    snapshot                  text      NOT NULL,
+                    reviewer_user_id          text      NOT NULL,
+                    decision                  text      NOT NULL,
+                    filter                    text      NOT NULL);
+
+CREATE UNIQUE INDEX IF NOT EXISTS coin_distribution_review_votes_open_ix ON coin_distribution_review_votes (snapshot, reviewer_user_id) WHERE closed_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS coin_distribution_holds  (
+                    created_at                timestamp NOT NULL,
+                    released_at               timestamp,
+                    user_id                   text      NOT NULL,
+                    reviewer_user_id          text      NOT NULL,
+                    released_by_user_id       text      NOT NULL DEFAULT '',
+                    reason                    text      NOT NULL DEFAULT '',
+                    until_released            boolean   NOT NULL);
+
+CREATE UNIQUE INDEX IF NOT EXISTS coin_distribution_holds_open_ix ON coin_distribution_holds (user_id) WHERE released_at IS NULL;
+CREATE INDEX IF NOT EXISTS coin_distribution_holds_created_at_ix ON coin_distribution_holds (created_at DESC, user_id);
+
+CREATE TABLE IF NOT EXISTS coin_distributions_on_hold  (
+                    held_at                   timestamp NOT NULL,
+                    created_at                timestamp NOT NULL,
+                    internal_id               bigint    NOT NULL,
+                    ice                       bigint    NOT NULL,
+                    day                       date      NOT NULL,
+                    username                  text      NOT NULL,
+                    referred_by_username      text      NOT NULL,
+                    user_id                   text      NOT NULL,
+                    eth_address               text      NOT NULL,
+                    country                   text      NOT NULL,
+                    PRIMARY KEY(day, user_id));
+
+CREATE TABLE IF NOT EXISTS coin_distribution_denylisted_eth_addresses  (
+                    created_at                timestamp NOT NULL DEFAULT current_timestamp,
+                    eth_address               text      NOT NULL primary key CHECK (eth_address = lower(eth_address)),
+                    reason                    text      NOT NULL DEFAULT '');
+
+CREATE TABLE IF NOT EXISTS coin_distribution_screened_addresses  (
+                    screened_at               timestamp NOT NULL,
+                    eth_address               text      NOT NULL primary key,
+                    contract                  boolean,
+                    error                     text      NOT NULL DEFAULT '',
+                    attempts                  bigint    NOT NULL DEFAULT 0);
+
+CREATE TABLE IF NOT EXISTS coin_distribution_transfers  (
+                    block_number              bigint    NOT NULL,
+                    log_index                 bigint    NOT NULL,
+                    tx_hash                   text      NOT NULL,
+                    eth_address               text      NOT NULL,
+                    iceflakes                 uint256,
+                    PRIMARY KEY(tx_hash, log_index));
+
+CREATE INDEX IF NOT EXISTS coin_distribution_transfers_eth_address_ix ON coin_distribution_transfers (eth_address);
+
+CREATE TABLE IF NOT EXISTS coin_distribution_reconciliations  (
+                    reconciled_at             timestamp NOT NULL,
+                    approved_count            bigint    NOT NULL,
+                    transfers_count           bigint    NOT NULL,
+                    approved_iceflakes        uint256,
+                    transferred_iceflakes     uint256,
+                    eth_address               text      NOT NULL,
+                    status                    text      NOT NULL,
+                    PRIMARY KEY(reconciled_at, eth_address));
+
+CREATE INDEX IF NOT EXISTS coin_distribution_reconciliations_eth_address_ix ON coin_distribution_reconciliations (eth_address, reconciled_at);
+
 create or replace function approve_coin_distributions(reviewer_user_id text, process_immediately boolean, nested boolean)
     returns RECORD
 language plpgsql
@@ -115,15 +350,16 @@ declare
          now timestamp := current_timestamp;
          ret RECORD;
 BEGIN
-    insert into pending_coin_distributions(created_at, internal_id, day, iceflakes, user_id, eth_address)
-    select created_at, internal_id, day, iceflakes, user_id, eth_address
+    insert into pending_coin_distributions(created_at, internal_id, day, iceflakes, user_id, eth_address, target)
+    select created_at, internal_id, day, iceflakes, user_id, eth_address, coin_distribution_target(user_id)
     from coin_distributions_pending_review;
 
-    insert into reviewed_coin_distributions(reviewed_at, created_at, internal_id, ice, day, review_day, iceflakes, username, referred_by_username, user_id, eth_address, reviewer_user_id, decision)
-    select now, created_at, internal_id, ice, day, now::date, iceflakes, username, referred_by_username, user_id, eth_address, reviewer_user_id, (case when process_immediately is true then 'approve-and-process-immediately' else 'approve' end) AS reason
+    insert into reviewed_coin_distributions(reviewed_at, created_at, internal_id, ice, day, review_day, iceflakes, username, referred_by_username, user_id, eth_address, country, reviewer_user_id, decision)
+    select now, created_at, internal_id, ice, day, now::date, iceflakes, username, referred_by_username, user_id, eth_address, country, reviewer_user_id, (case when process_immediately is true then 'approve-and-process-immediately' else 'approve' end) AS reason
     from coin_distributions_pending_review;
 
     IF process_immediately is true THEN
+        UPDATE global SET value = 'true' WHERE starts_with(key, 'coin_distributer_forced_execution_') OR starts_with(key, 'coin_distributer_enabled_');
         INSERT INTO global (key,value)
                     VALUES ('coin_distributer_enabled','true'),
                            ('coin_distributer_forced_execution','true')
@@ -148,12 +384,13 @@ language plpgsql
 declare
          now timestamp := current_timestamp;
 BEGIN
-    insert into reviewed_coin_distributions(reviewed_at, created_at, internal_id, ice, day, review_day, iceflakes, username, referred_by_username, user_id, eth_address, reviewer_user_id, decision)
-    select now, created_at, internal_id, ice, day, now::date, iceflakes, username, referred_by_username, user_id, eth_address, reviewer_user_id, 'deny'
+    insert into reviewed_coin_distributions(reviewed_at, created_at, internal_id, ice, day, review_day, iceflakes, username, referred_by_username, user_id, eth_address, country, reviewer_user_id, decision)
+    select now, created_at, internal_id, ice, day, now::date, iceflakes, username, referred_by_username, user_id, eth_address, country, reviewer_user_id, 'deny'
     from coin_distributions_pending_review;
 
     delete from coin_distributions_pending_review where 1=1;
 
+    UPDATE global SET value = 'false' WHERE starts_with(key, 'coin_distributer_enabled_');
     INSERT INTO global (key,value)
                 VALUES ('coin_distributer_enabled','false'),
                        ('coin_collector_enabled','false')
@@ -172,11 +409,12 @@ declare
          zeros text := '0000000000000000';
          now timestamp := current_timestamp;
          reward_pool_internal_id bigint := 999999999;
+         max_users_per_eth_address bigint := coalesce((select value::bigint from global where key = 'coin_distributer_max_users_per_eth_address'), 0);
 BEGIN
     delete from coin_distributions_by_earner WHERE balance = 0;
 
-    insert into coin_distributions_pending_review(created_at, internal_id, ice, day, iceflakes, username, referred_by_username, user_id, eth_address)
-        SELECT created_at, internal_id, ice, day, (ice::text||zeros)::uint256 AS iceflakes, username, referred_by_username, user_id, eth_address
+    insert into coin_distributions_pending_review(created_at, internal_id, ice, day, iceflakes, username, referred_by_username, user_id, eth_address, country)
+        SELECT created_at, internal_id, ice, day, (ice::text||zeros)::uint256 AS iceflakes, username, referred_by_username, user_id, eth_address, country
         FROM (select
                    min (created_at) filter ( where user_id=earner_user_id or internal_id = reward_pool_internal_id)  AS created_at,
                    min (internal_id) filter ( where user_id=earner_user_id or internal_id = reward_pool_internal_id)  AS internal_id,
@@ -185,7 +423,8 @@ BEGIN
                    string_agg(username,'') AS username,
                    string_agg(referred_by_username,'') AS referred_by_username,
                    user_id,
-                   string_agg(eth_address,'') AS eth_address
+                   string_agg(eth_address,'') AS eth_address,
+                   string_agg(country,'') AS country
                 from coin_distributions_by_earner
                 group by day,user_id) AS X;
 
@@ -194,8 +433,73 @@ BEGIN
     WITH del as (
        DELETE FROM coin_distributions_pending_review WHERE internal_id IS NULL RETURNING *
     )
-    insert into reviewed_coin_distributions(reviewed_at, created_at, internal_id, ice, day, review_day, iceflakes, username, referred_by_username, user_id, eth_address, reviewer_user_id, decision)
-    select now, COALESCE(created_at,to_timestamp(0)), COALESCE(internal_id,0), ice, day, now::date, iceflakes, username, referred_by_username, user_id, eth_address, 'system', 'deny due to incomplete data'
+    insert into reviewed_coin_distributions(reviewed_at, created_at, internal_id, ice, day, review_day, iceflakes, username, referred_by_username, user_id, eth_address, country, reviewer_user_id, decision, screening_reason)
+    select now, COALESCE(created_at,to_timestamp(0)), COALESCE(internal_id,0), ice, day, now::date, iceflakes, username, referred_by_username, user_id, eth_address, country, 'system', 'deny due to incomplete data', 'incomplete-data'
+    from del;
+
+    UPDATE coin_distribution_holds
+    SET released_at = now,
+        released_by_user_id = 'system'
+    WHERE released_at IS NULL AND until_released IS FALSE;
+
+    WITH held AS (
+        DELETE FROM coin_distributions_pending_review p
+        USING coin_distribution_holds h
+        WHERE h.user_id = p.user_id AND h.released_at IS NULL
+        RETURNING p.*
+    )
+    insert into coin_distributions_on_hold(held_at, created_at, internal_id, ice, day, username, referred_by_username, user_id, eth_address, country)
+    select now, coalesce(created_at, now), internal_id, ice, day, username, referred_by_username, user_id, eth_address, country
+    from held
+    ON CONFLICT (day, user_id) DO UPDATE
+        SET ice = coin_distributions_on_hold.ice + EXCLUDED.ice;
+
+    WITH released AS (
+        DELETE FROM coin_distributions_on_hold o
+        WHERE NOT EXISTS (SELECT 1 FROM coin_distribution_holds h WHERE h.user_id = o.user_id AND h.released_at IS NULL)
+        RETURNING o.*
+    ), carried AS (
+        SELECT DISTINCT ON (user_id) created_at, internal_id, sum(ice) OVER (PARTITION BY user_id) AS ice, day, username, referred_by_username, user_id, eth_address, country
+        FROM released
+        ORDER BY user_id, day DESC
+    ), merged AS (
+        UPDATE coin_distributions_pending_review p
+        SET ice = p.ice + c.ice,
+            held_ice = p.held_ice + c.ice,
+            iceflakes = ((p.ice + c.ice)::text||zeros)::uint256
+        FROM carried c
+        WHERE p.user_id = c.user_id
+          AND p.day = (SELECT max(pp.day) FROM coin_distributions_pending_review pp WHERE pp.user_id = c.user_id)
+        RETURNING p.user_id
+    )
+    insert into coin_distributions_pending_review(created_at, internal_id, ice, held_ice, day, iceflakes, username, referred_by_username, user_id, eth_address, country)
+    select created_at, internal_id, ice, ice, day, (ice::text||zeros)::uint256, username, referred_by_username, user_id, eth_address, country
+    from carried
+    where user_id NOT IN (SELECT user_id FROM merged);
+
+    WITH shared AS (
+        SELECT lower(eth_address) AS eth_address
+        FROM coin_distributions_pending_review
+        GROUP BY 1
+        HAVING max_users_per_eth_address > 0 AND count(DISTINCT user_id) > max_users_per_eth_address
+    ), screened AS (
+        SELECT p.day,
+               p.user_id,
+               (CASE
+                    WHEN p.eth_address !~ '^0x[0-9a-fA-F]{40}$' THEN 'invalid-address'
+                    WHEN lower(p.eth_address) = '0x0000000000000000000000000000000000000000' THEN 'zero-address'
+                    WHEN EXISTS (SELECT 1 FROM coin_distribution_denylisted_eth_addresses d WHERE d.eth_address = lower(p.eth_address)) THEN 'denylisted-address'
+                    WHEN EXISTS (SELECT 1 FROM shared s WHERE s.eth_address = lower(p.eth_address)) THEN 'shared-address'
+               END) AS reason
+        FROM coin_distributions_pending_review p
+    ), del as (
+        DELETE FROM coin_distributions_pending_review p
+        USING screened s
+        WHERE p.day = s.day AND p.user_id = s.user_id AND s.reason IS NOT NULL
+        RETURNING p.*, s.reason
+    )
+    insert into reviewed_coin_distributions(reviewed_at, created_at, internal_id, ice, day, review_day, iceflakes, username, referred_by_username, user_id, eth_address, country, reviewer_user_id, decision, screening_reason)
+    select now, created_at, internal_id, ice, day, now::date, iceflakes, username, referred_by_username, user_id, eth_address, country, 'system', 'deny', reason
     from del;
 
     IF nested is false THEN
@@ -0,0 +1,112 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package coindistribution
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	stdlibtime "time"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/wintr/connectors/storage/v2"
+	"github.com/ice-blockchain/wintr/log"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+// EnsureBalances checks the balances before the next batch is fetched, so we pause (and alert) instead of
+// moving the records to PENDING and failing the airdrop with `insufficient funds`.
+func (proc *coinProcessor) EnsureBalances(ctx context.Context) error {
+	status, err := proc.CheckBalances(ctx)
+	if err != nil {
+		return err
+	}
+	proc.balances.Store(status)
+	if err = status.Err(); err != nil {
+		log.Warn(fmt.Sprintf("controller[%v]: distribution paused: %v", proc.Target.Name, err))
+		proc.maybeSendMessage(ctx, proc.targetKey(configKeyCoinDistributerMsgLowBalance), func(ctx context.Context) error {
+			return sendCoinDistributerBalanceTooLowSlackMessage(ctx, proc.Target.Name, status)
+		})
+	}
+
+	return err
+}
+
+// CheckBalances reads the ICE balance of the distribution contract and the gas balance of the sender and their thresholds:
+// `coin_distributer_min_token_balance_percent` of the ICE still to be distributed (NEW and PENDING records and the in-flight ones not mined yet)
+// and `coin_distributer_min_gas_balance_gwei`.
+func (proc *coinProcessor) CheckBalances(ctx context.Context) (*balanceStatus, error) {
+	var minTokenPercent, minGasGwei uint64
+	if err := databaseGetValue(ctx, proc.DB, proc.targetKey(configKeyCoinDistributerMinToken), &minTokenPercent); err != nil {
+		return nil, err
+	}
+	if err := databaseGetValue(ctx, proc.DB, proc.targetKey(configKeyCoinDistributerMinGas), &minGasGwei); err != nil {
+		return nil, err
+	}
+
+	status := &balanceStatus{
+		CheckedAt:     time.Now(),
+		Token:         big.NewInt(0),
+		RequiredToken: big.NewInt(0),
+		RequiredGas:   new(big.Int).Mul(new(big.Int).SetUint64(minGasGwei), big.NewInt(1_000_000_000)), //nolint:gomnd // Wei per gwei.
+	}
+	// The Merkle claims are paid by the claims contract, we only need gas to publish the roots.
+	if !proc.isMerkleMode() {
+		remaining, err := proc.RemainingIceflakes(ctx)
+		if err != nil {
+			return nil, err
+		}
+		status.RequiredToken.Div(remaining.Mul(remaining, new(big.Int).SetUint64(minTokenPercent)), big.NewInt(100)) //nolint:gomnd // Percent.
+		if status.Token, err = proc.Client.TokenBalance(ctx); err != nil {
+			return nil, errors.Wrapf(err, "failed to get the ICE balance of %v", proc.Target.Name)
+		}
+	}
+
+	gas, err := proc.Client.GasBalance(ctx)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get the gas balance of %v", proc.Target.Name)
+	}
+	status.Gas = gas
+
+	return status, nil
+}
+
+// RemainingIceflakes returns the ICE not debited from the contract yet: the one not sent to the chain and the one in flight, not mined yet.
+func (proc *coinProcessor) RemainingIceflakes(ctx context.Context) (*big.Int, error) {
+	const stmt = `SELECT coalesce(sum(iceflakes), 0)::text
+				  FROM pending_coin_distributions
+				  WHERE (eth_status IN ('NEW', 'PENDING') OR (eth_status IN ('ACCEPTED', 'REVERIFY') AND eth_mined_tx IS NULL))
+					AND target = $1`
+
+	reqCtx, cancel := context.WithTimeout(ctx, requestDeadline)
+	defer cancel()
+
+	sum, err := storage.ExecOne[string](reqCtx, proc.DB, stmt, proc.Target.Name)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to sum the remaining coin distributions of %v", proc.Target.Name)
+	}
+	remaining, ok := new(big.Int).SetString(*sum, 10) //nolint:gomnd // Decimal.
+	if !ok {
+		return nil, errors.Errorf("invalid sum of the remaining coin distributions of %v: %q", proc.Target.Name, *sum)
+	}
+
+	return remaining, nil
+}
+
+// Err fails with errBalanceTooLow if any of the balances is below its threshold.
+func (b *balanceStatus) Err() error {
+	if b.Token.Cmp(b.RequiredToken) < 0 {
+		return errors.Wrapf(errBalanceTooLow, "ICE balance %v is below %v", b.Token, b.RequiredToken)
+	}
+	if b.Gas.Cmp(b.RequiredGas) < 0 {
+		return errors.Wrapf(errBalanceTooLow, "gas balance %v is below %v", b.Gas, b.RequiredGas)
+	}
+
+	return nil
+}
+
+func (b *balanceStatus) String() string {
+	return fmt.Sprintf("ICE balance %v (required %v), gas balance %v (required %v), checked at %v",
+		b.Token, b.RequiredToken, b.Gas, b.RequiredGas, b.CheckedAt.Format(stdlibtime.RFC3339))
+}
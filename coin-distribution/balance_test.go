@@ -0,0 +1,35 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package coindistribution
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ice-blockchain/wintr/time"
+)
+
+func TestBalanceStatusErr(t *testing.T) {
+	t.Parallel()
+
+	status := &balanceStatus{
+		CheckedAt:     time.Now(),
+		Token:         big.NewInt(1_000),
+		RequiredToken: big.NewInt(1_000),
+		Gas:           big.NewInt(5),
+		RequiredGas:   big.NewInt(5),
+	}
+	require.NoError(t, status.Err())
+
+	status.Token = big.NewInt(999)
+	require.ErrorIs(t, status.Err(), errBalanceTooLow)
+	require.ErrorContains(t, status.Err(), "ICE balance 999 is below 1000")
+
+	status.Token = big.NewInt(2_000)
+	status.Gas = big.NewInt(4)
+	require.ErrorIs(t, status.Err(), errBalanceTooLow)
+	require.ErrorContains(t, status.Err(), "gas balance 4 is below 5")
+	require.Contains(t, status.String(), "gas balance 4 (required 5)")
+}
@@ -3,12 +3,16 @@
 package coindistribution
 
 import (
+	"context"
 	"fmt"
 	"math/big"
+	"slices"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
 
 	"github.com/ice-blockchain/wintr/log"
+	"github.com/ice-blockchain/wintr/time"
 )
 
 func (r *batchRecord) Address() common.Address {
@@ -56,15 +60,188 @@ func (b *batch) Users() []string {
 	return users
 }
 
+// UserCount returns the number of distinct users of the batch, each can have many records, I.E. one for each day.
+func (b *batch) UserCount() int {
+	users := make(map[string]struct{}, len(b.Records))
+	for idx := range b.Records {
+		users[b.Records[idx].UserID] = struct{}{}
+	}
+
+	return len(users)
+}
+
+// SplitUsers splits the records of the first `users` users of the batch from the records of the others.
+// The records of a user are never split, because they're updated all together, by user.
+func (b *batch) SplitUsers(users int) (kept, released []*batchRecord) {
+	keptUsers := make(map[string]struct{}, users)
+	kept = make([]*batchRecord, 0, len(b.Records))
+	for idx := range b.Records {
+		if _, ok := keptUsers[b.Records[idx].UserID]; ok || len(keptUsers) < users {
+			keptUsers[b.Records[idx].UserID] = struct{}{}
+			kept = append(kept, b.Records[idx])
+		} else {
+			released = append(released, b.Records[idx])
+		}
+	}
+
+	return kept, released
+}
+
 func (b *batch) SetStatus(status ethApiStatus) {
 	for idx := range b.Records {
 		b.Records[idx].EthStatus = status
 	}
 }
 
-func (b *batch) SetAccepted(txHash string) {
+func (b *batch) SetAccepted(tx *airdropTransaction, sentAt *time.Time) {
+	nonce := tx.Nonce
+	b.TX = tx.Hash
+	b.Nonce = &nonce
+	b.GasPrice = tx.GasPrice
+	b.GasTipCap = tx.GasTipCap
+	b.SentAt = sentAt
 	for idx := range b.Records {
 		b.Records[idx].EthStatus = ethApiStatusAccepted
-		b.Records[idx].EthTX = &txHash
+		b.Records[idx].EthTX = &tx.Hash
+	}
+}
+
+// GasLimit is the gas limit to use for the airdrop TX of the batch, 0 if it was never estimated.
+func (b *batch) GasLimit() uint64 {
+	return b.GasEstimate + b.GasEstimate*gasEstimateBuffer/100 //nolint:gomnd // Percent.
+}
+
+func (b *batch) GasGetter(gas gasGetter) gasGetter {
+	return &batchGasGetter{gasGetter: gas, Limit: b.GasLimit()}
+}
+
+func (g *batchGasGetter) GetGasOptions(ctx context.Context) (*gasOptions, error) {
+	options, err := g.gasGetter.GetGasOptions(ctx)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // .
+	}
+	if g.Limit != 0 {
+		options.Limit = g.Limit
+	}
+
+	return options, nil
+}
+
+// fitBatchSize scales the number of recipients so their gas fits under the allowed amount, as if every recipient costs the same.
+func fitBatchSize(recipients int, gas, allowed uint64) int {
+	if gas == 0 {
+		return recipients
+	}
+	size := int(uint64(recipients) * allowed / gas)
+	if size > maxBatchSize {
+		size = maxBatchSize
+	}
+
+	return size
+}
+
+func (tx *airdropTransaction) TipCapText() string {
+	if tx.GasTipCap == nil {
+		return "0"
+	}
+
+	return tx.GasTipCap.String()
+}
+
+// Hashes returns the current TX of the batch and all the ones it replaced, any of them can be mined.
+func (b *batch) Hashes() []string {
+	return append([]string{b.TX}, b.ReplacedTXs...)
+}
+
+// OtherHashes returns all the TXs of the batch, but the given one.
+func (b *batch) OtherHashes(hash string) []string {
+	hashes := make([]string, 0, len(b.ReplacedTXs)+1)
+	for _, other := range b.Hashes() {
+		if other != "" && other != hash && !slices.Contains(hashes, other) {
+			hashes = append(hashes, other)
+		}
 	}
+
+	return hashes
+}
+
+// Confirmation returns the TX of the batch that was mined, its receipt and whether its block is deep enough to be final.
+// Once mined, the batch sticks to that TX and block: if the receipt is gone or it's in another block now, the block was reorged out.
+func (b *batch) Confirmation(receipts map[string]*txReceipt, latestBlock, depth uint64) (string, *txReceipt, confirmationState) {
+	var (
+		hash    = b.TX
+		receipt *txReceipt
+	)
+	if b.Mined != nil {
+		hash, receipt = b.Mined.Hash, receipts[b.Mined.Hash]
+		if receipt == nil || receipt.BlockHash != b.Mined.BlockHash {
+			return hash, receipt, confirmationStateReorged
+		}
+	} else {
+		for _, candidate := range b.Hashes() {
+			if receipt = receipts[candidate]; receipt != nil {
+				hash = candidate
+
+				break
+			}
+		}
+		if receipt == nil {
+			return hash, nil, confirmationStatePending
+		}
+	}
+
+	// The block of the TX is its first confirmation.
+	if latestBlock+1 < receipt.BlockNumber+depth {
+		return hash, receipt, confirmationStateConfirming
+	}
+
+	return hash, receipt, confirmationStateConfirmed
+}
+
+// ReplacementGasPrice is the minimum gas price (or max fee per gas) nodes accept to replace the current TX of the batch.
+func (b *batch) ReplacementGasPrice() *big.Int {
+	return bumpGasPrice(b.GasPrice)
+}
+
+// ReplacementGasTipCap is the minimum max priority fee per gas nodes accept to replace the current TX of the batch.
+// For a legacy TX, the gas price is used as the priority fee.
+func (b *batch) ReplacementGasTipCap() *big.Int {
+	if b.GasTipCap == nil {
+		return bumpGasPrice(b.GasPrice)
+	}
+
+	return bumpGasPrice(b.GasTipCap)
+}
+
+func bumpGasPrice(value *big.Int) *big.Int {
+	price := new(big.Int).Mul(value, big.NewInt(100+transactionReplacementGasPriceBump)) //nolint:gomnd // Percent.
+
+	return price.Div(price, big.NewInt(100)).Add(price, big.NewInt(1)) //nolint:gomnd // Percent.
+}
+
+func (g *replacementGasGetter) GetGasOptions(ctx context.Context) (*gasOptions, error) {
+	options, err := g.gasGetter.GetGasOptions(ctx)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // .
+	}
+
+	bumped := &gasOptions{Price: maxBigInt(options.Price, g.MinPrice), Limit: options.Limit}
+	if options.TipCap != nil {
+		bumped.TipCap = maxBigInt(options.TipCap, g.MinTipCap)
+		bumped.Price = maxBigInt(bumped.Price, bumped.TipCap)
+	}
+	if g.MaxPrice != nil && bumped.Price.Cmp(g.MaxPrice) > 0 {
+		return nil, errors.Wrapf(errGasFeeCapExceeded, "replacement gas price %v > %v", bumped.Price, g.MaxPrice)
+	}
+	g.Last = bumped
+
+	return bumped, nil
+}
+
+func maxBigInt(a, b *big.Int) *big.Int {
+	if a.Cmp(b) < 0 {
+		return b
+	}
+
+	return a
 }
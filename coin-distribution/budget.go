@@ -0,0 +1,108 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package coindistribution
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/wintr/connectors/storage/v2"
+	"github.com/ice-blockchain/wintr/log"
+)
+
+// getBudgetBreaches compares the coin distributions pending review (the current cycle) with the budget guards:
+// `coin_distributer_review_max_cycle_ice`, `coin_distributer_review_max_user_ice` and `coin_distributer_review_max_cycle_increase_percent`
+// over the ICE approved in the previous cycle. 0 disables a guard.
+func getBudgetBreaches(ctx context.Context, db storage.Querier) (*budgetGuards, []*BudgetBreach, error) {
+	guards := new(budgetGuards)
+	for key, val := range map[string]*uint64{
+		configKeyCoinDistributerMaxCycleIce:      &guards.MaxCycleIce,
+		configKeyCoinDistributerMaxUserIce:       &guards.MaxUserIce,
+		configKeyCoinDistributerMaxCycleIncrease: &guards.MaxCycleIncreasePercent,
+	} {
+		if err := databaseGetValue(ctx, db, key, val); err != nil {
+			return nil, nil, err
+		}
+	}
+	totals, err := getBudgetTotals(ctx, db, guards.MaxUserIce)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return guards, guards.breaches(totals), nil
+}
+
+// getBudgetTotals sums the coin distributions pending review by user, they have one row for each day of the cycle.
+func getBudgetTotals(ctx context.Context, db storage.Querier, maxUserIce uint64) (*budgetTotals, error) {
+	const sql = `WITH users AS (
+					SELECT user_id, sum(ice) AS ice
+					FROM coin_distributions_pending_review
+					GROUP BY user_id
+				 )
+				 SELECT (SELECT coalesce(sum(ice), 0) FROM users) AS cycle_ice,
+						(SELECT coalesce(max(ice), 0) FROM users) AS max_user_ice,
+						(SELECT count(1) FROM users WHERE $1 > 0 AND ice > $1) AS users_over_limit,
+						(SELECT coalesce(sum(ice), 0)
+						 FROM reviewed_coin_distributions
+						 WHERE decision != 'deny'
+						   AND review_day = ` + previousReviewDaySQL + `
+						) AS previous_cycle_ice`
+	totals, err := storage.ExecOne[budgetTotals](ctx, db, sql, maxUserIce*100) //nolint:gomnd // ice is stored in hundredths.
+
+	return totals, errors.Wrap(err, "failed to select coin_distributions_pending_review budget totals")
+}
+
+func (g *budgetGuards) breaches(totals *budgetTotals) []*BudgetBreach {
+	breaches := make([]*BudgetBreach, 0, 3) //nolint:gomnd // 3 guards.
+	cycleIce, previousCycleIce := float64(totals.CycleIce)/100, float64(totals.PreviousCycleIce)/100
+	if g.MaxCycleIce > 0 && cycleIce > float64(g.MaxCycleIce) {
+		breaches = append(breaches, &BudgetBreach{Guard: budgetGuardMaxCycleIce, Limit: float64(g.MaxCycleIce), Actual: cycleIce})
+	}
+	if g.MaxUserIce > 0 && totals.UsersOverLimit > 0 {
+		breaches = append(breaches, &BudgetBreach{
+			Guard:  budgetGuardMaxUserIce,
+			Limit:  float64(g.MaxUserIce),
+			Actual: float64(totals.MaxUserIce) / 100,
+			Users:  totals.UsersOverLimit,
+		})
+	}
+	if g.MaxCycleIncreasePercent > 0 && previousCycleIce > 0 {
+		if increase := (cycleIce - previousCycleIce) * 100 / previousCycleIce; increase > float64(g.MaxCycleIncreasePercent) {
+			breaches = append(breaches, &BudgetBreach{Guard: budgetGuardMaxCycleIncreasePercent, Limit: float64(g.MaxCycleIncreasePercent), Actual: increase})
+		}
+	}
+
+	return breaches
+}
+
+// ensureBudgetGuards blocks the approval of a cycle breaching any of the budget guards, unless the reviewer overrides them.
+func ensureBudgetGuards(ctx context.Context, conn storage.QueryExecer, reviewerUserID string, override bool) error {
+	_, breaches, err := getBudgetBreaches(ctx, conn)
+	if err != nil {
+		return errors.Wrap(err, "failed to getBudgetBreaches")
+	}
+	if len(breaches) == 0 {
+		return nil
+	}
+	descriptions := make([]string, 0, len(breaches))
+	for _, breach := range breaches {
+		descriptions = append(descriptions, breach.String())
+	}
+	if !override {
+		return errors.Wrapf(ErrBudgetGuardsBreached, "%v", strings.Join(descriptions, "; "))
+	}
+	log.Warn(fmt.Sprintf("budget guards overridden by %v: %v", reviewerUserID, strings.Join(descriptions, "; ")))
+
+	return nil
+}
+
+func (b *BudgetBreach) String() string {
+	if b.Users > 0 {
+		return fmt.Sprintf("%v: %v user(s) above %v, up to %.2f", b.Guard, b.Users, b.Limit, b.Actual)
+	}
+
+	return fmt.Sprintf("%v: %.2f above %v", b.Guard, b.Actual, b.Limit)
+}
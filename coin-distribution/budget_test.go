@@ -0,0 +1,59 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package coindistribution
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ice-blockchain/wintr/connectors/storage/v2"
+)
+
+func TestBudgetGuardsBreaches(t *testing.T) {
+	t.Parallel()
+
+	totals := &budgetTotals{CycleIce: 150_000, MaxUserIce: 60_000, UsersOverLimit: 2, PreviousCycleIce: 100_000}
+	require.Empty(t, new(budgetGuards).breaches(totals))
+	require.Empty(t, (&budgetGuards{MaxCycleIce: 1_500, MaxCycleIncreasePercent: 50}).breaches(totals))
+
+	breaches := (&budgetGuards{MaxCycleIce: 1_000, MaxUserIce: 500, MaxCycleIncreasePercent: 20}).breaches(totals)
+	require.Len(t, breaches, 3)
+	assert.Equal(t, &BudgetBreach{Guard: budgetGuardMaxCycleIce, Limit: 1_000, Actual: 1_500}, breaches[0])
+	assert.Equal(t, &BudgetBreach{Guard: budgetGuardMaxUserIce, Limit: 500, Actual: 600, Users: 2}, breaches[1])
+	assert.Equal(t, &BudgetBreach{Guard: budgetGuardMaxCycleIncreasePercent, Limit: 20, Actual: 50}, breaches[2])
+	assert.Equal(t, "maxUserIce: 2 user(s) above 500, up to 600.00", breaches[1].String())
+
+	totals.PreviousCycleIce = 0
+	require.Empty(t, (&budgetGuards{MaxCycleIncreasePercent: 20}).breaches(totals))
+
+	t.Run("user with several days in the cycle", func(t *testing.T) {
+		maybeSkipTest(t)
+		ctx := context.TODO()
+		db := storage.MustConnect(ctx, ddl, applicationYamlKey)
+		defer db.Close()
+
+		// Every day is below the limit, but not their sum, and way above the ice of any other user.
+		const dayIce, maxUserIce = 100_000_000_000_000, 1_500_000_000_000
+		before, err := getBudgetTotals(ctx, db, maxUserIce)
+		require.NoError(t, err)
+		userID := RandStringBytes(8)
+		_, err = storage.Exec(ctx, db, `INSERT INTO coin_distributions_pending_review(created_at, internal_id, ice, day, iceflakes, username, referred_by_username, user_id, eth_address)
+										VALUES (current_timestamp, 1, $3, current_date - 1, 10000000000000000000, $1, '', $1, $2),
+											   (current_timestamp, 1, $3, current_date - 2, 10000000000000000000, $1, '', $1, $2)`,
+			userID, "0x0000000000000000000000000000000000000001", dayIce)
+		require.NoError(t, err)
+		defer func() {
+			_, dErr := storage.Exec(ctx, db, `DELETE FROM coin_distributions_pending_review WHERE user_id = $1`, userID)
+			require.NoError(t, dErr)
+		}()
+
+		after, err := getBudgetTotals(ctx, db, maxUserIce)
+		require.NoError(t, err)
+		assert.Equal(t, before.UsersOverLimit+1, after.UsersOverLimit)
+		assert.EqualValues(t, 2*dayIce, after.MaxUserIce)
+		assert.Equal(t, before.CycleIce+2*dayIce, after.CycleIce)
+	})
+}
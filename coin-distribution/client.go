@@ -8,6 +8,8 @@ import (
 	"math/big"
 	"net"
 	"net/http"
+	"slices"
+	"sort"
 	"strings"
 	"sync"
 	"syscall"
@@ -16,9 +18,10 @@ import (
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/txpool"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/hashicorp/go-multierror"
@@ -28,22 +31,29 @@ import (
 	"github.com/ice-blockchain/wintr/log"
 )
 
-func mustNewEthClient(ctx context.Context, endpoint, privateKey, contract string) *ethClientImpl {
-	key, err := crypto.HexToECDSA(privateKey)
-	log.Panic(errors.Wrap(err, "failed to parse private key")) //nolint:revive,nolintlint //.
-
-	rpcClient, err := ethclient.DialContext(ctx, endpoint)
+func mustNewEthClient(ctx context.Context, chain *chainConfig) *ethClientImpl {
+	rpcClient, err := ethclient.DialContext(ctx, chain.RPC)
 	log.Panic(errors.Wrap(err, "failed to connect to ethereum RPC")) //nolint:revive,nolintlint //.
 
-	distributor, err := coindistribution.NewCoindistribution(common.HexToAddress(contract), rpcClient)
+	distributor, err := coindistribution.NewCoindistribution(common.HexToAddress(chain.ContractAddress), rpcClient)
 	log.Panic(errors.Wrap(err, "failed to create contract instance")) //nolint:revive,nolintlint //.
 
-	return &ethClientImpl{
+	client := &ethClientImpl{
 		RPC:        rpcClient,
 		AirDropper: distributor,
-		Key:        key,
+		Filterer:   distributor,
+		Token:      distributor,
+		Contract:   common.HexToAddress(chain.ContractAddress),
+		Signer:     mustNewSigner(ctx, chain),
 		Mutex:      new(sync.Mutex),
 	}
+	if chain.MerkleRootsContractAddress != "" {
+		client.MerkleRootsContract = common.HexToAddress(chain.MerkleRootsContractAddress)
+		client.MerkleRoots, err = coindistribution.NewMerkleRoots(client.MerkleRootsContract, rpcClient)
+		log.Panic(errors.Wrap(err, "failed to create merkle roots contract instance")) //nolint:revive,nolintlint //.
+	}
+
+	return client
 }
 
 func handleRPCError(ctx context.Context, target error) (retryAfter time.Duration) {
@@ -68,6 +78,16 @@ func handleRPCError(ctx context.Context, target error) (retryAfter time.Duration
 		return 0
 	}
 
+	if errors.Is(target, errGasEstimation) || errors.Is(target, errSigningRejected) || errors.Is(target, errLeadershipLost) {
+		return 0
+	}
+
+	if errors.Is(target, errGasFeeCapExceeded) {
+		log.Error(errors.Wrap(sendEthereumGasFeeCapExceededSlackMessage(ctx, target.Error()), "failed to send slack message"))
+
+		return time.Minute * 10
+	}
+
 	// We may have two types of errors here:
 	// 1. Errors from ethereum RPC.
 	// 2. Errors from ethereum module (pre validation).
@@ -90,8 +110,10 @@ func handleRPCError(ctx context.Context, target error) (retryAfter time.Duration
 		core.ErrTxTypeNotSupported,
 		core.ErrSenderNoEOA,
 		core.ErrBlobFeeCapTooLow,
+		// Retrying with the same fees does not help, the replacement bumps them.
+		txpool.ErrReplaceUnderpriced,
 	} {
-		if errors.Is(target, ethErr) || strings.HasPrefix(target.Error(), ethErr.Error()) {
+		if isEthError(target, ethErr) {
 			return 0
 		}
 	}
@@ -99,6 +121,15 @@ func handleRPCError(ctx context.Context, target error) (retryAfter time.Duration
 	return time.Minute
 }
 
+func isEthError(target, ethErr error) bool {
+	return errors.Is(target, ethErr) || strings.HasPrefix(target.Error(), ethErr.Error())
+}
+
+// containsEthError is isEthError for the errors that were wrapped or aggregated since they were returned by the node.
+func containsEthError(target, ethErr error) bool {
+	return errors.Is(target, ethErr) || strings.Contains(target.Error(), ethErr.Error())
+}
+
 func maybeRetryRPCRequest[T any](ctx context.Context, fn func() (T, error)) (val T, err error) {
 main:
 	for attempt := 1; ctx.Err() == nil; attempt++ {
@@ -136,17 +167,58 @@ func (ec *ethClientImpl) SuggestGasPrice(ctx context.Context) (*big.Int, error)
 	})
 }
 
+func (ec *ethClientImpl) SuggestGasFees(ctx context.Context) (baseFee, tipCap *big.Int, err error) {
+	history, err := maybeRetryRPCRequest(ctx, func() (*ethereum.FeeHistory, error) {
+		return ec.RPC.FeeHistory(ctx, feeHistoryBlocks, nil, []float64{feeHistoryRewardPercentile}) //nolint:wrapcheck //.
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if baseFee, tipCap = feesFromHistory(history); tipCap == nil {
+		// Nobody paid any priority fee recently (or the node does not report rewards), so we ask for the node's guess.
+		tipCap, err = maybeRetryRPCRequest(ctx, func() (*big.Int, error) {
+			return ec.RPC.SuggestGasTipCap(ctx) //nolint:wrapcheck //.
+		})
+	}
+
+	return baseFee, tipCap, err
+}
+
+// feesFromHistory returns the base fee of the next block and the median of the priority fees paid in the recent blocks.
+func feesFromHistory(history *ethereum.FeeHistory) (baseFee, tipCap *big.Int) {
+	baseFee = big.NewInt(0)
+	if len(history.BaseFee) != 0 {
+		baseFee = history.BaseFee[len(history.BaseFee)-1]
+	}
+
+	tips := make([]*big.Int, 0, len(history.Reward))
+	for _, rewards := range history.Reward {
+		if len(rewards) != 0 && rewards[0] != nil && rewards[0].Sign() > 0 {
+			tips = append(tips, rewards[0])
+		}
+	}
+	if len(tips) == 0 {
+		return baseFee, nil
+	}
+	sort.Slice(tips, func(i, j int) bool { return tips[i].Cmp(tips[j]) < 0 })
+
+	return baseFee, tips[len(tips)/2]
+}
+
 func (ec *ethClientImpl) AirdropToWallets(opts *bind.TransactOpts, recipients []common.Address, amounts []*big.Int) (*types.Transaction, error) {
-	// The slow zone, we **must** have `nonce` as a linear sequence, **without** gaps.
+	// The slow zone, nonces are assigned by the processor, but transactions are still sent one by one.
 	ec.Mutex.Lock()
 	defer ec.Mutex.Unlock()
 
 	tx, err := ec.AirDropper.AirdropToWallets(opts, recipients, amounts)
 	if err == nil && opts.Context.Err() == nil {
-		log.Info(fmt.Sprintf("airdropper: new transaction: %v | nonce %v | gas %v | cost %v | limit %v | recipients %v",
+		log.Info(fmt.Sprintf("airdropper: new transaction: %v | type %v | nonce %v | gas %v | tip %v | cost %v | limit %v | recipients %v",
 			tx.Hash().String(),
+			tx.Type(),
 			tx.Nonce(),
 			tx.GasPrice().String(),
+			tx.GasTipCap().String(),
 			tx.Cost().String(),
 			tx.Gas(),
 			len(recipients),
@@ -156,36 +228,268 @@ func (ec *ethClientImpl) AirdropToWallets(opts *bind.TransactOpts, recipients []
 	return tx, err //nolint:wrapcheck //.
 }
 
-func (ec *ethClientImpl) CreateTransactionOpts(ctx context.Context, gasPrice, chanID *big.Int, gasLimit uint64) *bind.TransactOpts {
-	opts, err := bind.NewKeyedTransactorWithChainID(ec.Key, chanID)
-	log.Panic(errors.Wrap(err, "failed to create transaction options")) //nolint:revive,nolintlint //.
+func (ec *ethClientImpl) PendingNonceAt(ctx context.Context) (uint64, error) {
+	return maybeRetryRPCRequest(ctx, func() (uint64, error) {
+		return ec.RPC.PendingNonceAt(ctx, ec.Signer.Address()) //nolint:wrapcheck //.
+	})
+}
+
+func (ec *ethClientImpl) BlockGasLimit(ctx context.Context) (uint64, error) {
+	return maybeRetryRPCRequest(ctx, func() (uint64, error) {
+		header, err := ec.RPC.HeaderByNumber(ctx, nil)
+		if err != nil {
+			return 0, err //nolint:wrapcheck //.
+		}
+
+		return header.GasLimit, nil
+	})
+}
+
+func (ec *ethClientImpl) EstimateAirdropGas(ctx context.Context, recipients []common.Address, amounts []*big.Int) (uint64, error) {
+	contractABI, err := coindistribution.CoindistributionMetaData.GetAbi()
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to parse contract ABI")
+	}
+	data, err := contractABI.Pack("airdropToWallets", recipients, amounts)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to pack airdropToWallets call")
+	}
+
+	return ec.estimateGas(ctx, ethereum.CallMsg{From: ec.Signer.Address(), To: &ec.Contract, Data: data})
+}
+
+func (ec *ethClientImpl) EstimateMerkleRootGas(ctx context.Context, cycle uint64, root common.Hash) (uint64, error) {
+	contractABI, err := coindistribution.MerkleRootsMetaData.GetAbi()
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to parse merkle roots contract ABI")
+	}
+	data, err := contractABI.Pack("publishMerkleRoot", new(big.Int).SetUint64(cycle), root)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to pack publishMerkleRoot call")
+	}
+
+	return ec.estimateGas(ctx, ethereum.CallMsg{From: ec.Signer.Address(), To: &ec.MerkleRootsContract, Data: data})
+}
+
+func (ec *ethClientImpl) estimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error) {
+	return maybeRetryRPCRequest(ctx, func() (uint64, error) {
+		gas, eErr := ec.RPC.EstimateGas(ctx, msg)
+		// The node executed the call and it failed (reverted, out of gas, etc.), it's not going to get any better with retries.
+		var rpcErr rpc.Error
+		if errors.As(eErr, &rpcErr) {
+			return 0, multierror.Append(errGasEstimation, eErr)
+		}
+
+		return gas, eErr //nolint:wrapcheck //.
+	})
+}
+
+// TokenBalance returns the ICE balance of the distribution contract, the airdrops are paid from it.
+func (ec *ethClientImpl) TokenBalance(ctx context.Context) (*big.Int, error) {
+	return maybeRetryRPCRequest(ctx, func() (*big.Int, error) {
+		return ec.Token.BalanceOf(&bind.CallOpts{Context: ctx}, ec.Contract) //nolint:wrapcheck //.
+	})
+}
+
+// GasBalance returns the native balance of the sender, paying for the gas of the airdrops.
+func (ec *ethClientImpl) GasBalance(ctx context.Context) (*big.Int, error) {
+	return maybeRetryRPCRequest(ctx, func() (*big.Int, error) {
+		return ec.RPC.BalanceAt(ctx, ec.Signer.Address(), nil) //nolint:wrapcheck //.
+	})
+}
+
+func (ec *ethClientImpl) LatestBlockNumber(ctx context.Context) (uint64, error) {
+	return maybeRetryRPCRequest(ctx, func() (uint64, error) {
+		return ec.RPC.BlockNumber(ctx) //nolint:wrapcheck //.
+	})
+}
+
+// TransferLogs returns the ICE transfers from the contract (airdrops) mined in the [fromBlock, toBlock] range.
+func (ec *ethClientImpl) TransferLogs(ctx context.Context, fromBlock, toBlock uint64) ([]*transferLog, error) {
+	return maybeRetryRPCRequest(ctx, func() ([]*transferLog, error) {
+		it, err := ec.Filterer.FilterTransfer(&bind.FilterOpts{Start: fromBlock, End: &toBlock, Context: ctx}, []common.Address{ec.Contract}, nil)
+		if err != nil {
+			return nil, err //nolint:wrapcheck //.
+		}
+		defer it.Close()
+
+		logs := make([]*transferLog, 0)
+		for it.Next() {
+			logs = append(logs, &transferLog{
+				Value:       it.Event.Value,
+				TxHash:      it.Event.Raw.TxHash.Hex(),
+				EthAddress:  strings.ToLower(it.Event.To.Hex()),
+				BlockNumber: it.Event.Raw.BlockNumber,
+				LogIndex:    it.Event.Raw.Index,
+			})
+		}
+
+		return logs, it.Error() //nolint:wrapcheck //.
+	})
+}
+
+func (ec *ethClientImpl) CreateTransactionOpts(ctx context.Context, gas *gasOptions, chanID *big.Int, nonce uint64) *bind.TransactOpts {
+	opts := &bind.TransactOpts{
+		From: ec.Signer.Address(),
+		Signer: func(address common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			if address != ec.Signer.Address() {
+				return nil, bind.ErrNotAuthorized
+			}
+
+			return ec.Signer.SignTx(ctx, tx, chanID) //nolint:wrapcheck //.
+		},
+	}
 	opts.Context = ctx
 	opts.Value = big.NewInt(0)
-	opts.GasLimit = gasLimit
-	opts.GasPrice = gasPrice
+	opts.GasLimit = gas.Limit
+	opts.Nonce = new(big.Int).SetUint64(nonce)
+	if gas.TipCap == nil {
+		opts.GasPrice = gas.Price
+	} else {
+		opts.GasFeeCap = gas.Price
+		opts.GasTipCap = gas.TipCap
+	}
 
 	return opts
 }
 
-func (ec *ethClientImpl) Airdrop(ctx context.Context, chanID *big.Int, gas gasGetter, recipients []common.Address, amounts []*big.Int) (string, error) {
-	fn := func() (string, error) {
-		gasPrice, gasLimit, err := gas.GetGasOptions(ctx)
+func (ec *ethClientImpl) Airdrop(
+	ctx context.Context, chanID *big.Int, gas gasGetter, nonce uint64, recipients []common.Address, amounts []*big.Int, signed signedTxHandler,
+) (*airdropTransaction, error) {
+	return ec.sendTransaction(ctx, chanID, gas, nonce, signed, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return ec.AirdropToWallets(opts, recipients, amounts)
+	})
+}
+
+func (ec *ethClientImpl) PublishMerkleRoot(
+	ctx context.Context, chanID *big.Int, gas gasGetter, nonce, cycle uint64, root common.Hash, signed signedTxHandler,
+) (*airdropTransaction, error) {
+	return ec.sendTransaction(ctx, chanID, gas, nonce, signed, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		ec.Mutex.Lock()
+		defer ec.Mutex.Unlock()
+
+		tx, err := ec.MerkleRoots.PublishMerkleRoot(opts, new(big.Int).SetUint64(cycle), root)
+		if err != nil {
+			return nil, err //nolint:wrapcheck //.
+		}
+		log.Info(fmt.Sprintf("merkle roots: new transaction: %v | type %v | nonce %v | gas %v | tip %v | limit %v | cycle %v | root %v",
+			tx.Hash().String(),
+			tx.Type(),
+			tx.Nonce(),
+			tx.GasPrice().String(),
+			tx.GasTipCap().String(),
+			tx.Gas(),
+			cycle,
+			root.Hex(),
+		))
+
+		return tx, nil
+	})
+}
+
+// sendTransaction hands every signed attempt over to signed before it's sent, so its hash is stored even if the outcome of sending it is lost.
+// Such an attempt might be in the pool of the node or even mined by the time we retry, so it's looked up instead of being rejected.
+func (ec *ethClientImpl) sendTransaction(
+	ctx context.Context, chanID *big.Int, gas gasGetter, nonce uint64, signed signedTxHandler, send func(opts *bind.TransactOpts) (*types.Transaction, error),
+) (*airdropTransaction, error) {
+	attempts := make([]*airdropTransaction, 0, 1)
+	fn := func() (*airdropTransaction, error) {
+		options, err := gas.GetGasOptions(ctx)
 		if err != nil {
-			return "", errors.Wrap(err, "failed to get gas options")
+			return nil, errors.Wrap(err, "failed to get gas options")
 		}
 
-		opts := ec.CreateTransactionOpts(ctx, gasPrice, chanID, gasLimit)
-		tx, err := ec.AirdropToWallets(opts, recipients, amounts)
+		opts := ec.CreateTransactionOpts(ctx, options, chanID, nonce)
+		sign := opts.Signer
+		opts.Signer = func(address common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			signedTx, sErr := sign(address, tx)
+			if sErr != nil {
+				return nil, sErr
+			}
+			attempt := &airdropTransaction{Hash: signedTx.Hash().String(), Nonce: nonce, GasPrice: options.Price, GasTipCap: options.TipCap}
+			if sErr = signed(attempt); sErr != nil {
+				return nil, errors.Wrapf(sErr, "failed to store signed transaction %v", attempt.Hash)
+			}
+			attempts = append(attempts, attempt)
+
+			return signedTx, nil
+		}
+		tx, err := send(opts)
 		if err != nil {
-			return "", err
+			return ec.knownTransaction(ctx, err, attempts)
 		}
 
-		return tx.Hash().String(), nil
+		return &airdropTransaction{Hash: tx.Hash().String(), Nonce: nonce, GasPrice: options.Price, GasTipCap: options.TipCap}, nil
 	}
 
 	return maybeRetryRPCRequest(ctx, fn)
 }
 
+// knownTransaction returns the attempt the node has already seen, if the sending failed because of it:
+// "already known" means the last one is in its pool, "replacement transaction underpriced" means one with the nonce is,
+// ours if the node knows it, and "nonce too low" means the nonce is used, by one of ours if it's mined.
+func (ec *ethClientImpl) knownTransaction(ctx context.Context, sendErr error, attempts []*airdropTransaction) (*airdropTransaction, error) {
+	if len(attempts) == 0 {
+		return nil, sendErr
+	}
+	if isEthError(sendErr, txpool.ErrAlreadyKnown) {
+		attempt := attempts[len(attempts)-1]
+		log.Warn(fmt.Sprintf("transaction %v with nonce %v is already known", attempt.Hash, attempt.Nonce))
+
+		return attempt, nil
+	} else if isEthError(sendErr, txpool.ErrReplaceUnderpriced) {
+		return ec.pooledTransaction(ctx, sendErr, attempts)
+	} else if !isEthError(sendErr, core.ErrNonceTooLow) {
+		return nil, sendErr
+	}
+
+	hashes := make([]*string, 0, len(attempts))
+	for _, attempt := range attempts {
+		hashes = append(hashes, &attempt.Hash)
+	}
+	receipts, err := ec.TransactionsReceipts(ctx, hashes)
+	if err != nil {
+		return nil, multierror.Append(sendErr, errors.Wrap(err, "failed to get receipts of the signed transactions"))
+	}
+	for _, attempt := range attempts {
+		if receipts[attempt.Hash] != nil {
+			log.Warn(fmt.Sprintf("transaction %v with nonce %v is already mined", attempt.Hash, attempt.Nonce))
+
+			return attempt, nil
+		}
+	}
+
+	return nil, sendErr
+}
+
+// pooledTransaction returns the last of the attempts the node knows, it's the one in its pool if sending a newer one was underpriced.
+func (ec *ethClientImpl) pooledTransaction(ctx context.Context, sendErr error, attempts []*airdropTransaction) (*airdropTransaction, error) {
+	hashes := make([]string, 0, len(attempts))
+	for _, attempt := range attempts {
+		hashes = append(hashes, attempt.Hash)
+	}
+	known, err := ec.KnownTransactions(ctx, hashes)
+	if err != nil {
+		return nil, multierror.Append(sendErr, errors.Wrap(err, "failed to get the signed transactions"))
+	}
+	for idx := len(attempts) - 1; idx >= 0; idx-- {
+		if slices.Contains(known, attempts[idx].Hash) {
+			log.Warn(fmt.Sprintf("transaction %v with nonce %v is already in the pool", attempts[idx].Hash, attempts[idx].Nonce))
+
+			return attempts[idx], nil
+		}
+	}
+
+	return nil, sendErr
+}
+
+// MerkleRoot returns the root published on-chain for the cycle, zero if there is none yet.
+func (ec *ethClientImpl) MerkleRoot(ctx context.Context, cycle uint64) (common.Hash, error) {
+	return maybeRetryRPCRequest(ctx, func() (common.Hash, error) {
+		return ec.MerkleRoots.MerkleRoots(&bind.CallOpts{Context: ctx}, new(big.Int).SetUint64(cycle)) //nolint:wrapcheck //.
+	})
+}
+
 func (ec *ethClientImpl) TransactionStatus(ctx context.Context, hash string) (ethTxStatus, error) {
 	return maybeRetryRPCRequest(ctx, func() (ethTxStatus, error) {
 		receipt, err := ec.RPC.TransactionReceipt(ctx, common.HexToHash(hash))
@@ -205,7 +509,8 @@ func (ec *ethClientImpl) TransactionStatus(ctx context.Context, hash string) (et
 	})
 }
 
-func (ec *ethClientImpl) TransactionsStatus(ctx context.Context, hashes []*string) (statuses map[ethTxStatus][]string, err error) { //nolint:funlen //.
+// TransactionsReceipts returns the receipts of the mined transactions, the pending ones are omitted.
+func (ec *ethClientImpl) TransactionsReceipts(ctx context.Context, hashes []*string) (receipts map[string]*txReceipt, err error) {
 	elements := make([]rpc.BatchElem, len(hashes)) //nolint:makezero //.
 	results := make([]*types.Receipt, len(hashes)) //nolint:makezero //.
 	for elementIdx := range elements {
@@ -222,7 +527,7 @@ func (ec *ethClientImpl) TransactionsStatus(ctx context.Context, hashes []*strin
 		return nil, batchErr
 	}
 
-	statuses = make(map[ethTxStatus][]string)
+	receipts = make(map[string]*txReceipt)
 	for elementIdx := range elements {
 		receipt := results[elementIdx]
 		if receipt == nil {
@@ -234,18 +539,93 @@ func (ec *ethClientImpl) TransactionsStatus(ctx context.Context, hashes []*strin
 			continue
 		}
 
+		status := ethTxStatusFailed
 		if receipt.Status == types.ReceiptStatusSuccessful {
-			statuses[ethTxStatusSuccessful] = append(statuses[ethTxStatusSuccessful], *hashes[elementIdx])
-		} else {
-			statuses[ethTxStatusFailed] = append(statuses[ethTxStatusFailed], *hashes[elementIdx])
+			status = ethTxStatusSuccessful
+		}
+		receipts[*hashes[elementIdx]] = &txReceipt{
+			Status:            status,
+			BlockHash:         receipt.BlockHash.Hex(),
+			BlockNumber:       receipt.BlockNumber.Uint64(),
+			GasUsed:           receipt.GasUsed,
+			EffectiveGasPrice: receipt.EffectiveGasPrice,
+		}
+	}
+
+	return receipts, err //nolint:wrapcheck //.
+}
+
+// KnownTransactions returns the given transactions the node knows about, mined or not.
+func (ec *ethClientImpl) KnownTransactions(ctx context.Context, hashes []string) (known []string, err error) {
+	elements := make([]rpc.BatchElem, len(hashes))     //nolint:makezero //.
+	results := make([]*types.Transaction, len(hashes)) //nolint:makezero //.
+	for elementIdx := range elements {
+		elements[elementIdx] = rpc.BatchElem{
+			Method: "eth_getTransactionByHash",
+			Args:   []any{hashes[elementIdx]},
+			Result: &results[elementIdx],
+		}
+	}
+
+	if _, batchErr := maybeRetryRPCRequest(ctx, func() (bool, error) {
+		return true, ec.RPC.Client().BatchCallContext(ctx, elements) //nolint:wrapcheck //.
+	}); batchErr != nil {
+		return nil, batchErr
+	}
+
+	for elementIdx := range elements {
+		if elements[elementIdx].Error != nil {
+			err = multierror.Append(err, elements[elementIdx].Error)
+		} else if results[elementIdx] != nil {
+			known = append(known, hashes[elementIdx])
+		}
+	}
+
+	return known, err //nolint:wrapcheck //.
+}
+
+// ConfirmedNonceAt returns the nonce of the next TX of the account to be mined, I.E. the number of its mined TXs.
+func (ec *ethClientImpl) ConfirmedNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	return maybeRetryRPCRequest(ctx, func() (uint64, error) {
+		return ec.RPC.NonceAt(ctx, account, nil) //nolint:wrapcheck //.
+	})
+}
+
+// ContractAddresses returns the given addresses that have code deployed at them, i.e. they're contracts, not wallets.
+func (ec *ethClientImpl) ContractAddresses(ctx context.Context, addresses []string) ([]string, error) {
+	elements := make([]rpc.BatchElem, len(addresses)) //nolint:makezero //.
+	results := make([]hexutil.Bytes, len(addresses))  //nolint:makezero //.
+	for elementIdx := range elements {
+		elements[elementIdx] = rpc.BatchElem{
+			Method: "eth_getCode",
+			Args:   []any{common.HexToAddress(addresses[elementIdx]), "latest"},
+			Result: &results[elementIdx],
+		}
+	}
+
+	if _, err := maybeRetryRPCRequest(ctx, func() (bool, error) {
+		return true, ec.RPC.Client().BatchCallContext(ctx, elements) //nolint:wrapcheck //.
+	}); err != nil {
+		return nil, err
+	}
+
+	var (
+		contracts []string
+		mErr      *multierror.Error
+	)
+	for elementIdx := range elements {
+		if elements[elementIdx].Error != nil {
+			mErr = multierror.Append(mErr, errors.Wrapf(elements[elementIdx].Error, "failed to get code at %v", addresses[elementIdx]))
+		} else if len(results[elementIdx]) != 0 {
+			contracts = append(contracts, addresses[elementIdx])
 		}
 	}
 
-	return statuses, err //nolint:wrapcheck //.
+	return contracts, mErr.ErrorOrNil() //nolint:wrapcheck //.
 }
 
 func (ec *ethClientImpl) Close() error {
 	ec.RPC.Close()
 
-	return nil
+	return errors.Wrap(ec.Signer.Close(), "failed to close signer")
 }
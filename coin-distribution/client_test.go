@@ -12,10 +12,16 @@ import (
 	"syscall"
 	"testing"
 
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/txpool"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
 
 	"github.com/ice-blockchain/wintr/log"
@@ -30,6 +36,18 @@ type (
 	mockedAirDropper struct {
 		errBefore int
 	}
+	// mockedSigningAirDropper signs every TX, but fails to send it with the next of sendErrs, if any.
+	mockedSigningAirDropper struct {
+		// pool gets the TXs that reached the node, if set.
+		pool     map[common.Hash]*types.Transaction
+		sendErrs []error
+		signed   []common.Hash
+	}
+	// mockedReceiptsAPI serves eth_getTransactionReceipt of the mined TXs and eth_getTransactionByHash of the pooled ones.
+	mockedReceiptsAPI struct {
+		mined  map[common.Hash]*types.Receipt
+		pooled map[common.Hash]*types.Transaction
+	}
 	mockedGasGetter struct {
 		val int64
 	}
@@ -45,20 +63,92 @@ func (m *mockedDummyEthClient) SuggestGasPrice(context.Context) (*big.Int, error
 	return big.NewInt(m.gas), nil
 }
 
-func (m *mockedDummyEthClient) Airdrop(context.Context, *big.Int, gasGetter, []common.Address, []*big.Int) (string, error) {
+func (m *mockedDummyEthClient) Airdrop(ctx context.Context, _ *big.Int, gas gasGetter, nonce uint64, _ []common.Address, _ []*big.Int, signed signedTxHandler) (*airdropTransaction, error) { //nolint:lll // .
 	if m.dropErr != nil {
-		return "", m.dropErr
+		return nil, m.dropErr
+	}
+	options, err := gas.GetGasOptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tx := &airdropTransaction{
+		Hash:      fmt.Sprintf("%10d", rand.Int63n(10_000_000_000)), //nolint:gosec //.
+		Nonce:     nonce,
+		GasPrice:  options.Price,
+		GasTipCap: options.TipCap,
+	}
+	if err = signed(tx); err != nil {
+		return nil, err
 	}
 
-	return fmt.Sprintf("%10d", rand.Int63n(10_000_000_000)), nil //nolint:gosec //.
+	return tx, nil
+}
+
+func (m *mockedDummyEthClient) PublishMerkleRoot(ctx context.Context, chanID *big.Int, gas gasGetter, nonce, _ uint64, _ common.Hash, signed signedTxHandler) (*airdropTransaction, error) { //nolint:lll // .
+	return m.Airdrop(ctx, chanID, gas, nonce, nil, nil, signed)
+}
+
+func (*mockedDummyEthClient) EstimateMerkleRootGas(context.Context, uint64, common.Hash) (uint64, error) {
+	return 50_000, nil
+}
+
+func (*mockedDummyEthClient) MerkleRoot(context.Context, uint64) (common.Hash, error) {
+	return common.Hash{}, nil
+}
+
+func (m *mockedDummyEthClient) SuggestGasFees(ctx context.Context) (baseFee, tipCap *big.Int, err error) {
+	baseFee, err = m.SuggestGasPrice(ctx)
+
+	return baseFee, big.NewInt(1), err
+}
+
+func (*mockedDummyEthClient) BlockGasLimit(context.Context) (uint64, error) {
+	return 30_000_000, nil
+}
+
+func (*mockedDummyEthClient) EstimateAirdropGas(_ context.Context, recipients []common.Address, _ []*big.Int) (uint64, error) {
+	return 50_000 + 30_000*uint64(len(recipients)), nil
+}
+
+func (*mockedDummyEthClient) TokenBalance(context.Context) (*big.Int, error) {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(30), nil), nil //nolint:gomnd // Enough for any test.
+}
+
+func (*mockedDummyEthClient) GasBalance(context.Context) (*big.Int, error) {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil), nil //nolint:gomnd // 1 ETH.
+}
+
+func (*mockedDummyEthClient) LatestBlockNumber(context.Context) (uint64, error) {
+	return 1_000, nil
+}
+
+func (*mockedDummyEthClient) TransferLogs(context.Context, uint64, uint64) ([]*transferLog, error) {
+	return nil, nil
+}
+
+func (*mockedDummyEthClient) ContractAddresses(context.Context, []string) ([]string, error) {
+	return nil, nil
+}
+
+func (*mockedDummyEthClient) PendingNonceAt(context.Context) (uint64, error) {
+	return 0, nil
 }
 
 func (*mockedDummyEthClient) Close() error {
 	return nil
 }
 
-func (*mockedDummyEthClient) TransactionsStatus(context.Context, []*string) (map[ethTxStatus][]string, error) {
-	return nil, nil //nolint:nilnil //.
+func (m *mockedDummyEthClient) TransactionsReceipts(ctx context.Context, hashes []*string) (map[string]*txReceipt, error) {
+	receipts := make(map[string]*txReceipt)
+	for _, hash := range hashes {
+		status, err := m.TransactionStatus(ctx, *hash)
+		if err != nil {
+			return nil, err
+		}
+		receipts[*hash] = &txReceipt{Status: status, BlockHash: "0x1", BlockNumber: 1, GasUsed: 21_000, EffectiveGasPrice: big.NewInt(1)}
+	}
+
+	return receipts, nil
 }
 
 func (m *mockedDummyEthClient) TransactionStatus(_ context.Context, hash string) (ethTxStatus, error) {
@@ -78,7 +168,7 @@ func (m *mockedAirDropper) AirdropToWallets(opts *bind.TransactOpts, _ []common.
 		return nil, &net.OpError{Err: syscall.ECONNRESET}
 	}
 
-	log.Info(fmt.Sprintf("airdropper: gas price %v, limit %v", opts.GasPrice.String(), opts.GasLimit))
+	log.Info(fmt.Sprintf("airdropper: gas price %v, fee cap %v, tip cap %v, limit %v", opts.GasPrice, opts.GasFeeCap, opts.GasTipCap, opts.GasLimit))
 
 	return types.NewTransaction(
 			0,
@@ -91,12 +181,43 @@ func (m *mockedAirDropper) AirdropToWallets(opts *bind.TransactOpts, _ []common.
 		nil
 }
 
-func (m *mockedGasGetter) GetGasOptions(context.Context) (*big.Int, uint64, error) {
+func (m *mockedSigningAirDropper) AirdropToWallets(opts *bind.TransactOpts, _ []common.Address, _ []*big.Int) (*types.Transaction, error) {
+	tx, err := opts.Signer(opts.From, types.NewTx(&types.LegacyTx{
+		Nonce:    opts.Nonce.Uint64(),
+		GasPrice: opts.GasPrice,
+		Gas:      opts.GasLimit,
+		To:       &common.Address{1},
+	}))
+	if err != nil {
+		return nil, err
+	}
+	m.signed = append(m.signed, tx.Hash())
+	if len(m.sendErrs) > 0 {
+		err, m.sendErrs = m.sendErrs[0], m.sendErrs[1:]
+		if m.pool != nil && !errors.Is(err, txpool.ErrReplaceUnderpriced) {
+			m.pool[tx.Hash()] = tx
+		}
+
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+func (m *mockedReceiptsAPI) GetTransactionReceipt(hash common.Hash) (*types.Receipt, error) {
+	return m.mined[hash], nil
+}
+
+func (m *mockedReceiptsAPI) GetTransactionByHash(hash common.Hash) (*types.Transaction, error) {
+	return m.pooled[hash], nil
+}
+
+func (m *mockedGasGetter) GetGasOptions(context.Context) (*gasOptions, error) {
 	m.val++
 
 	log.Info(fmt.Sprintf("gas getter: %v", m.val))
 
-	return big.NewInt(m.val), uint64(m.val), nil
+	return &gasOptions{Price: big.NewInt(m.val), Limit: uint64(m.val)}, nil
 }
 
 func TestGasPriceUpdateDuringRetry(t *testing.T) {
@@ -111,13 +232,157 @@ func TestGasPriceUpdateDuringRetry(t *testing.T) {
 
 	impl := new(ethClientImpl)
 	impl.Mutex = new(sync.Mutex)
-	impl.Key = privateKey
+	impl.Signer = &localSigner{Key: privateKey}
 	impl.AirDropper = dropper
 	gasGetter := new(mockedGasGetter)
 
-	_, err = impl.Airdrop(context.TODO(), big.NewInt(1), gasGetter, []common.Address{{1}}, []*big.Int{big.NewInt(1)})
+	tx, err := impl.Airdrop(context.TODO(), big.NewInt(1), gasGetter, 42, []common.Address{{1}}, []*big.Int{big.NewInt(1)}, ignoreSignedTx)
 	require.NoError(t, err)
+	require.EqualValues(t, 42, tx.Nonce)
+	require.EqualValues(t, errCount+1, tx.GasPrice.Int64())
 
 	require.Zero(t, dropper.errBefore)
 	require.Equal(t, errCount+1, int(gasGetter.val))
 }
+
+func ignoreSignedTx(*airdropTransaction) error {
+	return nil
+}
+
+func newSigningEthClient(t *testing.T, dropper airDropper, receipts *mockedReceiptsAPI) *ethClientImpl {
+	t.Helper()
+
+	privateKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("eth", receipts))
+	t.Cleanup(server.Stop)
+
+	return &ethClientImpl{
+		RPC:        ethclient.NewClient(rpc.DialInProc(server)),
+		Mutex:      new(sync.Mutex),
+		Signer:     &localSigner{Key: privateKey},
+		AirDropper: dropper,
+	}
+}
+
+func TestAirdropStoresSignedTransactions(t *testing.T) { //nolint:funlen // .
+	t.Parallel()
+
+	send := func(t *testing.T, sendErrs []error, mined func(signed []common.Hash) map[common.Hash]*types.Receipt) (*airdropTransaction, []string, *mockedSigningAirDropper, error) { //nolint:lll // .
+		t.Helper()
+
+		dropper := &mockedSigningAirDropper{sendErrs: sendErrs}
+		receipts := &mockedReceiptsAPI{}
+		impl := newSigningEthClient(t, dropper, receipts)
+		stored := make([]string, 0)
+		signed := func(tx *airdropTransaction) error {
+			stored = append(stored, tx.Hash)
+			if mined != nil {
+				receipts.mined = mined(append(dropper.signed, common.HexToHash(tx.Hash)))
+			}
+
+			return nil
+		}
+		tx, err := impl.Airdrop(context.Background(), big.NewInt(1), new(mockedGasGetter), 7, []common.Address{{1}}, []*big.Int{big.NewInt(1)}, signed)
+
+		return tx, stored, dropper, err
+	}
+	minedFirst := func(signed []common.Hash) map[common.Hash]*types.Receipt {
+		return map[common.Hash]*types.Receipt{signed[0]: {
+			Status:      types.ReceiptStatusSuccessful,
+			BlockHash:   common.Hash{2},
+			BlockNumber: big.NewInt(3),
+			Logs:        []*types.Log{},
+			TxHash:      signed[0],
+		}}
+	}
+	lostOutcome := &net.OpError{Err: syscall.ECONNRESET}
+
+	t.Run("stored before it's sent", func(t *testing.T) {
+		t.Parallel()
+
+		tx, stored, dropper, err := send(t, nil, nil)
+		require.NoError(t, err)
+		require.Equal(t, []string{dropper.signed[0].String()}, stored)
+		require.Equal(t, stored[0], tx.Hash)
+	})
+
+	t.Run("already known", func(t *testing.T) {
+		t.Parallel()
+
+		tx, stored, _, err := send(t, []error{lostOutcome, txpool.ErrAlreadyKnown}, nil)
+		require.NoError(t, err)
+		require.Len(t, stored, 2)
+		require.Equal(t, stored[1], tx.Hash)
+		require.EqualValues(t, 7, tx.Nonce)
+	})
+
+	t.Run("nonce too low, mined", func(t *testing.T) {
+		t.Parallel()
+
+		tx, stored, _, err := send(t, []error{lostOutcome, errors.Wrap(core.ErrNonceTooLow, "address 0x1, tx: 7 state: 8")}, minedFirst)
+		require.NoError(t, err)
+		require.Len(t, stored, 2)
+		require.Equal(t, stored[0], tx.Hash)
+		require.EqualValues(t, 1, tx.GasPrice.Int64())
+	})
+
+	t.Run("nonce too low, not ours", func(t *testing.T) {
+		t.Parallel()
+
+		tx, stored, _, err := send(t, []error{errors.New(core.ErrNonceTooLow.Error() + ": address 0x1, tx: 7 state: 8")}, nil)
+		require.ErrorIs(t, err, errClientUncoverable)
+		require.Nil(t, tx)
+		require.Len(t, stored, 1)
+	})
+
+	t.Run("replacement underpriced, ours in the pool", func(t *testing.T) {
+		t.Parallel()
+
+		pool := make(map[common.Hash]*types.Transaction)
+		dropper := &mockedSigningAirDropper{pool: pool, sendErrs: []error{lostOutcome, txpool.ErrReplaceUnderpriced}}
+		impl := newSigningEthClient(t, dropper, &mockedReceiptsAPI{pooled: pool})
+		tx, err := impl.Airdrop(context.Background(), big.NewInt(1), new(mockedGasGetter), 7, []common.Address{{1}}, []*big.Int{big.NewInt(1)}, ignoreSignedTx)
+		require.NoError(t, err)
+		require.Len(t, dropper.signed, 2)
+		require.Equal(t, dropper.signed[0].String(), tx.Hash)
+		require.EqualValues(t, 1, tx.GasPrice.Int64())
+	})
+
+	t.Run("replacement underpriced, not ours", func(t *testing.T) {
+		t.Parallel()
+
+		tx, stored, _, err := send(t, []error{txpool.ErrReplaceUnderpriced}, nil)
+		require.ErrorIs(t, err, errClientUncoverable, "not retried with the same fees")
+		require.Nil(t, tx)
+		require.Len(t, stored, 1)
+	})
+
+	t.Run("not sent if not stored", func(t *testing.T) {
+		t.Parallel()
+
+		dropper := new(mockedSigningAirDropper)
+		impl := newSigningEthClient(t, dropper, new(mockedReceiptsAPI))
+		_, err := impl.Airdrop(context.Background(), big.NewInt(1), new(mockedGasGetter), 7, nil, nil, func(*airdropTransaction) error {
+			return errLeadershipLost
+		})
+		require.ErrorIs(t, err, errLeadershipLost)
+		require.Empty(t, dropper.signed)
+	})
+}
+
+func TestFeesFromHistory(t *testing.T) {
+	t.Parallel()
+
+	baseFee, tipCap := feesFromHistory(&ethereum.FeeHistory{
+		BaseFee: []*big.Int{big.NewInt(10), big.NewInt(12), big.NewInt(15)},
+		Reward:  [][]*big.Int{{big.NewInt(3)}, {big.NewInt(0)}, {big.NewInt(1)}, {big.NewInt(2)}, {}},
+	})
+	require.EqualValues(t, 15, baseFee.Int64())
+	require.EqualValues(t, 2, tipCap.Int64())
+
+	baseFee, tipCap = feesFromHistory(&ethereum.FeeHistory{Reward: [][]*big.Int{{big.NewInt(0)}}})
+	require.Zero(t, baseFee.Sign())
+	require.Nil(t, tipCap)
+}
@@ -18,15 +18,15 @@ import (
 	"github.com/ice-blockchain/wintr/time"
 )
 
-func (d *databaseConfig) MustDisable(reason string) {
-	for err := d.Disable(context.Background()); err != nil; err = d.Disable(context.Background()) {
-		log.Error(errors.Wrap(err, "failed to disable coinDistributer"))
+func (proc *coinProcessor) MustDisable(reason string) {
+	for err := proc.Disable(context.Background()); err != nil; err = proc.Disable(context.Background()) {
+		log.Error(errors.Wrapf(err, "failed to disable coinDistributer of %v", proc.Target.Name))
 		stdlibtime.Sleep(stdlibtime.Second)
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), requestDeadline)
 	defer cancel()
-	log.Error(sendCoinDistributionsProcessingStoppedDueToUnrecoverableFailureSlackMessage(ctx, reason),
+	log.Error(sendCoinDistributionsProcessingStoppedDueToUnrecoverableFailureSlackMessage(ctx, proc.Target.Name, reason),
 		"failed to sendCoinDistributionsProcessingStoppedDueToUnrecoverableFailureSlackMessage")
 }
 
@@ -87,43 +87,89 @@ func databaseGetValue[T bool | constraints.Integer | time.Time](ctx context.Cont
 	return nil
 }
 
-func (d *databaseConfig) GetGasLimit(ctx context.Context) (val uint64, err error) {
-	err = databaseGetValue(ctx, d.DB, configKeyCoinDistributerGasLimit, &val)
+func (proc *coinProcessor) GetGasLimit(ctx context.Context) (val uint64, err error) {
+	err = databaseGetValue(ctx, proc.DB, proc.targetKey(configKeyCoinDistributerGasLimit), &val)
 
 	return val, err
 }
 
-func (d *databaseConfig) GetGasPriceOverride(ctx context.Context) (val uint64, err error) {
-	err = databaseGetValue(ctx, d.DB, configKeyCoinDistributerGasPrice, &val)
+func (proc *coinProcessor) GetBlockGasLimitMargin(ctx context.Context) (val uint64, err error) {
+	err = databaseGetValue(ctx, proc.DB, proc.targetKey(configKeyCoinDistributerGasMargin), &val)
 
 	return val, err
 }
 
-func (d *databaseConfig) IsEnabled(ctx context.Context) (val bool) {
-	log.Error(errors.Wrap(databaseGetValue(ctx, d.DB, configKeyCoinDistributerEnabled, &val), "failed to databaseGetValue"))
+func (proc *coinProcessor) GetGasPriceOverride(ctx context.Context) (val uint64, err error) {
+	err = databaseGetValue(ctx, proc.DB, proc.targetKey(configKeyCoinDistributerGasPrice), &val)
+
+	return val, err
+}
+
+func (proc *coinProcessor) IsDynamicFeesEnabled(ctx context.Context) (val bool, err error) {
+	err = databaseGetValue(ctx, proc.DB, proc.targetKey(configKeyCoinDistributerDynamicFees), &val)
+
+	return val, err
+}
+
+// GetGasFeeCaps returns the max fee per gas and max priority fee per gas we're willing to pay, 0 means no cap.
+func (proc *coinProcessor) GetGasFeeCaps(ctx context.Context) (feeCap, tipCap uint64, err error) {
+	if err = databaseGetValue(ctx, proc.DB, proc.targetKey(configKeyCoinDistributerMaxFeeCap), &feeCap); err != nil {
+		return 0, 0, err
+	}
+	err = databaseGetValue(ctx, proc.DB, proc.targetKey(configKeyCoinDistributerMaxTipCap), &tipCap)
+
+	return feeCap, tipCap, err
+}
+
+func (proc *coinProcessor) GetMaxInFlightTransactions(ctx context.Context) (val uint64, err error) {
+	err = databaseGetValue(ctx, proc.DB, proc.targetKey(configKeyCoinDistributerMaxInFlight), &val)
+	if err == nil && val == 0 {
+		val = 1
+	}
+
+	return val, err
+}
+
+func (proc *coinProcessor) GetTransactionReplacementTimeout(ctx context.Context) (stdlibtime.Duration, error) {
+	var minutes uint64
+	err := databaseGetValue(ctx, proc.DB, proc.targetKey(configKeyCoinDistributerReplaceTTL), &minutes)
+
+	return stdlibtime.Duration(minutes) * stdlibtime.Minute, err
+}
+
+// GetConfirmationBlocks returns how many blocks deep the block of a TX must be before we settle it, 0 means settled as soon as it's mined.
+func (proc *coinProcessor) GetConfirmationBlocks(ctx context.Context) (val uint64, err error) {
+	err = databaseGetValue(ctx, proc.DB, proc.targetKey(configKeyCoinDistributerConfirmations), &val)
+
+	return val, err
+}
+
+func (proc *coinProcessor) IsEnabled(ctx context.Context) (val bool) {
+	log.Error(errors.Wrap(databaseGetValue(ctx, proc.DB, proc.targetKey(configKeyCoinDistributerEnabled), &val), "failed to databaseGetValue"))
 
 	return val
 }
 
-func (d *databaseConfig) IsOnDemandMode(ctx context.Context) (val bool) {
-	log.Error(databaseGetValue(ctx, d.DB, configKeyCoinDistributerOnDemand, &val), "failed to databaseGetValue")
+func (proc *coinProcessor) IsOnDemandMode(ctx context.Context) (val bool) {
+	log.Error(databaseGetValue(ctx, proc.DB, proc.targetKey(configKeyCoinDistributerOnDemand), &val), "failed to databaseGetValue")
 
 	return val
 }
 
-func (d *databaseConfig) DisableOnDemand(ctx context.Context) error {
-	return databaseSetValue(ctx, d.DB, configKeyCoinDistributerOnDemand, false)
+func (proc *coinProcessor) DisableOnDemand(ctx context.Context) error {
+	return databaseSetValue(ctx, proc.DB, proc.targetKey(configKeyCoinDistributerOnDemand), false)
 }
 
-func (d *databaseConfig) Disable(ctx context.Context) error {
-	return databaseSetValue(ctx, d.DB, configKeyCoinDistributerEnabled, false)
+func (proc *coinProcessor) Disable(ctx context.Context) error {
+	return databaseSetValue(ctx, proc.DB, proc.targetKey(configKeyCoinDistributerEnabled), false)
 }
 
-func (d *databaseConfig) HasPendingTransactions(ctx context.Context, status ethApiStatus) bool {
+func (proc *coinProcessor) HasPendingTransactions(ctx context.Context, status ethApiStatus) bool {
 	reqCtx, cancel := context.WithTimeout(ctx, requestDeadline)
 	defer cancel()
 
-	val, err := storage.ExecOne[bool](reqCtx, d.DB, `SELECT true FROM pending_coin_distributions where eth_status = $1 limit 1`, status)
+	val, err := storage.ExecOne[bool](reqCtx, proc.DB,
+		`SELECT true FROM pending_coin_distributions where eth_status = $1 and target = $2 limit 1`, status, proc.Target.Name)
 	if err != nil {
 		if errors.Is(err, storage.ErrNotFound) {
 			err = nil
@@ -136,18 +182,46 @@ func (d *databaseConfig) HasPendingTransactions(ctx context.Context, status ethA
 	return *val
 }
 
+// targetKey returns the `global` key holding the given setting or message date for the target of the processor.
+func (proc *coinProcessor) targetKey(key string) string {
+	if proc.Target.Name == defaultDistributionTarget {
+		return key
+	}
+
+	return key + "_" + proc.Target.Name
+}
+
+// InitTargetGlobals seeds the `global` keys of an additional target with `init_coin_distribution_target_globals`,
+// the default one is seeded by the DDL itself.
+func (proc *coinProcessor) InitTargetGlobals(ctx context.Context) error {
+	if proc.Target.Name == defaultDistributionTarget {
+		return nil
+	}
+
+	reqCtx, cancel := context.WithTimeout(ctx, requestDeadline)
+	defer cancel()
+
+	_, err := storage.Exec(reqCtx, proc.DB, `SELECT init_coin_distribution_target_globals($1)`, proc.Target.Name)
+
+	return errors.Wrapf(err, "failed to init the globals of %v", proc.Target.Name)
+}
+
 func init() {
 	appCfg.MustLoadFromKey(applicationYamlKey, &cfg)
 }
 
 func MustStartCoinDistribution(ctx context.Context, _ context.CancelFunc) Client {
 	cfg.EnsureValid()
-	eth := mustNewEthClient(ctx, cfg.Ethereum.RPC, cfg.Ethereum.PrivateKey, cfg.Ethereum.ContractAddress)
+	eth := mustNewEthClient(ctx, &cfg.Ethereum)
 
 	cd := mustCreateCoinDistributionFromConfig(ctx, &cfg, eth)
+	for _, target := range cfg.AdditionalTargets() {
+		cd.AddTarget(target, mustNewEthClient(ctx, &target.chainConfig))
+	}
 	cd.MustStart(ctx, nil)
 
-	go startPrepareCoinDistributionsForReviewMonitor(ctx, cd.DB)
+	go startPrepareCoinDistributionsForReviewMonitor(ctx, cd.DB, cd.Clients)
+	go cd.startReconciliationMonitor(ctx)
 
 	return cd
 }
@@ -159,22 +233,44 @@ func mustCreateCoinDistributionFromConfig(ctx context.Context, conf *config, eth
 		Processor: newCoinProcessor(ethClient, db, conf),
 		DB:        db,
 	}
+	cd.Processors = append(cd.Processors, cd.Processor)
+	cd.Clients = append(cd.Clients, ethClient)
 
 	return cd
 }
 
+// AddTarget adds a processor distributing the pending coin distributions routed to the given target, with its own controller.
+func (cd *coinDistributer) AddTarget(target *distributionTarget, ethClient ethClient) {
+	cd.Processors = append(cd.Processors, newTargetCoinProcessor(ethClient, cd.DB, target))
+	cd.Clients = append(cd.Clients, ethClient)
+}
+
 func (cd *coinDistributer) MustStart(ctx context.Context, notifyProcessed chan<- *batch) {
-	cd.Processor.Start(ctx, notifyProcessed)
+	for _, proc := range cd.Processors {
+		proc.Start(ctx, notifyProcessed)
+	}
 }
 
 func (cd *coinDistributer) Close() error {
-	return multierror.Append( //nolint:wrapcheck //.
-		errors.Wrap(cd.Processor.Close(), "failed to close processor"),
-		errors.Wrap(cd.Client.Close(), "failed to close eth client"),
-		errors.Wrap(cd.DB.Close(), "failed to close db"),
-	).ErrorOrNil()
+	var mErr *multierror.Error
+	for _, proc := range cd.Processors {
+		mErr = multierror.Append(mErr, errors.Wrapf(proc.Close(), "failed to close processor of %v", proc.Target.Name))
+	}
+	for _, client := range cd.Clients {
+		mErr = multierror.Append(mErr, errors.Wrap(client.Close(), "failed to close eth client"))
+	}
+
+	return multierror.Append(mErr, errors.Wrap(cd.DB.Close(), "failed to close db")).ErrorOrNil() //nolint:wrapcheck //.
 }
 
 func (cd *coinDistributer) CheckHealth(ctx context.Context) error {
+	for _, proc := range cd.Processors {
+		// Reported by the leader only, it's the one that checked them before its last batch.
+		// Low balances are not a health issue, EnsureBalances already pauses the distribution and alerts about them.
+		if status := proc.balances.Load(); status != nil {
+			log.Info(fmt.Sprintf("[health-check] %v: %v", proc.Target.Name, status))
+		}
+	}
+
 	return errors.Wrap(cd.DB.Ping(ctx), "[health-check] failed to ping DB")
 }
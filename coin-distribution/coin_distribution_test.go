@@ -37,16 +37,17 @@ func TestFullCoinDistribution(t *testing.T) { //nolint:paralleltest,funlen //.
 		t.Skip("skip full coin distribution test")
 	}
 
-	cl := mustNewEthClient(context.TODO(), rpc, privateKey, contractAddr)
-	require.NotNil(t, cl)
-	defer cl.Close()
-
 	conf := new(config)
+	conf.Development = true
 	conf.Ethereum.ContractAddress = contractAddr
 	conf.Ethereum.ChainID = 97
 	conf.Ethereum.RPC = rpc
 	conf.Ethereum.PrivateKey = privateKey
 
+	cl := mustNewEthClient(context.TODO(), &conf.Ethereum)
+	require.NotNil(t, cl)
+	defer cl.Close()
+
 	t.Run("AddPendingEntry", func(t *testing.T) {
 		db := storage.MustConnect(context.TODO(), ddl, applicationYamlKey)
 		defer db.Close()
@@ -201,3 +202,32 @@ func TestCoinDistributionWaitFailed(t *testing.T) { //nolint:paralleltest,funlen
 	require.Equal(t, testTxFailed, *processedBatch.Records[0].EthTX)
 	require.False(t, cd.Processor.IsEnabled(context.TODO()))
 }
+
+func TestTargetGlobals(t *testing.T) { //nolint:paralleltest //.
+	maybeSkipTest(t)
+
+	ctx := context.TODO()
+	db := storage.MustConnect(ctx, ddl, applicationYamlKey)
+	defer db.Close()
+
+	proc := newCoinProcessor(new(mockedDummyEthClient), db, new(config))
+	bsc := newTargetCoinProcessor(new(mockedDummyEthClient), db, &distributionTarget{Name: "bsc"})
+	require.NoError(t, bsc.InitTargetGlobals(ctx))
+	require.NoError(t, databaseSetValue(ctx, db, configKeyCoinDistributerEnabled, true))
+	require.NoError(t, databaseSetValue(ctx, db, configKeyCoinDistributerConfirmations, 12))
+	require.NoError(t, databaseSetValue(ctx, db, bsc.targetKey(configKeyCoinDistributerConfirmations), 3))
+
+	depth, err := bsc.GetConfirmationBlocks(ctx)
+	require.NoError(t, err)
+	require.EqualValues(t, 3, depth)
+	depth, err = proc.GetConfirmationBlocks(ctx)
+	require.NoError(t, err)
+	require.EqualValues(t, 12, depth)
+
+	require.False(t, bsc.IsEnabled(ctx))
+	require.True(t, proc.IsEnabled(ctx))
+	require.NoError(t, databaseSetValue(ctx, db, bsc.targetKey(configKeyCoinDistributerEnabled), true))
+	require.NoError(t, bsc.Disable(ctx))
+	require.False(t, bsc.IsEnabled(ctx))
+	require.True(t, proc.IsEnabled(ctx), "disabling a target leaves the others running")
+}
@@ -3,6 +3,10 @@
 package coindistribution
 
 import (
+	"fmt"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/pkg/errors"
 
@@ -10,19 +14,106 @@ import (
 )
 
 func (cfg *config) EnsureValid() {
-	if cfg.Ethereum.ChainID == 0 {
-		log.Panic("ethereum.chainID must be > 0")
+	cfg.Ethereum.ensureValid("ethereum", cfg.Development)
+	for name, target := range cfg.Targets {
+		if name == defaultDistributionTarget {
+			log.Panic(fmt.Sprintf("targets.%v is reserved for the default target, use `ethereum` instead", name))
+		}
+		if target == nil {
+			log.Panic(fmt.Sprintf("targets.%v must not be empty", name))
+		}
+		target.ensureValid("targets."+name, cfg.Development)
+		for _, v := range []int{target.StartHours, target.EndHours} {
+			if v < 0 || v > 23 {
+				log.Panic(fmt.Sprintf("targets.%v has invalid hour: %v", name, v))
+			}
+		}
+	}
+}
+
+func (c *chainConfig) ensureValid(prefix string, development bool) {
+	if c.ChainID == 0 {
+		log.Panic(prefix + ".chainID must be > 0")
+	}
+	if c.RPC == "" {
+		log.Panic(prefix + ".rpc must not be empty")
+	}
+	if c.ContractAddress == "" {
+		log.Panic(prefix + ".contractAddress must not be empty")
 	}
-	if cfg.Ethereum.RPC == "" {
-		log.Panic("ethereum.rpc must not be empty")
+
+	switch c.Mode {
+	case "", distributionModeAirdrop:
+	case distributionModeMerkle:
+		if !common.IsHexAddress(c.MerkleRootsContractAddress) {
+			log.Panic(prefix + ".merkleRootsContractAddress must be a valid address in the merkle mode")
+		}
+	default:
+		log.Panic(fmt.Sprintf("%v.mode must be `%v` or `%v`, got %q", prefix, distributionModeAirdrop, distributionModeMerkle, c.Mode))
+	}
+
+	signers := 0
+	for _, configured := range []bool{c.PrivateKey != "", c.Signer.Keystore.File != "", c.Signer.Remote.URL != ""} {
+		if configured {
+			signers++
+		}
 	}
-	if cfg.Ethereum.PrivateKey == "" {
-		log.Panic("ethereum.privateKey must not be empty")
+	if signers != 1 {
+		log.Panic(prefix + ": exactly one of privateKey, signer.keystore or signer.remote must be set")
 	}
-	_, err := crypto.HexToECDSA(cfg.Ethereum.PrivateKey)
-	log.Panic(errors.Wrap(err, "ethereum.privateKey is invalid")) //nolint:revive,nolintlint //.
 
-	if cfg.Ethereum.ContractAddress == "" {
-		log.Panic("ethereum.contractAddress must not be empty")
+	switch {
+	case c.PrivateKey != "":
+		if !development {
+			log.Panic(prefix + ".privateKey is allowed only in development, use signer.keystore or signer.remote instead")
+		}
+		_, err := crypto.HexToECDSA(c.PrivateKey)
+		log.Panic(errors.Wrap(err, prefix+".privateKey is invalid")) //nolint:revive,nolintlint //.
+
+	case c.Signer.Keystore.File != "":
+		if c.Signer.Keystore.PassphraseEnv == "" {
+			log.Panic(prefix + ".signer.keystore.passphraseEnv must not be empty")
+		}
+
+	case c.Signer.Remote.URL != "":
+		if !common.IsHexAddress(c.Signer.Remote.Address) {
+			log.Panic(prefix + ".signer.remote.address must be a valid address")
+		}
 	}
 }
+
+// DefaultTarget returns the `ethereum` target, with the top level time window.
+func (cfg *config) DefaultTarget() *distributionTarget {
+	return &distributionTarget{
+		Name:        defaultDistributionTarget,
+		chainConfig: cfg.Ethereum,
+		StartHours:  cfg.StartHours,
+		EndHours:    cfg.EndHours,
+	}
+}
+
+// Target returns the target with the given name, nil if it's not configured.
+func (cfg *config) Target(name string) *distributionTarget {
+	if name == defaultDistributionTarget {
+		return cfg.DefaultTarget()
+	}
+	for _, target := range cfg.AdditionalTargets() {
+		if target.Name == name {
+			return target
+		}
+	}
+
+	return nil
+}
+
+// AdditionalTargets returns the configured targets besides the default one, with their names set.
+func (cfg *config) AdditionalTargets() []*distributionTarget {
+	targets := make([]*distributionTarget, 0, len(cfg.Targets))
+	for name, target := range cfg.Targets {
+		target.Name = name
+		targets = append(targets, target)
+	}
+	sort.Slice(targets, func(i, j int) bool { return targets[i].Name < targets[j].Name })
+
+	return targets
+}
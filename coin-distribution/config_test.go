@@ -0,0 +1,115 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package coindistribution
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigTargets(t *testing.T) {
+	t.Parallel()
+
+	const (
+		privateKey = "4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318"
+		contract   = "0x0000000000000000000000000000000000000001"
+	)
+	chain := func(rpc string, chainID int64) chainConfig {
+		return chainConfig{RPC: rpc, PrivateKey: privateKey, ContractAddress: contract, ChainID: chainID}
+	}
+	conf := &config{
+		Development: true,
+		Ethereum:    chain("https://ethereum", 1),
+		StartHours:  10,
+		EndHours:    12,
+		Targets: map[string]*distributionTarget{
+			"testnet": {chainConfig: chain("https://testnet", 97), StartHours: 1, EndHours: 2},
+			"bsc":     {chainConfig: chain("https://bsc", 56)},
+		},
+	}
+	require.NotPanics(t, conf.EnsureValid)
+
+	def := conf.DefaultTarget()
+	assert.Equal(t, defaultDistributionTarget, def.Name)
+	assert.Equal(t, "https://ethereum", def.RPC)
+	assert.Equal(t, 10, def.StartHours)
+	assert.Equal(t, 12, def.EndHours)
+
+	targets := conf.AdditionalTargets()
+	require.Len(t, targets, 2)
+	assert.Equal(t, "bsc", targets[0].Name)
+	assert.EqualValues(t, 56, targets[0].ChainID)
+	assert.Equal(t, "testnet", targets[1].Name)
+	assert.Equal(t, 1, targets[1].StartHours)
+	assert.Equal(t, "https://testnet", conf.Target("testnet").RPC)
+	assert.Equal(t, "https://ethereum", conf.Target(defaultDistributionTarget).RPC)
+	assert.Nil(t, conf.Target("polygon"))
+
+	conf.Targets["bsc"].EndHours = 24
+	require.Panics(t, conf.EnsureValid)
+
+	conf.Targets["bsc"].EndHours = 0
+	conf.Targets[defaultDistributionTarget] = &distributionTarget{chainConfig: chain("https://mainnet", 1)}
+	require.Panics(t, conf.EnsureValid)
+}
+
+func TestProcessorTargetKey(t *testing.T) {
+	t.Parallel()
+
+	proc := newCoinProcessor(new(mockedDummyEthClient), nil, new(config))
+	assert.Equal(t, configKeyCoinDistributerOnDemand, proc.targetKey(configKeyCoinDistributerOnDemand))
+
+	proc = newTargetCoinProcessor(new(mockedDummyEthClient), nil, &distributionTarget{Name: "bsc"})
+	assert.Equal(t, configKeyCoinDistributerOnDemand+"_bsc", proc.targetKey(configKeyCoinDistributerOnDemand))
+}
+
+func TestChainConfigSigner(t *testing.T) {
+	t.Parallel()
+
+	chain := func() *chainConfig {
+		return &chainConfig{RPC: "https://ethereum", ContractAddress: "0x0000000000000000000000000000000000000001", ChainID: 1}
+	}
+
+	raw := chain()
+	raw.PrivateKey = "4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318"
+	require.NotPanics(t, func() { raw.ensureValid("ethereum", true) })
+	require.Panics(t, func() { raw.ensureValid("ethereum", false) })
+
+	keystoreFile := chain()
+	keystoreFile.Signer.Keystore.File = "/secrets/keystore.json"
+	require.Panics(t, func() { keystoreFile.ensureValid("ethereum", false) })
+	keystoreFile.Signer.Keystore.PassphraseEnv = "COIN_DISTRIBUTION_KEYSTORE_PASSPHRASE"
+	require.NotPanics(t, func() { keystoreFile.ensureValid("ethereum", false) })
+
+	remote := chain()
+	remote.Signer.Remote.URL = "http://clef:8550"
+	require.Panics(t, func() { remote.ensureValid("ethereum", false) })
+	remote.Signer.Remote.Address = "0x4B73C58370AEfcEf86A6021afCDe5673511376B2"
+	require.NotPanics(t, func() { remote.ensureValid("ethereum", false) })
+
+	remote.Signer.Keystore = keystoreFile.Signer.Keystore
+	require.Panics(t, func() { remote.ensureValid("ethereum", false) })
+	require.Panics(t, func() { chain().ensureValid("ethereum", true) })
+}
+
+func TestChainConfigMode(t *testing.T) {
+	t.Parallel()
+
+	chain := &chainConfig{
+		RPC:             "https://ethereum",
+		ContractAddress: "0x0000000000000000000000000000000000000001",
+		PrivateKey:      "4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318",
+		ChainID:         1,
+	}
+	require.NotPanics(t, func() { chain.ensureValid("ethereum", true) })
+
+	chain.Mode = distributionModeMerkle
+	require.Panics(t, func() { chain.ensureValid("ethereum", true) })
+	chain.MerkleRootsContractAddress = "0x0000000000000000000000000000000000000002"
+	require.NotPanics(t, func() { chain.ensureValid("ethereum", true) })
+
+	chain.Mode = "push"
+	require.Panics(t, func() { chain.ensureValid("ethereum", true) })
+}
@@ -10,13 +10,17 @@ import (
 	"io"
 	"math/big"
 	"sync"
+	"sync/atomic"
 	stdlibtime "time"
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
 
+	coindistribution "github.com/ice-blockchain/freezer/coin-distribution/internal"
 	"github.com/ice-blockchain/wintr/connectors/storage/v2"
 	"github.com/ice-blockchain/wintr/time"
 )
@@ -32,11 +36,21 @@ type (
 	Repository interface {
 		io.Closer
 		GetCoinDistributionsForReview(ctx context.Context, arg *GetCoinDistributionsForReviewArg) (*CoinDistributionsForReview, error)
+		GetCoinDistributionsReviewDiff(ctx context.Context, arg *GetCoinDistributionsReviewDiffArg) (*CoinDistributionsReviewDiff, error)
 		CheckHealth(ctx context.Context) error
-		ReviewCoinDistributions(ctx context.Context, reviewerUserID string, decision string) error
+		ReviewCoinDistributions(ctx context.Context, reviewerUserID string, arg *ReviewCoinDistributionsArg) (*CoinDistributionsReviewVotes, error)
 		NotifyCoinDistributionCollectionCycleEnded(ctx context.Context) error
 		GetCollectorSettings(ctx context.Context) (*CollectorSettings, error)
 		CollectCoinDistributionsForReview(ctx context.Context, records []*ByEarnerForReview) error
+		GetCoinDistributionPayouts(ctx context.Context, arg *GetCoinDistributionPayoutsArg) (*CoinDistributionPayouts, error)
+		// ExportCoinDistributions streams all the coin distributions of the source matching the filter to w, page by page.
+		ExportCoinDistributions(ctx context.Context, arg *ExportCoinDistributionsArg, w io.Writer) error
+		GetCoinDistributionMerkleProofs(ctx context.Context, arg *GetCoinDistributionMerkleProofsArg) (*CoinDistributionMerkleProofs, error)
+		GetRejectedCoinDistributions(ctx context.Context, arg *GetRejectedCoinDistributionsArg) (*RejectedCoinDistributions, error)
+		ResolveRejectedCoinDistributions(ctx context.Context, operatorUserID string, arg *ResolveRejectedCoinDistributionsArg) error
+		GetCoinDistributionHolds(ctx context.Context, arg *GetCoinDistributionHoldsArg) (*CoinDistributionHolds, error)
+		HoldCoinDistributions(ctx context.Context, reviewerUserID string, arg *HoldCoinDistributionsArg) (*HeldCoinDistributions, error)
+		ReleaseCoinDistributionHolds(ctx context.Context, reviewerUserID string, arg *ReleaseCoinDistributionHoldsArg) error
 	}
 	CollectorSettings struct {
 		DeniedCountries          map[string]struct{}
@@ -55,6 +69,36 @@ type (
 		Cursor        uint64           `json:"cursor" example:"5065"`
 		TotalRows     uint64           `json:"totalRows" example:"5065"`
 		TotalIce      float64          `json:"totalIce" example:"5065.3"`
+		// BudgetBreaches are the budget guards the whole cycle breaches, it can't be approved without overriding them.
+		BudgetBreaches []*BudgetBreach `json:"budgetBreaches,omitempty"`
+		// Snapshot identifies the distributions matching the filter, the approvals are counted per snapshot.
+		Snapshot string `json:"snapshot" example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"`
+		// Votes are all the open votes, of any snapshot.
+		Votes  []*CoinDistributionsReviewVote `json:"votes"`
+		Quorum uint64                         `json:"quorum" example:"2"`
+	}
+
+	CoinDistributionsReviewVotes struct {
+		Snapshot string                         `json:"snapshot" example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"`
+		Votes    []*CoinDistributionsReviewVote `json:"votes"`
+		Quorum   uint64                         `json:"quorum" example:"2"`
+		// Executed is false while the snapshot is waiting for more approvals.
+		Executed bool `json:"executed" example:"false"`
+	}
+
+	CoinDistributionsReviewVote struct {
+		CreatedAt      *time.Time `json:"createdAt" db:"created_at" swaggertype:"string" example:"2022-01-03T16:20:52.156534Z"`
+		Snapshot       string     `json:"snapshot" db:"snapshot" example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"`
+		ReviewerUserID string     `json:"reviewerUserId" db:"reviewer_user_id" example:"12746386-03de-44d7-91c7-856fa66b6ed6"`
+		Decision       string     `json:"decision" db:"decision" example:"approve"`
+		Filter         string     `json:"filter" db:"filter" example:"minIce=10.00"`
+	}
+
+	BudgetBreach struct {
+		Guard  string  `json:"guard" example:"maxUserIce" enums:"maxCycleIce,maxUserIce,maxCycleIncreasePercent"`
+		Limit  float64 `json:"limit" example:"1000"`
+		Actual float64 `json:"actual" example:"5065.3"`
+		Users  uint64  `json:"users,omitempty" example:"3"`
 	}
 
 	GetCoinDistributionsForReviewArg struct {
@@ -62,10 +106,25 @@ type (
 		IceOrderBy                string `form:"iceOrderBy" example:"asc"`
 		UsernameOrderBy           string `form:"usernameOrderBy" example:"asc"`
 		ReferredByUsernameOrderBy string `form:"referredByUsernameOrderBy" example:"asc"`
-		UsernameKeyword           string `form:"usernameKeyword" example:"jdoe"`
-		ReferredByUsernameKeyword string `form:"referredByUsernameKeyword" example:"jdoe"`
-		Cursor                    uint64 `form:"cursor" example:"5065"`
-		Limit                     uint64 `form:"limit" example:"5000"`
+		CoinDistributionsForReviewFilter
+		Cursor uint64 `form:"cursor" example:"5065"`
+		Limit  uint64 `form:"limit" example:"5000"`
+	}
+
+	ReviewCoinDistributionsArg struct {
+		Decision string `form:"decision" required:"true" swaggerignore:"true" enums:"approve,approve-and-process-immediately,deny"`
+		CoinDistributionsForReviewFilter
+		OverrideBudgetGuards bool `form:"overrideBudgetGuards" swaggerignore:"true"`
+		// Snapshot is the one the reviewer has seen, if set, the vote is rejected if the distributions changed since.
+		Snapshot string `form:"snapshot" swaggerignore:"true"`
+	}
+
+	CoinDistributionsForReviewFilter struct {
+		UsernameKeyword           string   `form:"usernameKeyword" example:"jdoe"`
+		ReferredByUsernameKeyword string   `form:"referredByUsernameKeyword" example:"jdoe"`
+		UserIDs                   []string `form:"userIds" example:"12746386-03de-44d7-91c7-856fa66b6ed6"`
+		MinIce                    float64  `form:"minIce" example:"10.5"`
+		MaxIce                    float64  `form:"maxIce" example:"1000"`
 	}
 
 	PendingReview struct {
@@ -75,8 +134,212 @@ type (
 		ReferredByUsername string     `json:"referredByUsername" swaggertype:"string" example:"myrefusername"`
 		UserID             string     `json:"userId" swaggertype:"string" example:"12746386-03de-44d7-91c7-856fa66b6ed6"`
 		EthAddress         string     `json:"ethAddress" swaggertype:"string" example:"0x43...."`
+		Country            string     `json:"country" swaggertype:"string" example:"US"`
 		Ice                float64    `json:"ice" db:"-" example:"1000"`
 		IceInternal        int64      `json:"-" db:"ice" swaggerignore:"true"`
+		ExceedsMaxUserIce  bool       `json:"exceedsMaxUserIce,omitempty" db:"-" example:"true"`
+		// HeldIce is the part of ice that was on hold and is carried over from a previous cycle.
+		HeldIce         float64 `json:"heldIce,omitempty" db:"-" example:"100"`
+		HeldIceInternal int64   `json:"-" db:"held_ice" swaggerignore:"true"`
+	}
+
+	GetCoinDistributionHoldsArg struct {
+		IncludeReleased bool   `form:"includeReleased" example:"false"`
+		Cursor          uint64 `form:"cursor" example:"5065"`
+		Limit           uint64 `form:"limit" example:"5000"`
+	}
+
+	CoinDistributionHolds struct {
+		Holds  []*CoinDistributionHold `json:"holds"`
+		Cursor uint64                  `json:"cursor" example:"5065"`
+	}
+
+	CoinDistributionHold struct {
+		CreatedAt        *time.Time `json:"createdAt" db:"created_at" swaggertype:"string" example:"2022-01-03T16:20:52.156534Z"`
+		ReleasedAt       *time.Time `json:"releasedAt,omitempty" db:"released_at" swaggertype:"string" example:"2022-01-03T16:20:52.156534Z"`
+		UserID           string     `json:"userId" db:"user_id" example:"12746386-03de-44d7-91c7-856fa66b6ed6"`
+		ReviewerUserID   string     `json:"reviewerUserId" db:"reviewer_user_id" example:"12746386-03de-44d7-91c7-856fa66b6ed6"`
+		ReleasedByUserID string     `json:"releasedByUserId,omitempty" db:"released_by_user_id" example:"system"`
+		Reason           string     `json:"reason" db:"reason" example:"suspected multi-accounting"`
+		// HeldIce is waiting to be carried into the next cycle, once the hold is released.
+		HeldIce         float64 `json:"heldIce" db:"-" example:"100"`
+		HeldIceInternal int64   `json:"-" db:"held_ice" swaggerignore:"true"`
+		// UntilReleased holds last until they're released manually, the others are released by the next cycle.
+		UntilReleased bool `json:"untilReleased" db:"until_released" example:"true"`
+	}
+
+	HoldCoinDistributionsArg struct {
+		UserIDs       []string `form:"userIds" required:"true" swaggerignore:"true"`
+		Reason        string   `form:"reason" swaggerignore:"true"`
+		UntilReleased bool     `form:"untilReleased" swaggerignore:"true"`
+	}
+
+	HeldCoinDistributions struct {
+		Rows uint64  `json:"rows" example:"3"`
+		Ice  float64 `json:"ice" example:"1000"`
+	}
+
+	ReleaseCoinDistributionHoldsArg struct {
+		UserIDs []string `form:"userIds" required:"true" swaggerignore:"true"`
+	}
+
+	GetCoinDistributionsReviewDiffArg struct {
+		// JumpPercent is the increase, compared to the previous cycle, above which an user is reported in `amountJumps`.
+		JumpPercent float64 `form:"jumpPercent" example:"100"`
+		// Limit is applied to each section.
+		Limit uint64 `form:"limit" example:"100"`
+	}
+
+	// CoinDistributionsReviewDiff compares the coin distributions pending review with the ones approved in the previous cycle.
+	CoinDistributionsReviewDiff struct {
+		PreviousReviewDay    string                                         `json:"previousReviewDay" example:"2022-01-03"`
+		NewEthAddresses      []*CoinDistributionsReviewDiffRecord           `json:"newEthAddresses"`
+		ChangedEthAddresses  []*CoinDistributionsReviewDiffRecord           `json:"changedEthAddresses"`
+		AmountJumps          []*CoinDistributionsReviewDiffRecord           `json:"amountJumps"`
+		SharedEthAddresses   []*CoinDistributionsReviewDiffSharedEthAddress `json:"sharedEthAddresses"`
+		ByCountry            []*CoinDistributionsReviewDiffAggregate        `json:"byCountry"`
+		ByReferredByUsername []*CoinDistributionsReviewDiffAggregate        `json:"byReferredByUsername"`
+	}
+
+	CoinDistributionsReviewDiffRecord struct {
+		UserID              string  `json:"userId" db:"user_id" example:"12746386-03de-44d7-91c7-856fa66b6ed6"`
+		Username            string  `json:"username" db:"username" example:"myusername"`
+		EthAddress          string  `json:"ethAddress" db:"eth_address" example:"0x43...."`
+		PreviousEthAddress  string  `json:"previousEthAddress,omitempty" db:"previous_eth_address" example:"0x12...."`
+		Ice                 float64 `json:"ice" db:"-" example:"1000"`
+		PreviousIce         float64 `json:"previousIce" db:"-" example:"400"`
+		IncreasePercent     float64 `json:"increasePercent,omitempty" db:"-" example:"150"`
+		IceInternal         int64   `json:"-" db:"ice" swaggerignore:"true"`
+		PreviousIceInternal int64   `json:"-" db:"previous_ice" swaggerignore:"true"`
+	}
+
+	CoinDistributionsReviewDiffSharedEthAddress struct {
+		EthAddress  string   `json:"ethAddress" db:"eth_address" example:"0x43...."`
+		UserIDs     []string `json:"userIds" db:"user_ids" example:"12746386-03de-44d7-91c7-856fa66b6ed6"`
+		Users       uint64   `json:"users" db:"users" example:"3"`
+		Ice         float64  `json:"ice" db:"-" example:"1000"`
+		IceInternal int64    `json:"-" db:"ice" swaggerignore:"true"`
+	}
+
+	CoinDistributionsReviewDiffAggregate struct {
+		Key                 string  `json:"key" db:"key" example:"US"`
+		Users               uint64  `json:"users" db:"users" example:"30"`
+		PreviousUsers       uint64  `json:"previousUsers" db:"previous_users" example:"25"`
+		Ice                 float64 `json:"ice" db:"-" example:"1000"`
+		PreviousIce         float64 `json:"previousIce" db:"-" example:"400"`
+		IceInternal         int64   `json:"-" db:"ice" swaggerignore:"true"`
+		PreviousIceInternal int64   `json:"-" db:"previous_ice" swaggerignore:"true"`
+	}
+
+	ExportCoinDistributionsArg struct {
+		Source string `form:"source" required:"true" swaggerignore:"true" enums:"pendingReview,reviewed,settled"`
+		Format string `form:"format" swaggerignore:"true" enums:"csv,parquet"`
+		CoinDistributionsForReviewFilter
+	}
+
+	// ExportedCoinDistribution is a row of an export, the columns that don't apply to its source are empty.
+	ExportedCoinDistribution struct {
+		SortDay            string  `db:"sort_day" parquet:"-"`
+		Day                string  `db:"day" parquet:"day"`
+		ReviewDay          string  `db:"review_day" parquet:"review_day"`
+		UserID             string  `db:"user_id" parquet:"user_id"`
+		Username           string  `db:"username" parquet:"username"`
+		ReferredByUsername string  `db:"referred_by_username" parquet:"referred_by_username"`
+		Country            string  `db:"country" parquet:"country"`
+		EthAddress         string  `db:"eth_address" parquet:"eth_address"`
+		Iceflakes          string  `db:"iceflakes" parquet:"iceflakes"`
+		CreatedAt          string  `db:"created_at" parquet:"created_at"`
+		ReviewedAt         string  `db:"reviewed_at" parquet:"reviewed_at"`
+		ReviewerUserID     string  `db:"reviewer_user_id" parquet:"reviewer_user_id"`
+		Decision           string  `db:"decision" parquet:"decision"`
+		ScreeningReason    string  `db:"screening_reason" parquet:"screening_reason"`
+		SettledAt          string  `db:"settled_at" parquet:"settled_at"`
+		TxHash             string  `db:"eth_tx" parquet:"tx_hash"`
+		Target             string  `db:"target" parquet:"target"`
+		Ice                float64 `db:"-" parquet:"ice"`
+		IceInternal        int64   `db:"ice" parquet:"-"`
+	}
+
+	GetCoinDistributionPayoutsArg struct {
+		UserID     string `form:"userId" example:"12746386-03de-44d7-91c7-856fa66b6ed6"`
+		EthAddress string `form:"ethAddress" example:"0x43...."`
+		Cursor     uint64 `form:"cursor" example:"5065"`
+		Limit      uint64 `form:"limit" example:"5000"`
+	}
+
+	CoinDistributionPayouts struct {
+		Payouts []*CoinDistributionPayout `json:"payouts"`
+		Cursor  uint64                    `json:"cursor" example:"5065"`
+	}
+
+	CoinDistributionPayout struct {
+		SettledAt         *time.Time `json:"settledAt" swaggertype:"string" example:"2022-01-03T16:20:52.156534Z"`
+		CreatedAt         *time.Time `json:"time" swaggertype:"string" example:"2022-01-03T16:20:52.156534Z"`
+		Day               *time.Time `json:"day" swaggertype:"string" example:"2022-01-03T00:00:00Z"`
+		Iceflakes         string     `json:"iceflakes" swaggertype:"string" example:"100000000000000"`
+		UserID            string     `json:"userId" swaggertype:"string" example:"12746386-03de-44d7-91c7-856fa66b6ed6"`
+		EthAddress        string     `json:"ethAddress" swaggertype:"string" example:"0x43...."`
+		TxHash            string     `json:"txHash" db:"eth_tx" swaggertype:"string" example:"0x5c50...."`
+		BatchID           string     `json:"batchId" swaggertype:"string" example:"01HN7W4RQ0JXZ8K3GSM4YJ1D2V"`
+		Target            string     `json:"target" swaggertype:"string" example:"ethereum"`
+		EffectiveGasPrice string     `json:"effectiveGasPrice" db:"eth_effective_gas_price" swaggertype:"string" example:"3000000000"`
+		BlockNumber       uint64     `json:"blockNumber" db:"eth_block_number" example:"35800000"`
+		BlockHash         string     `json:"blockHash" db:"eth_block_hash" swaggertype:"string" example:"0x8f1e...."`
+		GasUsed           uint64     `json:"gasUsed" db:"eth_gas_used" example:"21000000"`
+		InternalID        int64      `json:"-" swaggerignore:"true"`
+	}
+
+	GetCoinDistributionMerkleProofsArg struct {
+		UserID string `uri:"userId" required:"true" swaggerignore:"true" example:"12746386-03de-44d7-91c7-856fa66b6ed6"`
+	}
+
+	CoinDistributionMerkleProofs struct {
+		Proofs []*CoinDistributionMerkleProof `json:"proofs"`
+	}
+
+	// CoinDistributionMerkleProof is what an user needs to claim the coins of a cycle published in the Merkle-claim mode.
+	CoinDistributionMerkleProof struct {
+		PublishedAt     *time.Time `json:"publishedAt" swaggertype:"string" example:"2022-01-03T16:20:52.156534Z"`
+		Root            string     `json:"root" swaggertype:"string" example:"0x9f2c...."`
+		ContractAddress string     `json:"contractAddress" swaggertype:"string" example:"0x43...."`
+		Target          string     `json:"target" swaggertype:"string" example:"ethereum"`
+		TxHash          string     `json:"txHash" db:"eth_tx" swaggertype:"string" example:"0x5c50...."`
+		EthAddress      string     `json:"ethAddress" swaggertype:"string" example:"0x43...."`
+		Iceflakes       string     `json:"iceflakes" swaggertype:"string" example:"100000000000000"`
+		Proof           []string   `json:"proof" example:"0x1b2c....,0x3d4e...."`
+		Cycle           uint64     `json:"cycle" example:"1"`
+	}
+
+	GetRejectedCoinDistributionsArg struct {
+		Target string `form:"target" example:"ethereum"`
+		Cursor uint64 `form:"cursor" example:"5065"`
+		Limit  uint64 `form:"limit" example:"5000"`
+	}
+
+	RejectedCoinDistributions struct {
+		Batches []*RejectedCoinDistributionBatch `json:"batches"`
+		Cursor  uint64                           `json:"cursor" example:"5065"`
+	}
+
+	// RejectedCoinDistributionBatch groups the rejected coin distributions of the same TX (or batch, if it was never sent).
+	RejectedCoinDistributionBatch struct {
+		RejectedAt  *time.Time `json:"rejectedAt" db:"rejected_at" swaggertype:"string" example:"2022-01-03T16:20:52.156534Z"`
+		BatchID     string     `json:"batchId" db:"batch_id" swaggertype:"string" example:"01HN7W4RQ0JXZ8K3GSM4YJ1D2V"`
+		TxHash      string     `json:"txHash" db:"tx_hash" swaggertype:"string" example:"0x5c50...."`
+		Target      string     `json:"target" swaggertype:"string" example:"ethereum"`
+		Error       string     `json:"error" swaggertype:"string" example:"transaction 0x5c50.... failed"`
+		Iceflakes   string     `json:"iceflakes" swaggertype:"string" example:"100000000000000"`
+		ReplacedTXs []string   `json:"replacedTxs" db:"replaced_txs" example:"0x1b2c...."`
+		UserIDs     []string   `json:"userIds" db:"user_ids" example:"12746386-03de-44d7-91c7-856fa66b6ed6"`
+		Records     uint64     `json:"records" example:"700"`
+		Nonce       *int64     `json:"-" db:"nonce"`
+	}
+
+	ResolveRejectedCoinDistributionsArg struct {
+		Decision string `form:"decision" required:"true" swaggerignore:"true" enums:"requeue,fail"`
+		Target   string `form:"target" required:"true" example:"ethereum"`
+		BatchID  string `form:"batchId" example:"01HN7W4RQ0JXZ8K3GSM4YJ1D2V"`
+		TxHash   string `form:"txHash" example:"0x5c50...."`
 	}
 
 	ByEarnerForReview struct {
@@ -86,21 +349,77 @@ type (
 		UserID             string
 		EarnerUserID       string
 		EthAddress         string
+		Country            string
 		InternalID         int64
 		Balance            float64
 	}
 )
 
+var (
+	ErrNotFound = errors.New("not found")
+	// ErrRequeueNotAllowed is returned if any TX of the rejected coin distributions was mined successfully
+	// or none of them is known to have failed on-chain, so requeueing them could pay the users twice.
+	ErrRequeueNotAllowed = errors.New("requeue not allowed")
+	// ErrBudgetGuardsBreached is returned if the cycle breaches any budget guard and the approval doesn't override them.
+	ErrBudgetGuardsBreached = errors.New("budget guards breached")
+	// ErrReviewSnapshotChanged is returned if the coin distributions changed since the reviewer has seen them.
+	ErrReviewSnapshotChanged = errors.New("review snapshot changed")
+	// ErrScreeningInProgress is returned on approvals while the eth addresses of the coin distributions are still being screened.
+	ErrScreeningInProgress = errors.New("screening in progress")
+)
+
 // Private API.
 
 const (
 	applicationYamlKey = "coin-distribution"
 	requestDeadline    = 25 * stdlibtime.Second
 
-	batchSize = 700
+	maxBatchSize = 700
+
+	gasEstimateBuffer = 10 // Percent on top of the estimate we use as the gas limit of an airdrop TX.
+
+	defaultDistributionTarget = "ethereum"
+
+	reconciliationInterval       = stdlibtime.Hour
+	reconciliationBlocksPerQuery = 5_000
+
+	reconciliationStatusMissing    reconciliationStatus = "missing"
+	reconciliationStatusDuplicate  reconciliationStatus = "duplicate"
+	reconciliationStatusMismatched reconciliationStatus = "mismatched"
+
+	distributionModeAirdrop distributionMode = "airdrop"
+	distributionModeMerkle  distributionMode = "merkle"
+
+	maxMerkleTreeRecords  = 1_000_000
+	merkleProofsPerInsert = 5_000
+
+	rejectedDecisionRequeue = "requeue"
+	rejectedDecisionFail    = "fail"
+
+	screeningReasonContractAddress = "contract-address"
+	screeningAddressesPerRequest   = 500
+	screeningMaxAttempts           = 10
 
 	gasPriceCacheTTL = stdlibtime.Minute
 
+	exportPageSize            = 5_000
+	exportSourcePendingReview = "pendingReview"
+	exportSourceReviewed      = "reviewed"
+	exportSourceSettled       = "settled"
+	exportFormatCSV           = "csv"
+	exportFormatParquet       = "parquet"
+
+	// leaderLeaseTTL is how long the leader keeps the lease without renewing it, the controller ticks every minute.
+	leaderLeaseTTL = 3 * stdlibtime.Minute
+
+	transactionStatusPollInterval      = 3 * stdlibtime.Second
+	transactionReplacementGasPriceBump = 20 // Percent, nodes require at least 10% to accept a replacement.
+
+	feeHistoryBlocks           = 20
+	feeHistoryRewardPercentile = 50
+	baseFeeMultiplier          = 2 // The max fee per gas survives 6 full blocks in a row.
+	maxTransactionReplacements = 10
+
 	workerActionRun      workerAction = 0
 	workerActionBlocked  workerAction = 1
 	workerActionDisabled workerAction = 2
@@ -110,18 +429,52 @@ const (
 	ethApiStatusPending  ethApiStatus = "PENDING"
 	ethApiStatusAccepted ethApiStatus = "ACCEPTED"
 	ethApiStatusRejected ethApiStatus = "REJECTED"
+	// ethApiStatusReverify is for the accepted TXs whose block was reorged out, they're tracked until mined again.
+	ethApiStatusReverify ethApiStatus = "REVERIFY"
+
+	confirmationStatePending    confirmationState = 0 // Not mined yet.
+	confirmationStateConfirming confirmationState = 1 // Mined, but not deep enough yet.
+	confirmationStateConfirmed  confirmationState = 2 // Mined deep enough and still in the same block.
+	confirmationStateReorged    confirmationState = 3 // Its block was reorged out.
 
 	ethTxStatusSuccessful ethTxStatus = "SUCCESSFUL"
 	ethTxStatusFailed     ethTxStatus = "FAILED"
 	ethTxStatusPending    ethTxStatus = "PENDING"
 
-	configKeyCoinDistributerEnabled     = "coin_distributer_enabled"
-	configKeyCoinDistributerOnDemand    = "coin_distributer_forced_execution"
-	configKeyCoinDistributerGasLimit    = "coin_distributer_gas_limit_units"
-	configKeyCoinDistributerGasPrice    = "coin_distributer_gas_price_override"
-	configKeyCoinDistributerMsgOnline   = "coin_distributer_msg_sent_online_date"
-	configKeyCoinDistributerMsgOffline  = "coin_distributer_msg_sent_offline_date"
-	configKeyCoinDistributerMsgFinished = "coin_distributer_msg_sent_finished_date"
+	configKeyCoinDistributerEnabled       = "coin_distributer_enabled"
+	configKeyCoinDistributerOnDemand      = "coin_distributer_forced_execution"
+	configKeyCoinDistributerGasLimit      = "coin_distributer_gas_limit_units"
+	configKeyCoinDistributerGasPrice      = "coin_distributer_gas_price_override"
+	configKeyCoinDistributerDynamicFees   = "coin_distributer_dynamic_fees_enabled"
+	configKeyCoinDistributerMaxFeeCap     = "coin_distributer_max_fee_per_gas_cap"
+	configKeyCoinDistributerMaxTipCap     = "coin_distributer_max_priority_fee_per_gas_cap"
+	configKeyCoinDistributerGasMargin     = "coin_distributer_block_gas_limit_margin_percent"
+	configKeyCoinDistributerMaxInFlight   = "coin_distributer_max_in_flight_transactions"
+	configKeyCoinDistributerReplaceTTL    = "coin_distributer_tx_replacement_timeout_minutes"
+	configKeyCoinDistributerConfirmations = "coin_distributer_confirmation_blocks"
+	configKeyCoinDistributerLeaderLease   = "coin_distributer_leader_lease"
+	configKeyCoinDistributerMinToken      = "coin_distributer_min_token_balance_percent"
+	configKeyCoinDistributerMinGas        = "coin_distributer_min_gas_balance_gwei"
+	configKeyCoinDistributerMsgOnline     = "coin_distributer_msg_sent_online_date"
+	configKeyCoinDistributerMsgOffline    = "coin_distributer_msg_sent_offline_date"
+	configKeyCoinDistributerMsgFinished   = "coin_distributer_msg_sent_finished_date"
+	configKeyCoinDistributerMsgLowBalance = "coin_distributer_msg_sent_low_balance_date"
+
+	configKeyCoinDistributerMaxCycleIce      = "coin_distributer_review_max_cycle_ice"
+	configKeyCoinDistributerMaxUserIce       = "coin_distributer_review_max_user_ice"
+	configKeyCoinDistributerMaxCycleIncrease = "coin_distributer_review_max_cycle_increase_percent"
+	configKeyCoinDistributerReviewQuorum     = "coin_distributer_review_quorum"
+
+	budgetGuardMaxCycleIce             = "maxCycleIce"
+	budgetGuardMaxUserIce              = "maxUserIce"
+	budgetGuardMaxCycleIncreasePercent = "maxCycleIncreasePercent"
+
+	configKeyCoinDistributionsScreeningPending = "coin_distributions_screening_pending"
+
+	configKeyCoinDistributerReconciliationNextBlock  = "coin_distributer_reconciliation_next_block"
+	configKeyCoinDistributerReconciliationStartDate  = "coin_distributer_reconciliation_start_date"
+	configKeyCoinDistributerReconciliationStartBlock = "coin_distributer_reconciliation_start_block"
+	configKeyCoinDistributerMsgReconciliation        = "coin_distributer_msg_sent_reconciliation_date"
 )
 
 // .
@@ -132,83 +485,338 @@ var (
 	ddl                  string
 	errNotEnoughData     = errors.New("not enough data")
 	errClientUncoverable = errors.New("uncoverable error")
+	errGasFeeCapExceeded = errors.New("max fee per gas cap exceeded")
+	errGasEstimation     = errors.New("gas estimation failed")
+	errSigningRejected   = errors.New("signer rejected the transaction")
+	errLeadershipLost    = errors.New("leadership lost")
+	errBalanceTooLow     = errors.New("balance too low")
 )
 
 type (
-	ethTxStatus  string
-	ethApiStatus string
-	workerAction uint
-	gasGetter    interface {
-		GetGasOptions(ctx context.Context) (price *big.Int, limit uint64, err error)
+	ethTxStatus          string
+	ethApiStatus         string
+	reconciliationStatus string
+	confirmationState    uint
+	distributionMode     string
+	workerAction         uint
+	gasGetter            interface {
+		GetGasOptions(ctx context.Context) (*gasOptions, error)
 	}
-	ethClient interface {
+	// signedTxHandler gets every signed TX before it's sent, an error stops it from being sent.
+	signedTxHandler func(tx *airdropTransaction) error
+	ethClient       interface {
 		SuggestGasPrice(ctx context.Context) (*big.Int, error)
-		TransactionsStatus(ctx context.Context, hashes []*string) (statuses map[ethTxStatus][]string, err error)
+		SuggestGasFees(ctx context.Context) (baseFee, tipCap *big.Int, err error)
+		TransactionsReceipts(ctx context.Context, hashes []*string) (receipts map[string]*txReceipt, err error)
 		TransactionStatus(ctx context.Context, hash string) (status ethTxStatus, err error)
-		Airdrop(ctx context.Context, chanID *big.Int, gas gasGetter, recipients []common.Address, amounts []*big.Int) (string, error)
+		PendingNonceAt(ctx context.Context) (uint64, error)
+		BlockGasLimit(ctx context.Context) (uint64, error)
+		EstimateAirdropGas(ctx context.Context, recipients []common.Address, amounts []*big.Int) (uint64, error)
+		LatestBlockNumber(ctx context.Context) (uint64, error)
+		TransferLogs(ctx context.Context, fromBlock, toBlock uint64) ([]*transferLog, error)
+		ContractAddresses(ctx context.Context, addresses []string) ([]string, error)
+		EstimateMerkleRootGas(ctx context.Context, cycle uint64, root common.Hash) (uint64, error)
+		PublishMerkleRoot(ctx context.Context, chanID *big.Int, gas gasGetter, nonce, cycle uint64, root common.Hash, signed signedTxHandler) (*airdropTransaction, error) //nolint:lll // .
+		MerkleRoot(ctx context.Context, cycle uint64) (common.Hash, error)
+		TokenBalance(ctx context.Context) (*big.Int, error)
+		GasBalance(ctx context.Context) (*big.Int, error)
+		Airdrop(ctx context.Context, chanID *big.Int, gas gasGetter, nonce uint64, recipients []common.Address, amounts []*big.Int, signed signedTxHandler) (*airdropTransaction, error) //nolint:lll // .
+		io.Closer
+	}
+	transferFilterer interface {
+		FilterTransfer(opts *bind.FilterOpts, from, to []common.Address) (*coindistribution.CoindistributionTransferIterator, error)
+	}
+	signer interface {
+		Address() common.Address
+		SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
 		io.Closer
 	}
+	tokenBalancer interface {
+		BalanceOf(opts *bind.CallOpts, account common.Address) (*big.Int, error)
+	}
 	airDropper interface {
 		AirdropToWallets(opts *bind.TransactOpts, recipients []common.Address, amounts []*big.Int) (*types.Transaction, error)
 	}
+	merkleRootsContract interface {
+		MerkleRoots(opts *bind.CallOpts, cycle *big.Int) ([32]byte, error)
+		PublishMerkleRoot(opts *bind.TransactOpts, cycle *big.Int, root [32]byte) (*types.Transaction, error)
+	}
+	// merkleTree is built over the (address, iceflakes) pairs of a cycle, only its root is published on-chain.
+	merkleTree struct {
+		Root   common.Hash
+		Leaves []*merkleLeaf
+		Cycle  uint64
+	}
+	merkleTreeRecord struct {
+		BatchID string `db:"batch_id"`
+		Root    string `db:"root"`
+		Cycle   int64  `db:"cycle"`
+	}
+	merkleLeaf struct {
+		Iceflakes *big.Int
+		UserIDs   []string
+		Proof     []common.Hash
+		Hash      common.Hash
+		Address   common.Address
+	}
+	txReceipt struct {
+		EffectiveGasPrice *big.Int
+		Status            ethTxStatus
+		BlockHash         string
+		BlockNumber       uint64
+		GasUsed           uint64
+	}
+	// rejectedChainState is what the chain knows about the TXs of rejected coin distributions.
+	rejectedChainState struct {
+		Receipts map[string]*txReceipt
+		// ConfirmedNonce is the nonce of the next TX of the sender to be mined, nil if the sender is unknown.
+		ConfirmedNonce *uint64
+		// Known are the TXs the node knows about, mined or not.
+		Known []string
+	}
+	// minedTransaction is the TX of a batch seen in a block, waiting for the block to be deep enough.
+	minedTransaction struct {
+		Hash        string
+		BlockHash   string
+		BlockNumber uint64
+	}
+	transferLog struct {
+		Value       *big.Int
+		TxHash      string
+		EthAddress  string
+		BlockNumber uint64
+		LogIndex    uint
+	}
+	reconciliationRecord struct {
+		EthAddress           string               `db:"eth_address"`
+		Status               reconciliationStatus `db:"status"`
+		ApprovedIceflakes    string               `db:"approved_iceflakes"`
+		TransferredIceflakes string               `db:"transferred_iceflakes"`
+		ApprovedCount        uint64               `db:"approved_count"`
+		TransfersCount       uint64               `db:"transfers_count"`
+	}
+	screeningSummary struct {
+		Reason string
+		Rows   uint64
+	}
+	addressScreening struct {
+		Error    string
+		Contract bool
+	}
+	reconciliationSummary struct {
+		ReconciledAt *time.Time
+		Records      []*reconciliationRecord
+		Addresses    uint64
+		Transfers    uint64
+	}
+	gasOptions struct {
+		// Price is the gas price of a legacy TX or the max fee per gas of a dynamic fee (EIP-1559) one.
+		Price *big.Int
+		// TipCap is the max priority fee per gas of a dynamic fee (EIP-1559) TX, nil for a legacy one.
+		TipCap *big.Int
+		Limit  uint64
+	}
+	airdropTransaction struct {
+		GasPrice  *big.Int
+		GasTipCap *big.Int
+		Hash      string
+		Nonce     uint64
+	}
+	batchGasGetter struct {
+		gasGetter
+		Limit uint64
+	}
+	// leaderGasGetter renews the leader lease of the processor before the gas options of every TX it signs, it fails if it was lost.
+	leaderGasGetter struct {
+		gasGetter
+		proc *coinProcessor
+	}
+	replacementGasGetter struct {
+		gasGetter
+		MinPrice  *big.Int
+		MinTipCap *big.Int
+		MaxPrice  *big.Int
+		// Last are the gas options of the last replacement attempt.
+		Last *gasOptions
+	}
 	batchRecord struct {
-		CreatedAt  *time.Time   `db:"created_at"`
-		Day        *time.Time   `db:"day"`
-		EthTX      *string      `db:"eth_tx"`
-		UserID     string       `db:"user_id"`
-		EthAddress string       `db:"eth_address"`
-		EthStatus  ethApiStatus `db:"eth_status"`
-		Iceflakes  string       `db:"iceflakes"`
-		InternalID int64        `db:"internal_id"`
+		CreatedAt           *time.Time   `db:"created_at"`
+		Day                 *time.Time   `db:"day"`
+		EthTXSentAt         *time.Time   `db:"eth_tx_sent_at"`
+		EthTX               *string      `db:"eth_tx"`
+		EthNonce            *int64       `db:"eth_nonce"`
+		EthGasEstimate      *int64       `db:"eth_gas_estimate"`
+		EthBatchID          *string      `db:"eth_batch_id"`
+		UserID              string       `db:"user_id"`
+		EthAddress          string       `db:"eth_address"`
+		EthStatus           ethApiStatus `db:"eth_status"`
+		Iceflakes           string       `db:"iceflakes"`
+		EthTXGasPrice       string       `db:"eth_tx_gas_price"`
+		EthTXGasTipCap      string       `db:"eth_tx_gas_tip_cap"`
+		EthReplacedTXs      []string     `db:"eth_replaced_txs"`
+		EthMinedTX          *string      `db:"eth_mined_tx"`
+		EthMinedBlockHash   *string      `db:"eth_mined_block_hash"`
+		EthMinedBlockNumber *int64       `db:"eth_mined_block_number"`
+		EthRejectedAt       *time.Time   `db:"eth_rejected_at"`
+		EthError            *string      `db:"eth_error"`
+		EthFencingToken     *int64       `db:"eth_fencing_token"`
+		Target              string       `db:"target"`
+		InternalID          int64        `db:"internal_id"`
 	}
 	batch struct {
-		ID      string
-		TX      string
-		Status  ethTxStatus
-		Records []*batchRecord
+		SentAt      *time.Time
+		Nonce       *uint64
+		GasPrice    *big.Int
+		GasTipCap   *big.Int
+		ID          string
+		TX          string
+		Status      ethTxStatus
+		Records     []*batchRecord
+		ReplacedTXs []string
+		// Merkle is set if the batch publishes the root of a Merkle tree instead of airdropping the coins.
+		Merkle *merkleTree
+		// Mined is set once any of the TXs of the batch is seen in a block, until it's confirmed or reorged out.
+		Mined *minedTransaction
+		// Signed is the last TX signed for the batch, it might have been broadcast even if sending it failed.
+		Signed      *airdropTransaction
+		GasEstimate uint64
+		stuckSent   bool
+	}
+	// budgetGuards are in whole ICE, 0 disables a guard.
+	budgetGuards struct {
+		MaxCycleIce             uint64
+		MaxUserIce              uint64
+		MaxCycleIncreasePercent uint64
+	}
+	budgetTotals struct {
+		CycleIce         uint64 `db:"cycle_ice"`
+		MaxUserIce       uint64 `db:"max_user_ice"`
+		UsersOverLimit   uint64 `db:"users_over_limit"`
+		PreviousCycleIce uint64 `db:"previous_cycle_ice"`
+	}
+	// balanceStatus compares the balances of the target with what the remaining coin distributions need.
+	balanceStatus struct {
+		CheckedAt     *time.Time
+		Token         *big.Int
+		RequiredToken *big.Int
+		Gas           *big.Int
+		RequiredGas   *big.Int
 	}
 	databaseConfig struct {
 		DB *storage.DB
 	}
 	coinProcessor struct {
 		*databaseConfig
-		Client        ethClient
-		Conf          *config
-		WG            *sync.WaitGroup
-		CancelSignal  chan struct{}
+		Client       ethClient
+		Target       *distributionTarget
+		WG           *sync.WaitGroup
+		CancelSignal chan struct{}
+		nonce        *uint64
+		// leaderID holds the leader lease of the target while fencingToken is not 0, only the leader distributes.
+		leaderID       string
+		leaseRenewedAt *time.Time
+		fencingToken   atomic.Uint64
+		// balances are the ones checked before the last batch, reported by the health check.
+		balances      atomic.Pointer[balanceStatus]
+		batchSize     int
 		gasPriceCache struct {
 			price *big.Int
 			time  *time.Time
 			mu    *sync.RWMutex
 		}
+		gasFeesCache struct {
+			baseFee *big.Int
+			tipCap  *big.Int
+			time    *time.Time
+			mu      *sync.Mutex
+		}
+	}
+	// localSigner signs with a key held in memory, decrypted from a keystore file or, for development only, a raw one.
+	localSigner struct {
+		Key *ecdsa.PrivateKey
+	}
+	// remoteSigner delegates signing to an external signer, i.e. Clef, so the key never leaves it.
+	remoteSigner struct {
+		RPC     *rpc.Client
+		Account common.Address
+	}
+	remoteSignTxArgs struct {
+		To                   *common.Address `json:"to"`
+		GasPrice             *hexutil.Big    `json:"gasPrice,omitempty"`
+		MaxFeePerGas         *hexutil.Big    `json:"maxFeePerGas,omitempty"`
+		MaxPriorityFeePerGas *hexutil.Big    `json:"maxPriorityFeePerGas,omitempty"`
+		Value                *hexutil.Big    `json:"value"`
+		ChainID              *hexutil.Big    `json:"chainId"`
+		Data                 hexutil.Bytes   `json:"data"`
+		Gas                  hexutil.Uint64  `json:"gas"`
+		Nonce                hexutil.Uint64  `json:"nonce"`
+		From                 common.Address  `json:"from"`
+	}
+	remoteSignTxResult struct {
+		Raw hexutil.Bytes `json:"raw"`
 	}
 	ethClientImpl struct {
 		RPC        *ethclient.Client
 		Mutex      *sync.Mutex
-		Key        *ecdsa.PrivateKey
+		Signer     signer
 		AirDropper airDropper
+		Filterer   transferFilterer
+		Token      tokenBalancer
+		// MerkleRoots is nil unless the chain has the Merkle roots contract configured.
+		MerkleRoots         merkleRootsContract
+		Contract            common.Address
+		MerkleRootsContract common.Address
 	}
 	coinDistributer struct {
-		Client    ethClient
-		DB        *storage.DB
-		Processor *coinProcessor
+		// Client and Processor are the ones of the default (`ethereum`) target.
+		Client     ethClient
+		DB         *storage.DB
+		Processor  *coinProcessor
+		Processors []*coinProcessor
+		Clients    []ethClient
 	}
 	repository struct {
 		cfg *config
 		db  *storage.DB
 	}
 	config struct {
-		AlertSlackWebhook string `yaml:"alert-slack-webhook" mapstructure:"alert-slack-webhook"`
-		Environment       string `yaml:"environment"         mapstructure:"environment"`
-		ReviewURL         string `yaml:"review-url"          mapstructure:"review-url"`
-		Ethereum          struct {
-			RPC             string `yaml:"rpc"             mapstructure:"rpc"`
-			PrivateKey      string `yaml:"privateKey"      mapstructure:"private-key"`
-			ContractAddress string `yaml:"contractAddress" mapstructure:"contract-address"`
-			ChainID         int64  `yaml:"chainId"         mapstructure:"chain-id"`
-		} `yaml:"ethereum" mapstructure:"ethereum"`
-		StartHours  int  `yaml:"startHours"  mapstructure:"start-hours"`
-		EndHours    int  `yaml:"endHours"    mapstructure:"end-hours"`
-		Development bool `yaml:"development" mapstructure:"development"`
+		AlertSlackWebhook string      `yaml:"alert-slack-webhook" mapstructure:"alert-slack-webhook"`
+		Environment       string      `yaml:"environment"         mapstructure:"environment"`
+		ReviewURL         string      `yaml:"review-url"          mapstructure:"review-url"`
+		Ethereum          chainConfig `yaml:"ethereum" mapstructure:"ethereum"`
+		// Targets are the additional chains we distribute to, besides the default `ethereum` one, i.e. `bsc` or a testnet.
+		// Pending coin distributions are routed to them via `coin_distribution_user_targets` or `coin_distributer_cycle_target`.
+		Targets     map[string]*distributionTarget `yaml:"targets" mapstructure:"targets"`
+		StartHours  int                            `yaml:"startHours"  mapstructure:"start-hours"`
+		EndHours    int                            `yaml:"endHours"    mapstructure:"end-hours"`
+		Development bool                           `yaml:"development" mapstructure:"development"`
+	}
+	chainConfig struct {
+		Signer struct {
+			Keystore struct {
+				File string `yaml:"file" mapstructure:"file"`
+				// PassphraseEnv is the name of the environment variable holding the passphrase of the keystore file.
+				PassphraseEnv string `yaml:"passphraseEnv" mapstructure:"passphrase-env"`
+			} `yaml:"keystore" mapstructure:"keystore"`
+			Remote struct {
+				URL     string `yaml:"url"     mapstructure:"url"`
+				Address string `yaml:"address" mapstructure:"address"`
+			} `yaml:"remote" mapstructure:"remote"`
+		} `yaml:"signer" mapstructure:"signer"`
+		RPC string `yaml:"rpc" mapstructure:"rpc"`
+		// PrivateKey is a raw hex private key, allowed only in development, use a signer otherwise.
+		PrivateKey      string `yaml:"privateKey"      mapstructure:"private-key"`
+		ContractAddress string `yaml:"contractAddress" mapstructure:"contract-address"`
+		// Mode is either `airdrop` (default), pushing the coins to every recipient, or `merkle`,
+		// publishing only the Merkle root of the cycle to MerkleRootsContractAddress, so the users claim the coins themselves.
+		Mode                       distributionMode `yaml:"mode"                       mapstructure:"mode"`
+		MerkleRootsContractAddress string           `yaml:"merkleRootsContractAddress" mapstructure:"merkle-roots-contract-address"`
+		ChainID                    int64            `yaml:"chainId"                    mapstructure:"chain-id"`
+	}
+	distributionTarget struct {
+		Name        string `yaml:"-" mapstructure:"-"`
+		chainConfig `yaml:",inline" mapstructure:",squash"`
+		StartHours  int `yaml:"startHours" mapstructure:"start-hours"`
+		EndHours    int `yaml:"endHours"   mapstructure:"end-hours"`
 	}
 )
@@ -0,0 +1,234 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package coindistribution
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/wintr/connectors/storage/v2"
+)
+
+const (
+	exportTimestampFormat = `'YYYY-MM-DD"T"HH24:MI:SS.US"Z"'`
+	// Every source is projected on the same columns, sort_day being the first part of its unique key.
+	exportPendingReviewSQL = `SELECT day AS sort_day,
+									 day,
+									 '' AS review_day,
+									 user_id,
+									 username,
+									 referred_by_username,
+									 country,
+									 eth_address,
+									 ice,
+									 coalesce(iceflakes::text, '') AS iceflakes,
+									 coalesce(to_char(created_at, ` + exportTimestampFormat + `), '') AS created_at,
+									 '' AS reviewed_at,
+									 '' AS reviewer_user_id,
+									 '' AS decision,
+									 '' AS screening_reason,
+									 '' AS settled_at,
+									 '' AS eth_tx,
+									 '' AS target
+							  FROM coin_distributions_pending_review`
+	exportReviewedSQL = `SELECT review_day AS sort_day,
+								day,
+								review_day::text AS review_day,
+								user_id,
+								username,
+								referred_by_username,
+								country,
+								eth_address,
+								ice,
+								coalesce(iceflakes::text, '') AS iceflakes,
+								to_char(created_at, ` + exportTimestampFormat + `) AS created_at,
+								to_char(reviewed_at, ` + exportTimestampFormat + `) AS reviewed_at,
+								reviewer_user_id,
+								decision,
+								coalesce(screening_reason, '') AS screening_reason,
+								'' AS settled_at,
+								'' AS eth_tx,
+								'' AS target
+						 FROM reviewed_coin_distributions`
+	// The settled rows don't have the review fields, so they're taken from their approval.
+	exportSettledSQL = `SELECT s.day AS sort_day,
+							   s.day,
+							   coalesce(r.review_day::text, '') AS review_day,
+							   s.user_id,
+							   coalesce(r.username, '') AS username,
+							   coalesce(r.referred_by_username, '') AS referred_by_username,
+							   coalesce(r.country, '') AS country,
+							   s.eth_address,
+							   coalesce(r.ice, 0) AS ice,
+							   coalesce(s.iceflakes::text, '') AS iceflakes,
+							   to_char(s.created_at, ` + exportTimestampFormat + `) AS created_at,
+							   coalesce(to_char(r.reviewed_at, ` + exportTimestampFormat + `), '') AS reviewed_at,
+							   coalesce(r.reviewer_user_id, '') AS reviewer_user_id,
+							   coalesce(r.decision, '') AS decision,
+							   coalesce(r.screening_reason, '') AS screening_reason,
+							   to_char(s.settled_at, ` + exportTimestampFormat + `) AS settled_at,
+							   s.eth_tx,
+							   s.target
+						FROM settled_coin_distributions s
+							 LEFT JOIN LATERAL (SELECT *
+												FROM reviewed_coin_distributions rr
+												WHERE rr.user_id = s.user_id
+												  AND rr.day = s.day
+												  AND rr.decision != 'deny'
+												ORDER BY rr.review_day DESC
+												LIMIT 1) r ON true`
+	exportPageSQL = `SELECT sort_day::text AS sort_day,
+							day::text AS day,
+							review_day,
+							user_id,
+							username,
+							referred_by_username,
+							country,
+							eth_address,
+							ice,
+							iceflakes,
+							created_at,
+							reviewed_at,
+							reviewer_user_id,
+							decision,
+							screening_reason,
+							settled_at,
+							eth_tx,
+							target
+					 FROM (%[1]v) x
+					 WHERE (sort_day, day, user_id) > ($1::date, $2::date, $3)
+					   AND %[2]v
+					 ORDER BY x.sort_day, x.day, x.user_id
+					 LIMIT $4`
+)
+
+type (
+	// coinDistributionsEncoder writes the pages of an export as they're fetched, so that it never holds more than a page.
+	coinDistributionsEncoder interface {
+		Encode(page []*ExportedCoinDistribution) error
+		Close() error
+	}
+	csvCoinDistributionsEncoder struct {
+		w *csv.Writer
+	}
+	parquetCoinDistributionsEncoder struct {
+		w *parquet.GenericWriter[ExportedCoinDistribution]
+	}
+)
+
+//nolint:gochecknoglobals // .
+var exportCSVHeader = []string{
+	"day", "review_day", "user_id", "username", "referred_by_username", "country", "eth_address", "ice", "iceflakes",
+	"created_at", "reviewed_at", "reviewer_user_id", "decision", "screening_reason", "settled_at", "tx_hash", "target",
+}
+
+func (r *repository) ExportCoinDistributions(ctx context.Context, arg *ExportCoinDistributionsArg, w io.Writer) error {
+	source, err := exportSourceSQL(arg.Source)
+	if err != nil {
+		return err
+	}
+	enc, err := newCoinDistributionsEncoder(arg.Format, w)
+	if err != nil {
+		return err
+	}
+	conditions, whereArgs := arg.CoinDistributionsForReviewFilter.where(5) //nolint:gomnd // $1-$3 are the cursor and $4 is the limit.
+	sql := fmt.Sprintf(exportPageSQL, source, strings.Join(append(conditions, "1=1"), " AND "))
+	sortDay, day, userID := "0001-01-01", "0001-01-01", ""
+	for {
+		page, pErr := storage.Select[ExportedCoinDistribution](ctx, r.db, sql, append([]any{sortDay, day, userID, exportPageSize}, whereArgs...)...)
+		if pErr != nil {
+			return errors.Wrapf(pErr, "failed to select the %v coin distributions to export after (%v,%v,%v)", arg.Source, sortDay, day, userID)
+		}
+		for _, row := range page {
+			row.Ice = float64(row.IceInternal) / 100 //nolint:gomnd // ice is stored with 2 decimals.
+		}
+		if err = enc.Encode(page); err != nil {
+			return errors.Wrapf(err, "failed to encode %v coin distributions as %v", len(page), arg.Format)
+		}
+		if len(page) < exportPageSize {
+			break
+		}
+		last := page[len(page)-1]
+		sortDay, day, userID = last.SortDay, last.Day, last.UserID
+	}
+
+	return errors.Wrapf(enc.Close(), "failed to finish the %v export", arg.Format)
+}
+
+func exportSourceSQL(source string) (string, error) {
+	switch source {
+	case exportSourcePendingReview:
+		return exportPendingReviewSQL, nil
+	case exportSourceReviewed:
+		return exportReviewedSQL, nil
+	case exportSourceSettled:
+		return exportSettledSQL, nil
+	default:
+		return "", errors.Errorf("unsupported export source %q", source)
+	}
+}
+
+func newCoinDistributionsEncoder(format string, w io.Writer) (coinDistributionsEncoder, error) {
+	switch format {
+	case exportFormatCSV, "":
+		enc := &csvCoinDistributionsEncoder{w: csv.NewWriter(w)}
+
+		return enc, errors.Wrap(enc.w.Write(exportCSVHeader), "failed to write the csv header")
+	case exportFormatParquet:
+		return &parquetCoinDistributionsEncoder{w: parquet.NewGenericWriter[ExportedCoinDistribution](w)}, nil
+	default:
+		return nil, errors.Errorf("unsupported export format %q", format)
+	}
+}
+
+func (e *csvCoinDistributionsEncoder) Encode(page []*ExportedCoinDistribution) error {
+	for _, row := range page {
+		if err := e.w.Write(row.csvRecord()); err != nil {
+			return errors.Wrapf(err, "failed to write the csv record of %#v", row)
+		}
+	}
+	e.w.Flush()
+
+	return errors.Wrap(e.w.Error(), "failed to flush the csv records")
+}
+
+func (e *csvCoinDistributionsEncoder) Close() error {
+	e.w.Flush()
+
+	return errors.Wrap(e.w.Error(), "failed to flush the csv writer")
+}
+
+// Encode writes each page as its own row group, so that the writer doesn't buffer the whole export.
+func (e *parquetCoinDistributionsEncoder) Encode(page []*ExportedCoinDistribution) error {
+	if len(page) == 0 {
+		return nil
+	}
+	rows := make([]ExportedCoinDistribution, 0, len(page))
+	for _, row := range page {
+		rows = append(rows, *row)
+	}
+	if _, err := e.w.Write(rows); err != nil {
+		return errors.Wrapf(err, "failed to write %v parquet rows", len(rows))
+	}
+
+	return errors.Wrap(e.w.Flush(), "failed to flush the parquet row group")
+}
+
+func (e *parquetCoinDistributionsEncoder) Close() error {
+	return errors.Wrap(e.w.Close(), "failed to close the parquet writer")
+}
+
+func (e *ExportedCoinDistribution) csvRecord() []string {
+	return []string{
+		e.Day, e.ReviewDay, e.UserID, e.Username, e.ReferredByUsername, e.Country, e.EthAddress,
+		strconv.FormatFloat(e.Ice, 'f', 2, 64), e.Iceflakes, //nolint:gomnd // ice has 2 decimals.
+		e.CreatedAt, e.ReviewedAt, e.ReviewerUserID, e.Decision, e.ScreeningReason, e.SettledAt, e.TxHash, e.Target,
+	}
+}
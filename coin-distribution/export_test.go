@@ -0,0 +1,57 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package coindistribution
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoinDistributionsEncoder(t *testing.T) {
+	t.Parallel()
+
+	page := []*ExportedCoinDistribution{
+		{SortDay: "2024-01-02", Day: "2024-01-02", UserID: "u1", Username: "jdoe", EthAddress: "0x1", Ice: 10.5, IceInternal: 1050},
+		{SortDay: "2024-01-02", Day: "2024-01-02", UserID: "u2", Username: "with,comma", EthAddress: "0x2", Ice: 1, IceInternal: 100},
+	}
+
+	t.Run("csv", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		enc, err := newCoinDistributionsEncoder(exportFormatCSV, &buf)
+		require.NoError(t, err)
+		require.NoError(t, enc.Encode(page))
+		require.NoError(t, enc.Encode(nil))
+		require.NoError(t, enc.Close())
+		assert.Equal(t, "day,review_day,user_id,username,referred_by_username,country,eth_address,ice,iceflakes,"+
+			"created_at,reviewed_at,reviewer_user_id,decision,screening_reason,settled_at,tx_hash,target\n"+
+			"2024-01-02,,u1,jdoe,,,0x1,10.50,,,,,,,,,\n"+
+			"2024-01-02,,u2,\"with,comma\",,,0x2,1.00,,,,,,,,,\n", buf.String())
+	})
+
+	t.Run("parquet", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		enc, err := newCoinDistributionsEncoder(exportFormatParquet, &buf)
+		require.NoError(t, err)
+		require.NoError(t, enc.Encode(page[:1]))
+		require.NoError(t, enc.Encode(page[1:]))
+		require.NoError(t, enc.Close())
+		rows, err := parquet.Read[ExportedCoinDistribution](bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		require.NoError(t, err)
+		require.Len(t, rows, 2)
+		assert.Equal(t, "u2", rows[1].UserID)
+		assert.Equal(t, "with,comma", rows[1].Username)
+		assert.InDelta(t, 10.5, rows[0].Ice, 0.001)
+		assert.Empty(t, rows[0].SortDay)
+	})
+
+	_, err := newCoinDistributionsEncoder("xlsx", new(bytes.Buffer))
+	require.Error(t, err)
+}
@@ -0,0 +1,101 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package coindistribution
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/wintr/connectors/storage/v2"
+)
+
+func (r *repository) GetCoinDistributionHolds(ctx context.Context, arg *GetCoinDistributionHoldsArg) (*CoinDistributionHolds, error) {
+	const sql = `SELECT h.created_at,
+						h.released_at,
+						h.user_id,
+						h.reviewer_user_id,
+						h.released_by_user_id,
+						h.reason,
+						coalesce((SELECT sum(o.ice) FROM coin_distributions_on_hold o WHERE o.user_id = h.user_id), 0)::bigint AS held_ice,
+						h.until_released
+				 FROM coin_distribution_holds h
+				 WHERE $3 OR h.released_at IS NULL
+				 ORDER BY h.created_at DESC, h.user_id ASC
+				 LIMIT $2 OFFSET $1`
+	holds, err := storage.Select[CoinDistributionHold](ctx, r.db, sql, arg.Cursor, arg.Limit, arg.IncludeReleased)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to select coin distribution holds for %#v", arg)
+	}
+	for _, hold := range holds {
+		hold.HeldIce = float64(hold.HeldIceInternal) / 100 //nolint:gomnd // ice is stored with 2 decimals.
+	}
+
+	return &CoinDistributionHolds{
+		Holds:  holds,
+		Cursor: arg.Cursor + uint64(len(holds)),
+	}, nil
+}
+
+// HoldCoinDistributions moves the coin distributions pending review of the users to `coin_distributions_on_hold`.
+// `prepare_coin_distributions_for_review` carries them into the next cycle, or the one after the hold is released, if it's UntilReleased.
+func (r *repository) HoldCoinDistributions(ctx context.Context, reviewerUserID string, arg *HoldCoinDistributionsArg) (*HeldCoinDistributions, error) {
+	const sql = `WITH holds AS (
+					INSERT INTO coin_distribution_holds(created_at, user_id, reviewer_user_id, reason, until_released)
+					SELECT current_timestamp, user_id, $2, $3, $4
+					FROM (SELECT DISTINCT unnest($1::text[]) AS user_id) AS x
+					ON CONFLICT (user_id) WHERE released_at IS NULL DO UPDATE
+						SET reviewer_user_id = EXCLUDED.reviewer_user_id,
+							reason = EXCLUDED.reason,
+							until_released = EXCLUDED.until_released
+				 ), held AS (
+					DELETE FROM coin_distributions_pending_review
+					WHERE user_id = ANY($1)
+					RETURNING *
+				 ), on_hold AS (
+					INSERT INTO coin_distributions_on_hold(held_at, created_at, internal_id, ice, day, username, referred_by_username, user_id, eth_address, country)
+					SELECT current_timestamp, coalesce(created_at, current_timestamp), internal_id, ice, day, username, referred_by_username, user_id, eth_address, country
+					FROM held
+					ON CONFLICT (day, user_id) DO UPDATE
+						SET ice = coin_distributions_on_hold.ice + EXCLUDED.ice
+				 )
+				 SELECT count(1) AS rows,
+						coalesce(sum(ice), 0) AS ice
+				 FROM held`
+	var held *HeldCoinDistributions
+	err := storage.DoInTransaction(ctx, r.db, func(conn storage.QueryExecer) error {
+		totals, err := storage.ExecOne[struct {
+			Rows uint64
+			Ice  uint64
+		}](ctx, conn, sql, arg.UserIDs, reviewerUserID, arg.Reason, arg.UntilReleased)
+		if err != nil {
+			return errors.Wrapf(err, "failed to hold the coin distributions of %v user(s)", len(arg.UserIDs))
+		}
+		held = &HeldCoinDistributions{Rows: totals.Rows, Ice: float64(totals.Ice) / 100} //nolint:gomnd // ice is stored with 2 decimals.
+
+		return errors.Wrap(r.sendCoinDistributionsHeldSlackMessage(ctx, arg, held), "failed to sendCoinDistributionsHeldSlackMessage")
+	})
+
+	return held, err
+}
+
+// ReleaseCoinDistributionHolds releases the open holds of the users, what they have on hold is carried into the next cycle.
+func (r *repository) ReleaseCoinDistributionHolds(ctx context.Context, reviewerUserID string, arg *ReleaseCoinDistributionHoldsArg) error {
+	const sql = `UPDATE coin_distribution_holds
+				 SET released_at = current_timestamp,
+					 released_by_user_id = $2
+				 WHERE released_at IS NULL
+				   AND user_id = ANY($1)`
+
+	return errors.Wrapf(storage.DoInTransaction(ctx, r.db, func(conn storage.QueryExecer) error {
+		rows, err := storage.Exec(ctx, conn, sql, arg.UserIDs, reviewerUserID)
+		if err != nil {
+			return errors.Wrap(err, "failed to update coin_distribution_holds")
+		}
+		if rows == 0 {
+			return ErrNotFound
+		}
+
+		return errors.Wrap(r.sendCoinDistributionHoldsReleasedSlackMessage(ctx, reviewerUserID, rows), "failed to sendCoinDistributionHoldsReleasedSlackMessage")
+	}), "failed to release the coin distribution holds of %v", arg.UserIDs)
+}
@@ -0,0 +1,64 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package coindistribution
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ice-blockchain/wintr/connectors/storage/v2"
+)
+
+func TestHoldCoinDistributions(t *testing.T) { //nolint:paralleltest // .
+	maybeSkipTest(t)
+	ctx := context.TODO()
+	db := storage.MustConnect(ctx, ddl, applicationYamlKey)
+	defer db.Close()
+	repo := &repository{db: db, cfg: new(config)}
+
+	userID := RandStringBytes(8)
+	const ethAddress = "0x0000000000000000000000000000000000000001"
+	_, err := storage.Exec(ctx, db, `INSERT INTO coin_distributions_pending_review(created_at, internal_id, ice, day, iceflakes, username, referred_by_username, user_id, eth_address)
+									 VALUES (current_timestamp, 1, 1000, current_date - 1, 10000000000000000000, $1, '', $1, $2)`, userID, ethAddress)
+	require.NoError(t, err)
+
+	held, err := repo.HoldCoinDistributions(ctx, "reviewer1", &HoldCoinDistributionsArg{UserIDs: []string{userID, userID}, Reason: "test"})
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, held.Rows)
+	assert.InDelta(t, 10.0, held.Ice, 0.001)
+
+	holds, err := repo.GetCoinDistributionHolds(ctx, &GetCoinDistributionHoldsArg{Limit: 1000})
+	require.NoError(t, err)
+	var hold *CoinDistributionHold
+	for _, h := range holds.Holds {
+		if h.UserID == userID {
+			hold = h
+		}
+	}
+	require.NotNil(t, hold)
+	assert.InDelta(t, 10.0, hold.HeldIce, 0.001)
+	assert.False(t, hold.UntilReleased)
+
+	_, err = storage.Exec(ctx, db, `INSERT INTO coin_distributions_by_earner(created_at, internal_id, balance, day, username, referred_by_username, user_id, earner_user_id, eth_address)
+									VALUES (current_timestamp, 1, 500, current_date, $1, '', $1, $1, $2)`, userID, ethAddress)
+	require.NoError(t, err)
+	require.NoError(t, storage.DoInTransaction(ctx, db, func(conn storage.QueryExecer) error {
+		_, pErr := storage.Exec(ctx, conn, "call prepare_coin_distributions_for_review(true)")
+
+		return pErr //nolint:wrapcheck // .
+	}))
+
+	merged, err := storage.Select[struct {
+		Ice     int64
+		HeldIce int64
+	}](ctx, db, `SELECT ice, held_ice FROM coin_distributions_pending_review WHERE user_id = $1`, userID)
+	require.NoError(t, err)
+	require.Len(t, merged, 1)
+	assert.EqualValues(t, 1500, merged[0].Ice)
+	assert.EqualValues(t, 1000, merged[0].HeldIce)
+
+	require.ErrorIs(t, repo.ReleaseCoinDistributionHolds(ctx, "reviewer1", &ReleaseCoinDistributionHoldsArg{UserIDs: []string{userID}}), ErrNotFound)
+}
@@ -0,0 +1,24 @@
+// SPDX-License-Identifier: ice License 1.0
+pragma solidity ^0.8.20;
+
+/// @notice Keeps the Merkle root of every coin distribution cycle published in the Merkle-claim mode.
+/// Users claim their coins with the proofs served by freezer-refrigerant, each leaf is
+/// keccak256(bytes.concat(keccak256(abi.encode(address, uint256 iceflakes)))), pairs are hashed sorted.
+contract MerkleRoots {
+    address public owner;
+    mapping(uint256 => bytes32) public merkleRoots;
+
+    event MerkleRootPublished(uint256 indexed cycle, bytes32 root);
+
+    constructor() {
+        owner = msg.sender;
+    }
+
+    /// @notice Publishes the root of the cycle, it can't be changed afterwards.
+    function publishMerkleRoot(uint256 cycle, bytes32 root) external {
+        require(msg.sender == owner);
+        require(root != bytes32(0) && merkleRoots[cycle] == bytes32(0));
+        merkleRoots[cycle] = root;
+        emit MerkleRootPublished(cycle, root);
+    }
+}
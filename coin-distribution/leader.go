@@ -0,0 +1,129 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package coindistribution
+
+import (
+	"context"
+	"fmt"
+	"os"
+	stdlibtime "time"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/wintr/connectors/storage/v2"
+	"github.com/ice-blockchain/wintr/log"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+// newLeaderID identifies the process in the leader lease, unique per start, so a restarted process never reuses the fencing token of its predecessor.
+func newLeaderID() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+
+	return fmt.Sprintf("%v/%v", host, ulid.Make())
+}
+
+// AcquireLeadership takes (or renews) the lease of the target in `global`, if it's ours or expired.
+// A new fencing token is issued every time the lease changes hands, it is written onto every batch we touch,
+// so the batches taken over by a newer leader can't be touched by us anymore.
+func (proc *coinProcessor) AcquireLeadership(ctx context.Context) (bool, error) {
+	const stmt = `
+update global
+set
+	value = jsonb_build_object(
+		'holder', $2::text,
+		'token', (value::jsonb->>'token')::bigint + (case when value::jsonb->>'holder' = $2 then 0 else 1 end),
+		'expiresAt', now() + make_interval(secs => $3)
+	)::text
+where
+	key = $1 and
+	(value::jsonb->>'holder' = $2 or (value::jsonb->>'expiresAt')::timestamptz < now())
+returning (value::jsonb->>'token')::bigint
+`
+
+	reqCtx, cancel := context.WithTimeout(ctx, requestDeadline)
+	defer cancel()
+
+	token, err := storage.ExecOne[int64](reqCtx, proc.DB, stmt, proc.targetKey(configKeyCoinDistributerLeaderLease), proc.leaderID, leaderLeaseTTL.Seconds())
+	if err != nil {
+		proc.fencingToken.Store(0)
+		if errors.Is(err, storage.ErrNotFound) {
+			return false, nil
+		}
+
+		return false, errors.Wrapf(err, "failed to acquire the leader lease of %v", proc.Target.Name)
+	}
+	if prev := proc.fencingToken.Swap(uint64(*token)); prev != uint64(*token) {
+		log.Info(fmt.Sprintf("controller[%v]: %v is the leader now, fencing token %v", proc.Target.Name, proc.leaderID, *token))
+	}
+	proc.leaseRenewedAt = time.Now()
+
+	return true, nil
+}
+
+// RenewLeadership keeps the lease while we're busy distributing, it fails with errLeadershipLost if somebody else took it over.
+func (proc *coinProcessor) RenewLeadership(ctx context.Context) error {
+	if proc.IsLeader() && stdlibtime.Since(*proc.leaseRenewedAt.Time) < leaderLeaseTTL/3 { //nolint:gomnd // Renewed well before it expires.
+		return nil
+	}
+	leader, err := proc.AcquireLeadership(ctx)
+	if err != nil {
+		return err
+	} else if !leader {
+		return errors.Wrapf(errLeadershipLost, "controller[%v]", proc.Target.Name)
+	}
+
+	return nil
+}
+
+// ReleaseLeadership lets the standby take over right away, instead of waiting for the lease to expire.
+func (proc *coinProcessor) ReleaseLeadership(ctx context.Context) error {
+	const stmt = `
+update global
+set
+	value = jsonb_set(value::jsonb, '{expiresAt}', to_jsonb(now() - interval '1 second'))::text
+where
+	key = $1 and
+	value::jsonb->>'holder' = $2
+`
+	if !proc.IsLeader() {
+		return nil
+	}
+	proc.fencingToken.Store(0)
+
+	reqCtx, cancel := context.WithTimeout(ctx, requestDeadline)
+	defer cancel()
+
+	_, err := storage.Exec(reqCtx, proc.DB, stmt, proc.targetKey(configKeyCoinDistributerLeaderLease), proc.leaderID)
+
+	return errors.Wrapf(err, "failed to release the leader lease of %v", proc.Target.Name)
+}
+
+func (g *leaderGasGetter) GetGasOptions(ctx context.Context) (*gasOptions, error) {
+	if err := g.proc.RenewLeadership(ctx); err != nil {
+		return nil, err
+	}
+
+	return g.gasGetter.GetGasOptions(ctx) //nolint:wrapcheck // .
+}
+
+func (proc *coinProcessor) IsLeader() bool {
+	return proc.fencingToken.Load() != 0
+}
+
+// FencingToken is written onto the batches and checked by every update of them: a batch with a higher token belongs to a newer leader.
+func (proc *coinProcessor) FencingToken() int64 {
+	return int64(proc.fencingToken.Load())
+}
+
+// fenced fails with errLeadershipLost if the statement didn't update any of the batch records, because a newer leader took them over.
+func fenced(rows uint64, err error) error {
+	if err == nil && rows == 0 {
+		err = errLeadershipLost
+	}
+
+	return err
+}
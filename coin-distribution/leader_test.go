@@ -0,0 +1,58 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package coindistribution
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ice-blockchain/wintr/connectors/storage/v2"
+)
+
+func TestFenced(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, fenced(1, nil))
+	require.ErrorIs(t, fenced(0, nil), errLeadershipLost)
+
+	dbErr := errors.New("db error") //nolint:goerr113 // .
+	require.ErrorIs(t, fenced(0, dbErr), dbErr)
+	require.NotEqual(t, newLeaderID(), newLeaderID())
+}
+
+func TestLeadership(t *testing.T) { //nolint:paralleltest // .
+	maybeSkipTest(t)
+	ctx := context.TODO()
+	db := storage.MustConnect(ctx, ddl, applicationYamlKey)
+	defer db.Close()
+
+	leader := newCoinProcessor(new(mockedDummyEthClient), db, &config{})
+	standby := newCoinProcessor(new(mockedDummyEthClient), db, &config{})
+
+	ok, err := leader.AcquireLeadership(ctx)
+	require.NoError(t, err)
+	require.True(t, ok)
+	token := leader.FencingToken()
+
+	ok, err = standby.AcquireLeadership(ctx)
+	require.NoError(t, err)
+	require.False(t, ok)
+	require.False(t, standby.IsLeader())
+
+	ok, err = leader.AcquireLeadership(ctx)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, token, leader.FencingToken())
+
+	require.NoError(t, leader.ReleaseLeadership(ctx))
+	require.False(t, leader.IsLeader())
+
+	ok, err = standby.AcquireLeadership(ctx)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Greater(t, standby.FencingToken(), token)
+	require.NoError(t, standby.ReleaseLeadership(ctx))
+}
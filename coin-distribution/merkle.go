@@ -0,0 +1,261 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package coindistribution
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/wintr/connectors/storage/v2"
+	"github.com/ice-blockchain/wintr/log"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+// newMerkleTree builds the tree over the (address, iceflakes) pairs of the records, the amounts of the same address are summed up.
+// Leaves are sorted by their hash and the odd node of a level is promoted as is, so the proofs are compatible with OpenZeppelin's MerkleProof.
+func newMerkleTree(records []*batchRecord) *merkleTree {
+	byAddress := make(map[common.Address]*merkleLeaf, len(records))
+	for _, record := range records {
+		addr := record.Address()
+		leaf, found := byAddress[addr]
+		if !found {
+			leaf = &merkleLeaf{Address: addr, Iceflakes: new(big.Int)}
+			byAddress[addr] = leaf
+		}
+		leaf.Iceflakes.Add(leaf.Iceflakes, record.Amount())
+		leaf.UserIDs = append(leaf.UserIDs, record.UserID)
+	}
+
+	tree := &merkleTree{Leaves: make([]*merkleLeaf, 0, len(byAddress))}
+	for _, leaf := range byAddress {
+		leaf.Hash = merkleLeafHash(leaf.Address, leaf.Iceflakes)
+		tree.Leaves = append(tree.Leaves, leaf)
+	}
+	sort.Slice(tree.Leaves, func(i, j int) bool { return bytes.Compare(tree.Leaves[i].Hash[:], tree.Leaves[j].Hash[:]) < 0 })
+
+	level := make([]common.Hash, len(tree.Leaves)) //nolint:makezero //.
+	for idx, leaf := range tree.Leaves {
+		level[idx] = leaf.Hash
+	}
+	for depth := 0; len(level) > 1; depth++ {
+		for idx, leaf := range tree.Leaves {
+			if sibling := (idx >> depth) ^ 1; sibling < len(level) {
+				leaf.Proof = append(leaf.Proof, level[sibling])
+			}
+		}
+		next := make([]common.Hash, 0, (len(level)+1)/2) //nolint:gomnd // Pairs.
+		for idx := 0; idx < len(level); idx += 2 {
+			if idx+1 == len(level) {
+				next = append(next, level[idx])
+			} else {
+				next = append(next, hashMerklePair(level[idx], level[idx+1]))
+			}
+		}
+		level = next
+	}
+	if len(level) == 1 {
+		tree.Root = level[0]
+	}
+
+	return tree
+}
+
+// merkleLeafHash is keccak256(bytes.concat(keccak256(abi.encode(address, iceflakes)))), hashed twice against second preimage attacks.
+func merkleLeafHash(address common.Address, iceflakes *big.Int) common.Hash {
+	encoded := make([]byte, 2*common.HashLength) //nolint:gomnd // Two ABI words.
+	copy(encoded[common.HashLength-common.AddressLength:common.HashLength], address.Bytes())
+	iceflakes.FillBytes(encoded[common.HashLength:])
+
+	return crypto.Keccak256Hash(crypto.Keccak256(encoded))
+}
+
+func hashMerklePair(a, b common.Hash) common.Hash {
+	if bytes.Compare(a[:], b[:]) > 0 {
+		a, b = b, a
+	}
+
+	return crypto.Keccak256Hash(a[:], b[:])
+}
+
+// verifyMerkleProof checks that the leaf belongs to the tree with the given root, the same way the claim contract does.
+func verifyMerkleProof(root, leaf common.Hash, proof []common.Hash) bool {
+	computed := leaf
+	for _, sibling := range proof {
+		computed = hashMerklePair(computed, sibling)
+	}
+
+	return computed == root
+}
+
+func (proc *coinProcessor) isMerkleMode() bool {
+	return proc.Target.Mode == distributionModeMerkle
+}
+
+// MerkleTreePrepare builds the Merkle tree of the batch, persists it with the proof of every leaf
+// and estimates the gas needed to publish its root.
+func (proc *coinProcessor) MerkleTreePrepare(ctx context.Context, data *batch) error {
+	tree := newMerkleTree(data.Records)
+	if err := proc.MerkleTreeSave(ctx, data.ID, tree); err != nil {
+		return err
+	}
+
+	estimate, err := proc.Client.EstimateMerkleRootGas(ctx, tree.Cycle, tree.Root)
+	if err != nil {
+		log.Error(errors.Wrapf(proc.MerkleTreeDelete(ctx, tree.Cycle), "failed to delete merkle tree of batch %v", data.ID))
+
+		return errors.Wrapf(err, "failed to estimate gas for merkle root of batch %v", data.ID)
+	}
+	data.GasEstimate, data.Merkle = estimate, tree
+	log.Info(fmt.Sprintf("batch %v: merkle tree of cycle %v: %v record(s), %v leaves, root %v, estimated gas %v",
+		data.ID, tree.Cycle, len(data.Records), len(tree.Leaves), tree.Root.Hex(), estimate))
+
+	return nil
+}
+
+// MerkleTreeSave persists the tree and the proofs of its leaves, the cycle of the tree is assigned by the database.
+func (proc *coinProcessor) MerkleTreeSave(ctx context.Context, batchID string, tree *merkleTree) error {
+	const (
+		treeStmt = `
+insert into coin_distribution_merkle_trees
+	(created_at, leaves, iceflakes, batch_id, target, root, contract_address)
+values
+	($1, $2, $3::text::uint256, $4, $5, $6, $7)
+returning cycle
+`
+		columns = 7
+	)
+
+	total := new(big.Int)
+	for _, leaf := range tree.Leaves {
+		total.Add(total, leaf.Iceflakes)
+	}
+
+	return errors.Wrapf(storage.DoInTransaction(ctx, proc.DB, func(conn storage.QueryExecer) error {
+		cycle, err := storage.ExecOne[int64](ctx, conn, treeStmt,
+			time.Now().Time, len(tree.Leaves), total.String(), batchID, proc.Target.Name, tree.Root.Hex(), proc.Target.MerkleRootsContractAddress)
+		if err != nil {
+			return errors.Wrap(err, "failed to insert merkle tree")
+		}
+		tree.Cycle = uint64(*cycle)
+
+		for from := 0; from < len(tree.Leaves); from += merkleProofsPerInsert {
+			leaves := tree.Leaves[from:min(from+merkleProofsPerInsert, len(tree.Leaves))]
+			values := make([]string, 0, len(leaves))
+			args := make([]any, 0, len(leaves)*columns)
+			for idx, leaf := range leaves {
+				proof := make([]string, 0, len(leaf.Proof))
+				for _, sibling := range leaf.Proof {
+					proof = append(proof, sibling.Hex())
+				}
+				ix := idx * columns
+				values = append(values, fmt.Sprintf("($%v,$%v,$%v::text::uint256,$%v,$%v,$%v,$%v)", ix+1, ix+2, ix+3, ix+4, ix+5, ix+6, ix+7)) //nolint:gomnd // Columns.
+				args = append(args,
+					*cycle, from+idx, leaf.Iceflakes.String(), strings.ToLower(leaf.Address.Hex()), leaf.Hash.Hex(), leaf.UserIDs, proof)
+			}
+			sql := fmt.Sprintf(`insert into coin_distribution_merkle_proofs(cycle, leaf_index, iceflakes, eth_address, leaf, user_ids, proof)
+								VALUES %v`, strings.Join(values, ",\n"))
+			if _, err = storage.Exec(ctx, conn, sql, args...); err != nil {
+				return errors.Wrapf(err, "failed to insert merkle proofs [%v:%v]", from, from+len(leaves))
+			}
+		}
+
+		return nil
+	}), "failed to save merkle tree of batch %v", batchID)
+}
+
+func (proc *coinProcessor) MerkleTreeDelete(ctx context.Context, cycle uint64) error {
+	_, err := storage.Exec(ctx, proc.DB, `delete from coin_distribution_merkle_trees where cycle = $1 and published_at is null`, int64(cycle))
+
+	return errors.Wrapf(err, "failed to delete merkle tree of cycle %v", cycle)
+}
+
+// MerkleTreesLoad sets the tree of the in-flight batches which publish a Merkle root, without its leaves.
+func (proc *coinProcessor) MerkleTreesLoad(ctx context.Context, batches []*batch) error {
+	const stmt = `
+select
+	cycle,
+	batch_id,
+	root
+from
+	coin_distribution_merkle_trees
+where
+	batch_id = ANY($1)
+`
+	if len(batches) == 0 {
+		return nil
+	}
+
+	byID := make(map[string]*batch, len(batches))
+	ids := make([]string, 0, len(batches))
+	for _, data := range batches {
+		byID[data.ID] = data
+		ids = append(ids, data.ID)
+	}
+	trees, err := storage.Select[merkleTreeRecord](ctx, proc.DB, stmt, ids)
+	if err != nil {
+		return errors.Wrap(err, "failed to select merkle trees of in-flight batches")
+	}
+	for _, tree := range trees {
+		byID[tree.BatchID].Merkle = &merkleTree{Cycle: uint64(tree.Cycle), Root: common.HexToHash(tree.Root)}
+	}
+
+	return nil
+}
+
+// MerkleTreePublished checks the root mined on-chain is the one of the tree and marks the tree as published,
+// so its proofs become available to the users.
+func (proc *coinProcessor) MerkleTreePublished(ctx context.Context, data *batch, minedHash string) error {
+	const stmt = `
+update coin_distribution_merkle_trees
+set
+	published_at = $2,
+	eth_tx = $3
+where
+	cycle = $1
+`
+
+	root, err := proc.Client.MerkleRoot(ctx, data.Merkle.Cycle)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get published merkle root of cycle %v", data.Merkle.Cycle)
+	} else if root != data.Merkle.Root {
+		return errors.Errorf("merkle root of cycle %v mismatch: published %v, expected %v", data.Merkle.Cycle, root.Hex(), data.Merkle.Root.Hex())
+	}
+
+	_, err = storage.Exec(ctx, proc.DB, stmt, int64(data.Merkle.Cycle), time.Now().Time, minedHash)
+
+	return errors.Wrapf(err, "failed to mark merkle tree of cycle %v as published", data.Merkle.Cycle)
+}
+
+func (r *repository) GetCoinDistributionMerkleProofs(
+	ctx context.Context, arg *GetCoinDistributionMerkleProofsArg,
+) (*CoinDistributionMerkleProofs, error) {
+	const sql = `SELECT t.published_at,
+						t.root,
+						t.contract_address,
+						t.target,
+						t.eth_tx,
+						p.eth_address,
+						p.iceflakes,
+						p.proof,
+						t.cycle
+				 FROM coin_distribution_merkle_proofs p
+					JOIN coin_distribution_merkle_trees t
+						ON t.cycle = p.cycle
+				 WHERE p.user_ids @> ARRAY[$1]
+				   AND t.published_at IS NOT NULL
+				 ORDER BY t.cycle DESC`
+	proofs, err := storage.Select[CoinDistributionMerkleProof](ctx, r.db, sql, arg.UserID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to select merkle proofs for userID:%v", arg.UserID)
+	}
+
+	return &CoinDistributionMerkleProofs{Proofs: proofs}, nil
+}
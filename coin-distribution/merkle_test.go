@@ -0,0 +1,190 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package coindistribution
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/core/vm/runtime"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+
+	coindistribution "github.com/ice-blockchain/freezer/coin-distribution/internal"
+)
+
+// evmMerkleRoots runs the MerkleRoots contract in an in-memory EVM, so the published roots are the real contract's state.
+type evmMerkleRoots struct {
+	Config  *runtime.Config
+	ABI     *abi.ABI
+	Address common.Address
+}
+
+func newEVMMerkleRoots(t *testing.T, owner common.Address) *evmMerkleRoots {
+	t.Helper()
+
+	contractABI, err := coindistribution.MerkleRootsMetaData.GetAbi()
+	require.NoError(t, err)
+
+	cfg := &runtime.Config{Origin: owner}
+	_, address, _, err := runtime.Create(common.FromHex(coindistribution.MerkleRootsMetaData.Bin), cfg)
+	require.NoError(t, err)
+
+	return &evmMerkleRoots{Config: cfg, ABI: contractABI, Address: address}
+}
+
+func (c *evmMerkleRoots) MerkleRoots(_ *bind.CallOpts, cycle *big.Int) ([32]byte, error) {
+	input, err := c.ABI.Pack("merkleRoots", cycle)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	output, _, err := runtime.Call(c.Address, input, c.Config)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	values, err := c.ABI.Unpack("merkleRoots", output)
+	if err != nil {
+		return [32]byte{}, err
+	}
+
+	return values[0].([32]byte), nil //nolint:forcetypeassert // .
+}
+
+func (c *evmMerkleRoots) PublishMerkleRoot(opts *bind.TransactOpts, cycle *big.Int, root [32]byte) (*types.Transaction, error) {
+	input, err := c.ABI.Pack("publishMerkleRoot", cycle, root)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := opts.Signer(opts.From, types.NewTx(&types.LegacyTx{
+		Nonce:    opts.Nonce.Uint64(),
+		GasPrice: opts.GasPrice,
+		Gas:      opts.GasLimit,
+		To:       &c.Address,
+		Data:     input,
+	}))
+	if err != nil {
+		return nil, err
+	}
+	cfg := *c.Config
+	cfg.Origin = opts.From
+	if _, _, err = runtime.Call(c.Address, input, &cfg); err != nil {
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+func testMerkleRecords(count int) []*batchRecord {
+	records := make([]*batchRecord, 0, count)
+	for idx := 0; idx < count; idx++ {
+		records = append(records, &batchRecord{
+			UserID:     fmt.Sprintf("user%v", idx),
+			EthAddress: common.BigToAddress(big.NewInt(int64(idx%(count-count/3) + 1))).Hex(),
+			Iceflakes:  fmt.Sprintf("%v0000000000000000", idx+1),
+		})
+	}
+
+	return records
+}
+
+func TestMerkleTree(t *testing.T) {
+	t.Parallel()
+
+	for _, count := range []int{1, 2, 3, 7, 64, 1001} {
+		records := testMerkleRecords(count)
+		tree := newMerkleTree(records)
+		require.NotEqual(t, common.Hash{}, tree.Root)
+
+		users, total, expected := 0, new(big.Int), new(big.Int)
+		for _, record := range records {
+			expected.Add(expected, record.Amount())
+		}
+		for _, leaf := range tree.Leaves {
+			users += len(leaf.UserIDs)
+			total.Add(total, leaf.Iceflakes)
+			require.Equal(t, merkleLeafHash(leaf.Address, leaf.Iceflakes), leaf.Hash)
+			require.True(t, verifyMerkleProof(tree.Root, leaf.Hash, leaf.Proof), "count %v, leaf %v", count, leaf.Address)
+
+			tampered := new(big.Int).Add(leaf.Iceflakes, big.NewInt(1))
+			require.False(t, verifyMerkleProof(tree.Root, merkleLeafHash(leaf.Address, tampered), leaf.Proof))
+		}
+		require.Equal(t, count, users)
+		require.Zero(t, expected.Cmp(total))
+		require.Equal(t, tree.Root, newMerkleTree(records).Root)
+	}
+
+	// Same address, amounts are summed up into a single leaf.
+	tree := newMerkleTree([]*batchRecord{
+		{UserID: "a", EthAddress: "0x0000000000000000000000000000000000000001", Iceflakes: "10"},
+		{UserID: "b", EthAddress: "0x0000000000000000000000000000000000000001", Iceflakes: "32"},
+	})
+	require.Len(t, tree.Leaves, 1)
+	require.EqualValues(t, 42, tree.Leaves[0].Iceflakes.Int64())
+	require.ElementsMatch(t, []string{"a", "b"}, tree.Leaves[0].UserIDs)
+	require.Empty(t, tree.Leaves[0].Proof)
+	require.Equal(t, tree.Leaves[0].Hash, tree.Root)
+}
+
+func TestMerkleLeafHash(t *testing.T) {
+	t.Parallel()
+
+	address := common.HexToAddress("0x4B73C58370AEfcEf86A6021afCDe5673511376B2")
+	amount := big.NewInt(1_000_000)
+
+	args := abi.Arguments{{Type: abi.Type{T: abi.AddressTy, Size: 20}}, {Type: abi.Type{T: abi.UintTy, Size: 256}}}
+	encoded, err := args.Pack(address, amount)
+	require.NoError(t, err)
+	require.Equal(t, crypto.Keccak256Hash(crypto.Keccak256(encoded)), merkleLeafHash(address, amount))
+}
+
+func TestMerkleRootPublishedOnChain(t *testing.T) {
+	t.Parallel()
+
+	privateKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	owner := crypto.PubkeyToAddress(privateKey.PublicKey)
+	contract := newEVMMerkleRoots(t, owner)
+
+	client := &ethClientImpl{
+		Mutex:       new(sync.Mutex),
+		Signer:      &localSigner{Key: privateKey},
+		MerkleRoots: contract,
+	}
+	ctx := context.Background()
+
+	tree := newMerkleTree(testMerkleRecords(100))
+	tree.Cycle = 7
+	tx, err := client.PublishMerkleRoot(ctx, big.NewInt(1), new(mockedGasGetter), 3, tree.Cycle, tree.Root, ignoreSignedTx)
+	require.NoError(t, err)
+	require.EqualValues(t, 3, tx.Nonce)
+
+	published, err := client.MerkleRoot(ctx, tree.Cycle)
+	require.NoError(t, err)
+	require.Equal(t, tree.Root, published)
+	for _, leaf := range tree.Leaves {
+		require.True(t, verifyMerkleProof(published, leaf.Hash, leaf.Proof))
+	}
+
+	unpublished, err := client.MerkleRoot(ctx, tree.Cycle+1)
+	require.NoError(t, err)
+	require.Equal(t, common.Hash{}, unpublished)
+
+	opts := client.CreateTransactionOpts(ctx, &gasOptions{Price: big.NewInt(1), Limit: 100_000}, big.NewInt(1), 4)
+	_, err = contract.PublishMerkleRoot(opts, new(big.Int).SetUint64(tree.Cycle), common.Hash{1})
+	require.ErrorIs(t, err, vm.ErrExecutionReverted, "published roots can't be changed")
+
+	strangerKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	stranger := &ethClientImpl{Mutex: new(sync.Mutex), Signer: &localSigner{Key: strangerKey}, MerkleRoots: contract}
+	opts = stranger.CreateTransactionOpts(ctx, &gasOptions{Price: big.NewInt(1), Limit: 100_000}, big.NewInt(1), 0)
+	_, err = contract.PublishMerkleRoot(opts, big.NewInt(8), common.Hash{1})
+	require.ErrorIs(t, err, vm.ErrExecutionReverted, "only the owner publishes roots")
+}
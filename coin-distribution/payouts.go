@@ -0,0 +1,44 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package coindistribution
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/wintr/connectors/storage/v2"
+)
+
+func (r *repository) GetCoinDistributionPayouts(ctx context.Context, arg *GetCoinDistributionPayoutsArg) (*CoinDistributionPayouts, error) {
+	conditions, whereArgs := arg.where(3) //nolint:gomnd // Cursor and limit come first.
+	sql := fmt.Sprintf(`SELECT *
+						FROM settled_coin_distributions
+						WHERE %[1]v
+						ORDER BY settled_at DESC, day DESC, user_id ASC
+						LIMIT $2 OFFSET $1`, strings.Join(append(conditions, "1=1"), " AND "))
+	payouts, err := storage.Select[CoinDistributionPayout](ctx, r.db, sql, append([]any{arg.Cursor, arg.Limit}, whereArgs...)...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to select settled_coin_distributions for %#v", arg)
+	}
+
+	return &CoinDistributionPayouts{
+		Payouts: payouts,
+		Cursor:  arg.Cursor + uint64(len(payouts)),
+	}, nil
+}
+
+func (a *GetCoinDistributionPayoutsArg) where(firstParamIx int) (conditions []string, args []any) {
+	if a.UserID != "" {
+		args = append(args, a.UserID)
+		conditions = append(conditions, fmt.Sprintf("user_id = $%v", firstParamIx+len(args)-1))
+	}
+	if a.EthAddress != "" {
+		args = append(args, a.EthAddress)
+		conditions = append(conditions, fmt.Sprintf("lower(eth_address) = lower($%v)", firstParamIx+len(args)-1))
+	}
+
+	return conditions, args
+}
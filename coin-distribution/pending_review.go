@@ -5,6 +5,7 @@ package coindistribution
 import (
 	"context"
 	"fmt"
+	"math"
 	"strings"
 	stdlibtime "time"
 
@@ -60,9 +61,27 @@ func (r *repository) GetCoinDistributionsForReview(ctx context.Context, arg *Get
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to select coin_distributions_pending_review for %#v", arg)
 	}
+	guards, breaches, err := getBudgetBreaches(ctx, r.db)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to getBudgetBreaches")
+	}
+	snapshot, err := reviewSnapshot(ctx, r.db, &arg.CoinDistributionsForReviewFilter)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get reviewSnapshot")
+	}
+	votes, err := r.getOpenReviewVotes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var quorum uint64
+	if err = databaseGetValue(ctx, r.db, configKeyCoinDistributerReviewQuorum, &quorum); err != nil {
+		return nil, err
+	}
 	distributions := make([]*PendingReview, len(result)) //nolint:makezero // .
 	for i, d := range result {
 		d.PendingReview.Ice = float64(d.PendingReview.IceInternal) / 100
+		d.PendingReview.HeldIce = float64(d.PendingReview.HeldIceInternal) / 100
+		d.PendingReview.ExceedsMaxUserIce = guards.MaxUserIce > 0 && d.PendingReview.Ice > float64(guards.MaxUserIce)
 		distributions[i] = d.PendingReview
 	}
 	conditions, whereArgs = arg.totalsWhere()
@@ -84,10 +103,14 @@ func (r *repository) GetCoinDistributionsForReview(ctx context.Context, arg *Get
 	}
 
 	return &CoinDistributionsForReview{
-		Distributions: distributions,
-		Cursor:        nextCursor,
-		TotalRows:     total.Rows,
-		TotalIce:      float64(total.Ice) / 100,
+		Distributions:  distributions,
+		Cursor:         nextCursor,
+		TotalRows:      total.Rows,
+		TotalIce:       float64(total.Ice) / 100,
+		BudgetBreaches: breaches,
+		Snapshot:       snapshot,
+		Votes:          votes,
+		Quorum:         max(quorum, 1),
 	}, nil
 }
 
@@ -111,67 +134,94 @@ func (a *GetCoinDistributionsForReviewArg) orderBy() []string {
 }
 
 func (a *GetCoinDistributionsForReviewArg) where() ([]string, []any) {
-	conditions := make([]string, 0, 2)
-	args := make([]any, 0, 2)
-
-	i := 3
-	if referredByUsernameKeyword := a.ReferredByUsernameKeyword; referredByUsernameKeyword != "" {
-		referredByUsernameKeyword = strings.ReplaceAll(referredByUsernameKeyword, "!", "!!")
-		referredByUsernameKeyword = strings.ReplaceAll(referredByUsernameKeyword, "%", "!%")
-		referredByUsernameKeyword = strings.ReplaceAll(referredByUsernameKeyword, "_", "!_")
-		referredByUsernameKeyword = strings.ReplaceAll(referredByUsernameKeyword, "[", "![")
-		referredByUsernameKeyword = referredByUsernameKeyword + "%"
+	return a.CoinDistributionsForReviewFilter.where(3) //nolint:gomnd // $1 and $2 are the cursor and the limit.
+}
+
+func (a *GetCoinDistributionsForReviewArg) totalsWhere() ([]string, []any) {
+	return a.CoinDistributionsForReviewFilter.where(1)
+}
+
+func (f *CoinDistributionsForReviewFilter) where(firstParamIx int) ([]string, []any) {
+	conditions := make([]string, 0, 5) //nolint:gomnd // .
+	args := make([]any, 0, 5)          //nolint:gomnd // .
+
+	i := firstParamIx
+	if f.ReferredByUsernameKeyword != "" {
 		conditions = append(conditions, fmt.Sprintf("referred_by_username LIKE $%v ESCAPE '!'", i))
-		args = append(args, strings.ToLower(referredByUsernameKeyword))
+		args = append(args, likePrefixPattern(f.ReferredByUsernameKeyword))
 		i++
 	}
-	if usernameKeyword := a.UsernameKeyword; usernameKeyword != "" {
-		usernameKeyword = strings.ReplaceAll(usernameKeyword, "!", "!!")
-		usernameKeyword = strings.ReplaceAll(usernameKeyword, "%", "!%")
-		usernameKeyword = strings.ReplaceAll(usernameKeyword, "_", "!_")
-		usernameKeyword = strings.ReplaceAll(usernameKeyword, "[", "![")
-		usernameKeyword = usernameKeyword + "%"
+	if f.UsernameKeyword != "" {
 		conditions = append(conditions, fmt.Sprintf("username LIKE $%v ESCAPE '!'", i))
-		args = append(args, strings.ToLower(usernameKeyword))
+		args = append(args, likePrefixPattern(f.UsernameKeyword))
+		i++
+	}
+	if len(f.UserIDs) != 0 {
+		conditions = append(conditions, fmt.Sprintf("user_id = ANY($%v)", i))
+		args = append(args, f.UserIDs)
+		i++
+	}
+	if f.MinIce > 0 {
+		conditions = append(conditions, fmt.Sprintf("ice >= $%v", i))
+		args = append(args, int64(math.Round(f.MinIce*100))) //nolint:gomnd // ice is stored with 2 decimals.
+		i++
+	}
+	if f.MaxIce > 0 {
+		conditions = append(conditions, fmt.Sprintf("ice <= $%v", i))
+		args = append(args, int64(math.Round(f.MaxIce*100))) //nolint:gomnd // ice is stored with 2 decimals.
 	}
 
 	return conditions, args
 }
 
-func (a *GetCoinDistributionsForReviewArg) totalsWhere() ([]string, []any) {
-	conditions := make([]string, 0, 2)
-	args := make([]any, 0, 2)
-
-	i := 1
-	if referredByUsernameKeyword := a.ReferredByUsernameKeyword; referredByUsernameKeyword != "" {
-		referredByUsernameKeyword = strings.ReplaceAll(referredByUsernameKeyword, "!", "!!")
-		referredByUsernameKeyword = strings.ReplaceAll(referredByUsernameKeyword, "%", "!%")
-		referredByUsernameKeyword = strings.ReplaceAll(referredByUsernameKeyword, "_", "!_")
-		referredByUsernameKeyword = strings.ReplaceAll(referredByUsernameKeyword, "[", "![")
-		referredByUsernameKeyword = referredByUsernameKeyword + "%"
-		conditions = append(conditions, fmt.Sprintf("referred_by_username LIKE $%v ESCAPE '!'", i))
-		args = append(args, strings.ToLower(referredByUsernameKeyword))
-		i++
+func (f *CoinDistributionsForReviewFilter) isEmpty() bool {
+	return f.ReferredByUsernameKeyword == "" && f.UsernameKeyword == "" && len(f.UserIDs) == 0 && f.MinIce <= 0 && f.MaxIce <= 0
+}
+
+func (f *CoinDistributionsForReviewFilter) String() string {
+	parts := make([]string, 0, 5) //nolint:gomnd // .
+	if f.ReferredByUsernameKeyword != "" {
+		parts = append(parts, fmt.Sprintf("referredByUsernameKeyword=%v", f.ReferredByUsernameKeyword))
 	}
-	if usernameKeyword := a.UsernameKeyword; usernameKeyword != "" {
-		usernameKeyword = strings.ReplaceAll(usernameKeyword, "!", "!!")
-		usernameKeyword = strings.ReplaceAll(usernameKeyword, "%", "!%")
-		usernameKeyword = strings.ReplaceAll(usernameKeyword, "_", "!_")
-		usernameKeyword = strings.ReplaceAll(usernameKeyword, "[", "![")
-		usernameKeyword = usernameKeyword + "%"
-		conditions = append(conditions, fmt.Sprintf("username LIKE $%v ESCAPE '!'", i))
-		args = append(args, strings.ToLower(usernameKeyword))
+	if f.UsernameKeyword != "" {
+		parts = append(parts, fmt.Sprintf("usernameKeyword=%v", f.UsernameKeyword))
+	}
+	if len(f.UserIDs) != 0 {
+		parts = append(parts, fmt.Sprintf("userIds=%v", len(f.UserIDs)))
+	}
+	if f.MinIce > 0 {
+		parts = append(parts, fmt.Sprintf("minIce=%.2f", f.MinIce))
+	}
+	if f.MaxIce > 0 {
+		parts = append(parts, fmt.Sprintf("maxIce=%.2f", f.MaxIce))
 	}
 
-	return conditions, args
+	return strings.Join(parts, ",")
+}
+
+func likePrefixPattern(keyword string) string {
+	keyword = strings.ReplaceAll(keyword, "!", "!!")
+	keyword = strings.ReplaceAll(keyword, "%", "!%")
+	keyword = strings.ReplaceAll(keyword, "_", "!_")
+	keyword = strings.ReplaceAll(keyword, "[", "![")
+
+	return strings.ToLower(keyword + "%")
 }
 
 //nolint:funlen // .
-func (r *repository) ReviewCoinDistributions(ctx context.Context, reviewerUserID string, decision string) error {
+func (r *repository) ReviewCoinDistributions(
+	ctx context.Context, reviewerUserID string, arg *ReviewCoinDistributionsArg,
+) (votes *CoinDistributionsReviewVotes, err error) {
+	votes = new(CoinDistributionsReviewVotes)
+	if !arg.isEmpty() {
+		err = errors.Wrapf(r.reviewFilteredCoinDistributions(ctx, reviewerUserID, arg, votes), "failed to reviewFilteredCoinDistributions for %#v", arg)
+
+		return votes, err
+	}
 	const sqlToCheckIfAnythingNeedsApproving = "SELECT true AS bogus WHERE exists (select 1 FROM coin_distributions_pending_review LIMIT 1)"
-	switch strings.ToLower(decision) {
+	switch decision := arg.Decision; strings.ToLower(decision) {
 	case "approve":
-		return storage.DoInTransaction(ctx, r.db, func(conn storage.QueryExecer) error {
+		err = storage.DoInTransaction(ctx, r.db, func(conn storage.QueryExecer) error {
 			if _, err := storage.ExecOne[struct{ Bogus bool }](ctx, conn, sqlToCheckIfAnythingNeedsApproving); err != nil {
 				if storage.IsErr(err, storage.ErrNotFound) {
 					err = nil
@@ -179,6 +229,15 @@ func (r *repository) ReviewCoinDistributions(ctx context.Context, reviewerUserID
 
 				return errors.Wrap(err, "failed to check if any rows in coin_distributions_pending_review exist")
 			}
+			if err := ensureScreened(ctx, conn); err != nil {
+				return err
+			}
+			if err := ensureBudgetGuards(ctx, conn, reviewerUserID, arg.OverrideBudgetGuards); err != nil {
+				return err
+			}
+			if err := r.castReviewVote(ctx, conn, reviewerUserID, arg, votes); err != nil || !votes.Executed {
+				return err
+			}
 			totals, err := storage.ExecOne[struct {
 				Rows uint64
 				Ice  uint64
@@ -191,7 +250,7 @@ func (r *repository) ReviewCoinDistributions(ctx context.Context, reviewerUserID
 				"failed to sendCurrentCoinDistributionsAvailableForReviewAreApprovedSlackMessage")
 		})
 	case "approve-and-process-immediately":
-		return storage.DoInTransaction(ctx, r.db, func(conn storage.QueryExecer) error {
+		err = storage.DoInTransaction(ctx, r.db, func(conn storage.QueryExecer) error {
 			if _, err := storage.ExecOne[struct{ Bogus bool }](ctx, conn, sqlToCheckIfAnythingNeedsApproving); err != nil {
 				if storage.IsErr(err, storage.ErrNotFound) {
 					err = nil
@@ -199,6 +258,15 @@ func (r *repository) ReviewCoinDistributions(ctx context.Context, reviewerUserID
 
 				return errors.Wrap(err, "failed to check if any rows in coin_distributions_pending_review exist")
 			}
+			if err := ensureScreened(ctx, conn); err != nil {
+				return err
+			}
+			if err := ensureBudgetGuards(ctx, conn, reviewerUserID, arg.OverrideBudgetGuards); err != nil {
+				return err
+			}
+			if err := r.castReviewVote(ctx, conn, reviewerUserID, arg, votes); err != nil || !votes.Executed {
+				return err
+			}
 			totals, err := storage.ExecOne[struct {
 				Rows uint64
 				Ice  uint64
@@ -211,7 +279,7 @@ func (r *repository) ReviewCoinDistributions(ctx context.Context, reviewerUserID
 				"failed to sendCurrentCoinDistributionsAvailableForReviewAreApprovedToBeProcessedImmediatelySlackMessage")
 		})
 	case "deny":
-		return storage.DoInTransaction(ctx, r.db, func(conn storage.QueryExecer) error {
+		err = storage.DoInTransaction(ctx, r.db, func(conn storage.QueryExecer) error {
 			if _, err := storage.ExecOne[struct{ Bogus bool }](ctx, conn, sqlToCheckIfAnythingNeedsApproving); err != nil {
 				if storage.IsErr(err, storage.ErrNotFound) {
 					err = nil
@@ -219,6 +287,9 @@ func (r *repository) ReviewCoinDistributions(ctx context.Context, reviewerUserID
 
 				return errors.Wrap(err, "failed to check if any rows in coin_distributions_pending_review exist")
 			}
+			if err := r.castReviewVote(ctx, conn, reviewerUserID, arg, votes); err != nil {
+				return err
+			}
 			if _, err := storage.Exec(ctx, conn, "call deny_coin_distributions($1,true)", reviewerUserID); err != nil {
 				return errors.Wrap(err, "failed to call deny_coin_distributions")
 			}
@@ -230,7 +301,87 @@ func (r *repository) ReviewCoinDistributions(ctx context.Context, reviewerUserID
 		log.Panic(fmt.Sprintf("unknown decision:`%v`", decision))
 	}
 
-	return ctx.Err()
+	return votes, err
+}
+
+//nolint:funlen // .
+func (r *repository) reviewFilteredCoinDistributions(
+	ctx context.Context, reviewerUserID string, arg *ReviewCoinDistributionsArg, votes *CoinDistributionsReviewVotes,
+) error {
+	decision := strings.ToLower(arg.Decision)
+	var processImmediately, insertIntoPendingCoinDistributions bool
+	switch decision {
+	case "approve":
+		insertIntoPendingCoinDistributions = true
+	case "approve-and-process-immediately":
+		insertIntoPendingCoinDistributions, processImmediately = true, true
+	case "deny":
+	default:
+		log.Panic(fmt.Sprintf("unknown decision:`%v`", arg.Decision))
+	}
+	conditions, whereArgs := arg.where(3) //nolint:gomnd // $1 and $2 are the reviewer and the decision.
+	approvedCTE := ""
+	if insertIntoPendingCoinDistributions {
+		approvedCTE = `,
+		  approved AS (
+			  INSERT INTO pending_coin_distributions(created_at, internal_id, day, iceflakes, user_id, eth_address, target)
+			  SELECT created_at, internal_id, day, iceflakes, user_id, eth_address, coin_distribution_target(user_id)
+			  FROM reviewed
+		  )`
+	}
+	sql := fmt.Sprintf(`WITH reviewed AS (
+							DELETE FROM coin_distributions_pending_review
+							WHERE %[1]v
+							RETURNING *
+						 )%[2]v,
+						 history AS (
+							INSERT INTO reviewed_coin_distributions(reviewed_at, created_at, internal_id, ice, day, review_day, iceflakes, username, referred_by_username, user_id, eth_address, country, reviewer_user_id, decision)
+							SELECT current_timestamp, created_at, internal_id, ice, day, current_date, iceflakes, username, referred_by_username, user_id, eth_address, country, $1, $2
+							FROM reviewed
+						 )
+						SELECT count(1) AS rows,
+							   coalesce(sum(ice),0) AS ice
+						FROM reviewed`, strings.Join(conditions, " AND "), approvedCTE)
+
+	return storage.DoInTransaction(ctx, r.db, func(conn storage.QueryExecer) error {
+		if insertIntoPendingCoinDistributions {
+			if err := ensureScreened(ctx, conn); err != nil {
+				return err
+			}
+			if err := ensureBudgetGuards(ctx, conn, reviewerUserID, arg.OverrideBudgetGuards); err != nil {
+				return err
+			}
+		}
+		if err := r.castReviewVote(ctx, conn, reviewerUserID, arg, votes); err != nil || !votes.Executed {
+			return err
+		}
+		totals, err := storage.ExecOne[struct {
+			Rows uint64
+			Ice  uint64
+		}](ctx, conn, sql, append([]any{reviewerUserID, decision}, whereArgs...)...)
+		if err != nil {
+			return errors.Wrapf(err, "failed to review coin_distributions_pending_review matching %v with %v", &arg.CoinDistributionsForReviewFilter, decision)
+		}
+		if totals.Rows == 0 {
+			return nil
+		}
+		if processImmediately {
+			sql := `WITH targets AS (
+						UPDATE global SET value = 'true' WHERE starts_with(key, 'coin_distributer_forced_execution_') OR starts_with(key, 'coin_distributer_enabled_')
+					)
+					INSERT INTO global (key,value)
+							   VALUES ('coin_distributer_enabled','true'),
+									  ('coin_distributer_forced_execution','true')
+					ON CONFLICT (key) DO UPDATE
+							   SET value = EXCLUDED.value`
+			if _, err = storage.Exec(ctx, conn, sql); err != nil {
+				return errors.Wrap(err, "failed to enable coin distributer for immediate processing")
+			}
+		}
+
+		return errors.Wrap(r.sendFilteredCoinDistributionsReviewedSlackMessage(ctx, decision, arg.CoinDistributionsForReviewFilter.String(), totals.Rows, float64(totals.Ice)/100),
+			"failed to sendFilteredCoinDistributionsReviewedSlackMessage")
+	})
 }
 
 func (r *repository) CollectCoinDistributionsForReview(ctx context.Context, records []*ByEarnerForReview) error {
@@ -246,7 +397,7 @@ func (r *repository) CollectCoinDistributionsForReview(ctx context.Context, reco
 			log.Warn(fmt.Sprintf("(%#v) is a duplicate of (%#v)", record, otherRecord))
 		}
 	}
-	const columns = 9
+	const columns = 10
 	values := make([]string, 0, len(records))
 	args := make([]any, 0, len(records)*columns)
 	ix := 0
@@ -261,10 +412,11 @@ func (r *repository) CollectCoinDistributionsForReview(ctx context.Context, reco
 			record.ReferredByUsername,
 			record.UserID,
 			record.EarnerUserID,
-			record.EthAddress)
+			record.EthAddress,
+			record.Country)
 		ix++
 	}
-	sql := fmt.Sprintf(`INSERT INTO coin_distributions_by_earner(created_at,day,internal_id,balance,username,referred_by_username,user_id,earner_user_id,eth_address) 
+	sql := fmt.Sprintf(`INSERT INTO coin_distributions_by_earner(created_at,day,internal_id,balance,username,referred_by_username,user_id,earner_user_id,eth_address,country) 
 																 VALUES %v
 						ON CONFLICT (day, user_id, earner_user_id) DO UPDATE
 							SET 
@@ -272,7 +424,8 @@ func (r *repository) CollectCoinDistributionsForReview(ctx context.Context, reco
 								balance = EXCLUDED.balance,
 								username = EXCLUDED.username,
 								referred_by_username = EXCLUDED.referred_by_username,
-								eth_address = EXCLUDED.eth_address`, strings.Join(values, ",\n"))
+								eth_address = EXCLUDED.eth_address,
+								country = EXCLUDED.country`, strings.Join(values, ",\n"))
 	_, err := storage.Exec(ctx, r.db, sql, args...)
 
 	return errors.Wrapf(err, "failed to insert into coin_distributions_by_earner [%v]", len(records))
@@ -351,7 +504,17 @@ func (r *repository) GetCollectorSettings(ctx context.Context) (*CollectorSettin
 	}, nil
 }
 
-func tryPrepareCoinDistributionsForReview(ctx context.Context, db *storage.DB) error {
+func tryPrepareCoinDistributionsForReview(ctx context.Context, db *storage.DB, clients []ethClient) error {
+	if err := prepareCoinDistributionsForReview(ctx, db); err != nil {
+		return err
+	}
+
+	return errors.Wrap(screenCoinDistributionsForReview(ctx, db, clients), "failed to screenCoinDistributionsForReview")
+}
+
+// prepareCoinDistributionsForReview moves the collected coin distributions to `coin_distributions_pending_review`
+// and leaves them to screenCoinDistributionsForReview, which checks their eth addresses on-chain outside of this transaction.
+func prepareCoinDistributionsForReview(ctx context.Context, db *storage.DB) error {
 	return storage.DoInTransaction(ctx, db, func(conn storage.QueryExecer) error {
 		if _, err := storage.ExecOne[struct{ Bogus bool }](ctx, conn, "SELECT true AS bogus FROM global WHERE key = 'new_coin_distributions_pending' FOR UPDATE SKIP LOCKED"); err != nil {
 			if storage.IsErr(err, storage.ErrNotFound) {
@@ -364,6 +527,14 @@ func tryPrepareCoinDistributionsForReview(ctx context.Context, db *storage.DB) e
 		if _, err := storage.Exec(ctx, conn, "call prepare_coin_distributions_for_review(true)"); err != nil {
 			return errors.Wrap(err, "failed to call prepare_coin_distributions_for_review")
 		}
+		if _, err := storage.Exec(ctx, conn, "DELETE FROM coin_distribution_screened_addresses WHERE 1=1"); err != nil {
+			return errors.Wrap(err, "failed to reset coin_distribution_screened_addresses")
+		}
+		if _, err := storage.Exec(ctx, conn, `INSERT INTO global (key,value) VALUES ($1, current_timestamp::text)
+												ON CONFLICT (key) DO UPDATE
+													SET value = EXCLUDED.value`, configKeyCoinDistributionsScreeningPending); err != nil {
+			return errors.Wrapf(err, "failed to set global.key='%v'", configKeyCoinDistributionsScreeningPending)
+		}
 
 		if rowsDeleted, err := storage.Exec(ctx, conn, "DELETE FROM global where key = 'new_coin_distributions_pending'"); err != nil || rowsDeleted != 1 {
 			if err == nil {
@@ -373,11 +544,174 @@ func tryPrepareCoinDistributionsForReview(ctx context.Context, db *storage.DB) e
 			return errors.Wrap(err, "failed to del global.key='new_coin_distributions_pending'")
 		}
 
-		return errors.Wrap(sendNewCoinDistributionsAvailableForReviewSlackMessage(ctx), "failed to sendNewCoinDistributionsAvailableForReviewSlackMessage")
+		return nil
+	})
+}
+
+// screenCoinDistributionsForReview denies the coin distributions to be sent to contracts, on any of the chains we distribute to.
+// The progress is kept in `coin_distribution_screened_addresses`, so every run checks only the addresses that weren't yet:
+// an address that can't be checked is retried by the next runs, up to `screeningMaxAttempts` times, without holding back the others.
+// Once they're all done, the reviewers are told the coin distributions are available for review.
+//
+//nolint:funlen // .
+func screenCoinDistributionsForReview(ctx context.Context, db *storage.DB, clients []ethClient) error {
+	const (
+		selectStmt = `SELECT DISTINCT lower(p.eth_address)
+					  FROM coin_distributions_pending_review p
+						LEFT JOIN coin_distribution_screened_addresses s
+							   ON s.eth_address = lower(p.eth_address)
+					  WHERE s.eth_address IS NULL OR (s.contract IS NULL AND s.attempts < $1)`
+		unscreenedStmt = `SELECT count(1) FROM coin_distribution_screened_addresses WHERE contract IS NULL`
+		summaryStmt    = `SELECT screening_reason AS reason, count(1) AS rows
+						  FROM reviewed_coin_distributions
+						  WHERE reviewed_at >= (SELECT value::timestamptz FROM global WHERE key = $1) AND screening_reason IS NOT NULL
+						  GROUP BY 1
+						  ORDER BY 1`
+	)
+	if _, err := storage.ExecOne[string](ctx, db, `SELECT value FROM global WHERE key = $1`, configKeyCoinDistributionsScreeningPending); err != nil {
+		if storage.IsErr(err, storage.ErrNotFound) {
+			err = nil
+		}
+
+		return errors.Wrap(err, "failed to check if there are coin distributions to screen")
+	}
+	rows, err := storage.Select[string](ctx, db, selectStmt, screeningMaxAttempts)
+	if err != nil {
+		return errors.Wrap(err, "failed to select eth addresses to screen")
+	}
+	addresses := make([]string, 0, len(rows))
+	for _, address := range rows {
+		addresses = append(addresses, *address)
+	}
+	var failed uint64
+	for start := 0; start < len(addresses) && ctx.Err() == nil; start += screeningAddressesPerRequest {
+		chunk := addresses[start:min(start+screeningAddressesPerRequest, len(addresses))]
+		screened := make(map[string]*addressScreening, len(chunk))
+		for _, client := range clients {
+			screenContractAddresses(ctx, client, chunk, screened)
+		}
+		if err = storeScreenedAddresses(ctx, db, screened); err != nil {
+			return errors.Wrapf(err, "failed to store %v screened address(es)", len(chunk))
+		}
+		for _, screening := range screened {
+			if screening.Error != "" {
+				failed++
+			}
+		}
+	}
+	if ctx.Err() != nil {
+		return errors.Wrap(ctx.Err(), "screening interrupted")
+	}
+	if failed != 0 {
+		log.Warn(fmt.Sprintf("screening: failed to check %v eth address(es), they're retried by the next run", failed))
+		if rows, err = storage.Select[string](ctx, db, selectStmt, screeningMaxAttempts); err != nil || len(rows) != 0 {
+			return errors.Wrap(err, "failed to select eth addresses left to screen")
+		}
+	}
+	unscreened, err := storage.ExecOne[uint64](ctx, db, unscreenedStmt)
+	if err != nil {
+		return errors.Wrap(err, "failed to count unscreened eth addresses")
+	}
+	screened, err := storage.Select[screeningSummary](ctx, db, summaryStmt, configKeyCoinDistributionsScreeningPending)
+	if err != nil {
+		return errors.Wrap(err, "failed to select screened coin distributions")
+	}
+	if rowsDeleted, dErr := storage.Exec(ctx, db, "DELETE FROM global WHERE key = $1", configKeyCoinDistributionsScreeningPending); dErr != nil || rowsDeleted != 1 {
+		// Another instance finished it in the meantime.
+		return errors.Wrapf(dErr, "failed to del global.key='%v'", configKeyCoinDistributionsScreeningPending)
+	}
+
+	return errors.Wrap(sendNewCoinDistributionsAvailableForReviewSlackMessage(ctx, screened, *unscreened),
+		"failed to sendNewCoinDistributionsAvailableForReviewSlackMessage")
+}
+
+// screenContractAddresses checks which of the addresses are contracts on the chain of the client.
+// If the addresses can't be checked all at once, they're checked one by one, so only the ones at fault are left unchecked.
+func screenContractAddresses(ctx context.Context, client ethClient, addresses []string, screened map[string]*addressScreening) {
+	contracts, err := client.ContractAddresses(ctx, addresses)
+	if err != nil && len(addresses) > 1 {
+		log.Error(errors.Wrapf(err, "failed to check if %v address(es) are contracts, checking them one by one", len(addresses)))
+		for _, address := range addresses {
+			screenContractAddresses(ctx, client, []string{address}, screened)
+		}
+
+		return
+	}
+	for _, address := range addresses {
+		if screened[address] == nil {
+			screened[address] = new(addressScreening)
+		}
+	}
+	if err != nil {
+		screened[addresses[0]].Error = err.Error()
+
+		return
+	}
+	for _, address := range contracts {
+		screened[address].Contract = true
+	}
+}
+
+// storeScreenedAddresses records the progress of the screening and denies the coin distributions to the contracts found.
+func storeScreenedAddresses(ctx context.Context, db *storage.DB, screened map[string]*addressScreening) error {
+	const (
+		progressStmt = `INSERT INTO coin_distribution_screened_addresses(screened_at, eth_address, contract, error, attempts)
+							SELECT current_timestamp, eth_address, contract, error, 1
+							FROM unnest($1::text[], $2::boolean[], $3::text[]) AS t(eth_address, contract, error)
+						ON CONFLICT (eth_address) DO UPDATE
+							SET screened_at = EXCLUDED.screened_at,
+								contract = EXCLUDED.contract,
+								error = EXCLUDED.error,
+								attempts = coin_distribution_screened_addresses.attempts + 1`
+		denyStmt = `WITH del AS (
+						 DELETE FROM coin_distributions_pending_review WHERE lower(eth_address) = ANY($1) RETURNING *
+					  )
+					  INSERT INTO reviewed_coin_distributions(reviewed_at, created_at, internal_id, ice, day, review_day, iceflakes, username, referred_by_username, user_id, eth_address, country, reviewer_user_id, decision, screening_reason)
+					  SELECT current_timestamp, created_at, internal_id, ice, day, current_date, iceflakes, username, referred_by_username, user_id, eth_address, country, 'system', 'deny', $2
+					  FROM del`
+	)
+	addresses, errs := make([]string, 0, len(screened)), make([]string, 0, len(screened))
+	contracts, denied := make([]*bool, 0, len(screened)), make([]string, 0)
+	for address, screening := range screened {
+		contract := screening.Contract
+		if contract {
+			denied = append(denied, address)
+		} else if screening.Error != "" {
+			// Unknown, unless any other chain says it's a contract.
+			addresses, contracts, errs = append(addresses, address), append(contracts, nil), append(errs, screening.Error)
+
+			continue
+		}
+		addresses, contracts, errs = append(addresses, address), append(contracts, &contract), append(errs, "")
+	}
+
+	return storage.DoInTransaction(ctx, db, func(conn storage.QueryExecer) error {
+		if _, err := storage.Exec(ctx, conn, progressStmt, addresses, contracts, errs); err != nil {
+			return errors.Wrap(err, "failed to insert coin_distribution_screened_addresses")
+		}
+		if len(denied) == 0 {
+			return nil
+		}
+		_, err := storage.Exec(ctx, conn, denyStmt, denied, screeningReasonContractAddress)
+
+		return errors.Wrapf(err, "failed to deny coin distributions to %v contract(s)", len(denied))
 	})
 }
 
-func startPrepareCoinDistributionsForReviewMonitor(ctx context.Context, db *storage.DB) {
+// ensureScreened blocks the approvals until the screening of the coin distributions pending review is done.
+func ensureScreened(ctx context.Context, conn storage.QueryExecer) error {
+	if _, err := storage.ExecOne[string](ctx, conn, `SELECT value FROM global WHERE key = $1`, configKeyCoinDistributionsScreeningPending); err != nil {
+		if storage.IsErr(err, storage.ErrNotFound) {
+			return nil
+		}
+
+		return errors.Wrap(err, "failed to check if the screening is done")
+	}
+
+	return ErrScreeningInProgress
+}
+
+func startPrepareCoinDistributionsForReviewMonitor(ctx context.Context, db *storage.DB, clients []ethClient) {
 	ticker := stdlibtime.NewTicker(30 * stdlibtime.Second) //nolint:gomnd // .
 	defer ticker.Stop()
 
@@ -385,7 +719,7 @@ func startPrepareCoinDistributionsForReviewMonitor(ctx context.Context, db *stor
 		select {
 		case <-ticker.C:
 			reqCtx, cancel := context.WithTimeout(ctx, 10*stdlibtime.Minute) //nolint:gomnd // .
-			log.Error(errors.Wrap(tryPrepareCoinDistributionsForReview(reqCtx, db), "failed to tryPrepareCoinDistributionsForReview"))
+			log.Error(errors.Wrap(tryPrepareCoinDistributionsForReview(reqCtx, db, clients), "failed to tryPrepareCoinDistributionsForReview"))
 			cancel()
 		case <-ctx.Done():
 			return
@@ -0,0 +1,240 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package coindistribution
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ice-blockchain/wintr/connectors/storage/v2"
+)
+
+type (
+	mockedContractsEthClient struct {
+		mockedDummyEthClient
+		contracts []string
+		failing   []string
+	}
+)
+
+func (m *mockedContractsEthClient) ContractAddresses(_ context.Context, addresses []string) ([]string, error) {
+	var found []string
+	for _, address := range addresses {
+		for _, failing := range m.failing {
+			if strings.EqualFold(address, failing) {
+				return nil, errors.Errorf("failed to get code at %v", address)
+			}
+		}
+		for _, contract := range m.contracts {
+			if strings.EqualFold(address, contract) {
+				found = append(found, address)
+			}
+		}
+	}
+
+	return found, nil
+}
+
+func TestCoinDistributionsForReviewFilterWhere(t *testing.T) {
+	t.Parallel()
+
+	filter := new(CoinDistributionsForReviewFilter)
+	assert.True(t, filter.isEmpty())
+	conditions, args := filter.where(3)
+	assert.Empty(t, conditions)
+	assert.Empty(t, args)
+
+	filter = &CoinDistributionsForReviewFilter{
+		UsernameKeyword:           "J_doe%",
+		ReferredByUsernameKeyword: "bo!b",
+		UserIDs:                   []string{"a", "b"},
+		MinIce:                    1.5,
+		MaxIce:                    100,
+	}
+	assert.False(t, filter.isEmpty())
+	conditions, args = filter.where(3)
+	assert.EqualValues(t, []string{
+		"referred_by_username LIKE $3 ESCAPE '!'",
+		"username LIKE $4 ESCAPE '!'",
+		"user_id = ANY($5)",
+		"ice >= $6",
+		"ice <= $7",
+	}, conditions)
+	assert.EqualValues(t, []any{"bo!!b%", "j!_doe!%%", []string{"a", "b"}, int64(150), int64(10000)}, args)
+	assert.Equal(t, "referredByUsernameKeyword=bo!b,usernameKeyword=J_doe%,userIds=2,minIce=1.50,maxIce=100.00", filter.String())
+
+	conditions, args = (&GetCoinDistributionsForReviewArg{CoinDistributionsForReviewFilter: CoinDistributionsForReviewFilter{MaxIce: 1}}).totalsWhere()
+	assert.EqualValues(t, []string{"ice <= $1"}, conditions)
+	assert.EqualValues(t, []any{int64(100)}, args)
+
+	_, args = (&CoinDistributionsForReviewFilter{MinIce: 0.29, MaxIce: 1.13}).where(1)
+	assert.EqualValues(t, []any{int64(29), int64(113)}, args)
+}
+
+func TestGetCoinDistributionPayoutsArgWhere(t *testing.T) {
+	t.Parallel()
+
+	conditions, args := new(GetCoinDistributionPayoutsArg).where(3)
+	assert.Empty(t, conditions)
+	assert.Empty(t, args)
+
+	conditions, args = (&GetCoinDistributionPayoutsArg{UserID: "a", EthAddress: "0xAb"}).where(3)
+	assert.EqualValues(t, []string{"user_id = $3", "lower(eth_address) = lower($4)"}, conditions)
+	assert.EqualValues(t, []any{"a", "0xAb"}, args)
+
+	conditions, args = (&GetCoinDistributionPayoutsArg{EthAddress: "0xAb"}).where(1)
+	assert.EqualValues(t, []string{"lower(eth_address) = lower($1)"}, conditions)
+	assert.EqualValues(t, []any{"0xAb"}, args)
+}
+
+func TestScreenCoinDistributionsForReview(t *testing.T) { //nolint:paralleltest,funlen // .
+	maybeSkipTest(t)
+	ctx := context.TODO()
+	db := storage.MustConnect(ctx, ddl, applicationYamlKey)
+	defer db.Close()
+
+	prefix := RandStringBytes(8)
+	address := func(suffix int) string {
+		return fmt.Sprintf("0x%040x", suffix+int(prefix[0])*1_000)
+	}
+	valid, contract, denylisted, shared := address(1), address(2), address(3), address(4)
+	recipients := map[string]string{
+		prefix + "valid":      valid,
+		prefix + "zero":       "0x0000000000000000000000000000000000000000",
+		prefix + "invalid":    "skip",
+		prefix + "contract":   contract,
+		prefix + "denylisted": denylisted,
+	}
+	for i := range 4 {
+		recipients[fmt.Sprintf("%vshared%v", prefix, i)] = shared
+	}
+
+	_, err := storage.Exec(ctx, db, `UPDATE global SET value = '3' WHERE key = 'coin_distributer_max_users_per_eth_address'`)
+	require.NoError(t, err)
+	_, err = storage.Exec(ctx, db, `INSERT INTO coin_distribution_denylisted_eth_addresses(eth_address) VALUES ($1) ON CONFLICT DO NOTHING`, denylisted)
+	require.NoError(t, err)
+	for userID, ethAddress := range recipients {
+		_, err = storage.Exec(ctx, db, `INSERT INTO coin_distributions_by_earner(created_at, internal_id, balance, day, username, referred_by_username, user_id, earner_user_id, eth_address)
+										VALUES (current_timestamp, 1, 100, current_date, $1, '', $1, $1, $2)`, userID, ethAddress)
+		require.NoError(t, err)
+	}
+
+	_, err = storage.Exec(ctx, db, `INSERT INTO global(key, value) VALUES ('new_coin_distributions_pending', 'true') ON CONFLICT DO NOTHING`)
+	require.NoError(t, err)
+	require.NoError(t, prepareCoinDistributionsForReview(ctx, db))
+	// The valid address can't be checked on one of the chains, so the screening isn't done, but the contract is denied anyway.
+	clients := []ethClient{
+		&mockedContractsEthClient{contracts: []string{contract}},
+		&mockedContractsEthClient{failing: []string{valid}},
+	}
+	require.NoError(t, screenCoinDistributionsForReview(ctx, db, clients))
+	require.ErrorIs(t, ensureScreened(ctx, db), ErrScreeningInProgress)
+	progress, err := storage.Select[struct {
+		EthAddress string
+		Error      string
+		Contract   *bool
+		Attempts   int64
+	}](ctx, db, `SELECT eth_address, contract, error, attempts FROM coin_distribution_screened_addresses WHERE eth_address = ANY($1)`, []string{valid, contract})
+	require.NoError(t, err)
+	require.Len(t, progress, 2)
+	for _, screened := range progress {
+		if screened.EthAddress == valid {
+			assert.Nil(t, screened.Contract)
+			assert.Contains(t, screened.Error, valid)
+		} else {
+			assert.True(t, *screened.Contract)
+			assert.Empty(t, screened.Error)
+		}
+		assert.EqualValues(t, 1, screened.Attempts)
+	}
+
+	reasons, err := storage.Select[struct {
+		UserID          string
+		ScreeningReason string
+	}](ctx, db, `SELECT user_id, screening_reason FROM reviewed_coin_distributions WHERE starts_with(user_id, $1) AND screening_reason IS NOT NULL`, prefix)
+	require.NoError(t, err)
+	actual := make(map[string]string, len(reasons))
+	for _, reason := range reasons {
+		actual[strings.TrimPrefix(reason.UserID, prefix)] = reason.ScreeningReason
+	}
+	assert.EqualValues(t, map[string]string{
+		"zero":       "zero-address",
+		"invalid":    "invalid-address",
+		"contract":   screeningReasonContractAddress,
+		"denylisted": "denylisted-address",
+		"shared0":    "shared-address",
+		"shared1":    "shared-address",
+		"shared2":    "shared-address",
+		"shared3":    "shared-address",
+	}, actual)
+
+	pending, err := storage.Select[string](ctx, db, `SELECT user_id FROM coin_distributions_pending_review WHERE starts_with(user_id, $1)`, prefix)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, prefix+"valid", *pending[0])
+}
+
+func TestScreenContractAddresses(t *testing.T) {
+	t.Parallel()
+
+	addresses := []string{"0x01", "0x02", "0x03", "0x04"}
+	screened := make(map[string]*addressScreening, len(addresses))
+	screenContractAddresses(context.Background(), &mockedContractsEthClient{contracts: []string{"0x02"}}, addresses, screened)
+	screenContractAddresses(context.Background(), &mockedContractsEthClient{contracts: []string{"0x04"}, failing: []string{"0x01", "0x04"}}, addresses, screened)
+
+	assert.Equal(t, map[string]*addressScreening{
+		"0x01": {Error: "failed to get code at 0x01"},
+		"0x02": {Contract: true},
+		"0x03": {},
+		"0x04": {Error: "failed to get code at 0x04"},
+	}, screened)
+}
+
+func TestCastReviewVote(t *testing.T) { //nolint:paralleltest // .
+	maybeSkipTest(t)
+	ctx := context.TODO()
+	db := storage.MustConnect(ctx, ddl, applicationYamlKey)
+	defer db.Close()
+	repo := &repository{db: db, cfg: new(config)}
+
+	userID := RandStringBytes(8)
+	_, err := storage.Exec(ctx, db, `INSERT INTO coin_distributions_pending_review(created_at, internal_id, ice, day, iceflakes, username, referred_by_username, user_id, eth_address)
+									 VALUES (current_timestamp, 1, 100, current_date, 1000000000000000000, $1, '', $1, '0x0000000000000000000000000000000000000001')`, userID)
+	require.NoError(t, err)
+	_, err = storage.Exec(ctx, db, `UPDATE global SET value = '1' WHERE key = $1`, configKeyCoinDistributerReviewQuorum)
+	require.NoError(t, err)
+
+	arg := &ReviewCoinDistributionsArg{
+		Decision:                         "approve",
+		CoinDistributionsForReviewFilter: CoinDistributionsForReviewFilter{UserIDs: []string{userID}},
+		Snapshot:                         "bogus",
+	}
+	snapshot, err := reviewSnapshot(ctx, db, &arg.CoinDistributionsForReviewFilter)
+	require.NoError(t, err)
+
+	require.ErrorIs(t, storage.DoInTransaction(ctx, db, func(conn storage.QueryExecer) error {
+		return repo.castReviewVote(ctx, conn, "reviewer1", arg, new(CoinDistributionsReviewVotes))
+	}), ErrReviewSnapshotChanged)
+
+	arg.Snapshot = snapshot
+	votes := new(CoinDistributionsReviewVotes)
+	require.NoError(t, storage.DoInTransaction(ctx, db, func(conn storage.QueryExecer) error {
+		return repo.castReviewVote(ctx, conn, "reviewer1", arg, votes)
+	}))
+	assert.True(t, votes.Executed)
+	assert.Equal(t, snapshot, votes.Snapshot)
+	require.Len(t, votes.Votes, 1)
+	assert.Equal(t, "reviewer1", votes.Votes[0].ReviewerUserID)
+
+	open, err := repo.getOpenReviewVotes(ctx)
+	require.NoError(t, err)
+	for _, vote := range open {
+		assert.NotEqual(t, snapshot, vote.Snapshot)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"sync"
 	stdlibtime "time"
 
+	"github.com/ethereum/go-ethereum/core/txpool"
 	"github.com/hashicorp/go-multierror"
 	"github.com/oklog/ulid/v2"
 	"github.com/pkg/errors"
@@ -19,15 +20,23 @@ import (
 )
 
 func newCoinProcessor(client ethClient, db *storage.DB, conf *config) *coinProcessor {
+	return newTargetCoinProcessor(client, db, conf.DefaultTarget())
+}
+
+func newTargetCoinProcessor(client ethClient, db *storage.DB, target *distributionTarget) *coinProcessor {
 	proc := &coinProcessor{
 		Client:         client,
-		Conf:           conf,
+		Target:         target,
 		WG:             new(sync.WaitGroup),
 		CancelSignal:   make(chan struct{}),
 		databaseConfig: &databaseConfig{DB: db},
+		batchSize:      maxBatchSize,
+		leaderID:       newLeaderID(),
 	}
 	proc.gasPriceCache.mu = new(sync.RWMutex)
 	proc.gasPriceCache.time = time.New(stdlibtime.Time{})
+	proc.gasFeesCache.mu = new(sync.Mutex)
+	proc.gasFeesCache.time = time.New(stdlibtime.Time{})
 
 	return proc
 }
@@ -81,33 +90,238 @@ func (proc *coinProcessor) GetGasPrice(ctx context.Context) (value *big.Int, err
 	return value, nil
 }
 
-func (proc *coinProcessor) BatchMarkAccepted(ctx context.Context, data *batch, txHash string) error {
+// GetGasFees returns the base fee of the next block and the suggested max priority fee per gas, both are cached for a while.
+func (proc *coinProcessor) GetGasFees(ctx context.Context) (baseFee, tipCap *big.Int, err error) {
+	proc.gasFeesCache.mu.Lock()
+	defer proc.gasFeesCache.mu.Unlock()
+	if proc.gasFeesCache.baseFee != nil && stdlibtime.Since(*proc.gasFeesCache.time.Time) < gasPriceCacheTTL {
+		return proc.gasFeesCache.baseFee, proc.gasFeesCache.tipCap, nil
+	}
+
+	if baseFee, tipCap, err = proc.Client.SuggestGasFees(ctx); err != nil {
+		return nil, nil, errors.Wrap(err, "failed to get gas fees")
+	}
+
+	log.Info(fmt.Sprintf("gas fees were updated from %v/%v to %v/%v",
+		proc.gasFeesCache.baseFee, proc.gasFeesCache.tipCap, baseFee.String(), tipCap.String()))
+	proc.gasFeesCache.baseFee = baseFee
+	proc.gasFeesCache.tipCap = tipCap
+	proc.gasFeesCache.time = time.Now()
+
+	return baseFee, tipCap, nil
+}
+
+// dynamicGasFees returns the max fee per gas and max priority fee per gas to use for the given base fee and suggested priority fee.
+// The caps are ignored if 0. It fails with errGasFeeCapExceeded if the TX would not be included under the max fee per gas cap.
+func dynamicGasFees(baseFee, suggestedTipCap *big.Int, maxFeeCap, maxTipCap uint64) (feeCap, tipCap *big.Int, err error) {
+	tipCap = new(big.Int).Set(suggestedTipCap)
+	if maxTipCap != 0 && tipCap.Cmp(new(big.Int).SetUint64(maxTipCap)) > 0 {
+		tipCap.SetUint64(maxTipCap)
+	}
+
+	feeCap = new(big.Int).Mul(baseFee, big.NewInt(baseFeeMultiplier))
+	feeCap.Add(feeCap, tipCap)
+	if maxFeeCap == 0 || feeCap.Cmp(new(big.Int).SetUint64(maxFeeCap)) <= 0 {
+		return feeCap, tipCap, nil
+	}
+
+	if required := new(big.Int).Add(baseFee, tipCap); required.Cmp(new(big.Int).SetUint64(maxFeeCap)) > 0 {
+		return nil, nil, errors.Wrapf(errGasFeeCapExceeded, "base fee %v + priority fee %v > %v", baseFee, tipCap, maxFeeCap)
+	}
+
+	return feeCap.SetUint64(maxFeeCap), tipCap, nil
+}
+
+func (proc *coinProcessor) BatchMarkAccepted(ctx context.Context, data *batch, tx *airdropTransaction) error {
 	const stmt = `
 update pending_coin_distributions
 set
 	eth_status = 'ACCEPTED',
-	eth_tx = $1
+	eth_tx = $1,
+	eth_nonce = $2,
+	eth_tx_gas_price = $3::text::uint256,
+	eth_tx_gas_tip_cap = $4::text::uint256,
+	eth_tx_sent_at = $5,
+	eth_gas_estimate = $6,
+	eth_batch_id = $7,
+	eth_replaced_txs = $11,
+	eth_fencing_token = $10
 where
 	eth_status = 'PENDING' and
-	user_id = ANY($2)
+	target = $8 and
+	user_id = ANY($9) and
+	coalesce(eth_fencing_token, 0) <= $10
+`
+
+	sentAt := time.Now()
+	// Any of the attempts signed for the batch can still be mined instead of the accepted one.
+	replaced := data.OtherHashes(tx.Hash)
+	err := fenced(storage.Exec(ctx, proc.DB, stmt,
+		tx.Hash, int64(tx.Nonce), tx.GasPrice.String(), tx.TipCapText(), sentAt.Time, int64(data.GasEstimate), data.ID, proc.Target.Name, data.Users(),
+		proc.FencingToken(), replaced))
+	data.ReplacedTXs = replaced
+	data.SetAccepted(tx, sentAt)
+
+	return errors.Wrapf(err, "failed to mark batch %v with TX %v as accepted", data.ID, tx.Hash)
+}
+
+func (proc *coinProcessor) BatchMarkReplaced(ctx context.Context, data *batch, tx *airdropTransaction) error {
+	const stmt = `
+update pending_coin_distributions
+set
+	eth_tx = $1,
+	eth_tx_gas_price = $2::text::uint256,
+	eth_tx_gas_tip_cap = $3::text::uint256,
+	eth_tx_sent_at = $4,
+	eth_replaced_txs = $7,
+	eth_fencing_token = $6
+where
+	eth_status IN ('ACCEPTED', 'REVERIFY') and
+	eth_tx = $5 and
+	coalesce(eth_fencing_token, 0) <= $6
 `
 
-	_, err := storage.Exec(ctx, proc.DB, stmt, txHash, data.Users())
-	data.SetAccepted(txHash)
+	sentAt := time.Now()
+	replaced := data.OtherHashes(tx.Hash)
+	err := fenced(storage.Exec(ctx, proc.DB, stmt, tx.Hash, tx.GasPrice.String(), tx.TipCapText(), sentAt.Time, data.TX, proc.FencingToken(), replaced))
+	if err != nil {
+		return errors.Wrapf(err, "failed to replace TX %v of batch %v with %v", data.TX, data.ID, tx.Hash)
+	}
+	data.ReplacedTXs = replaced
+	data.SetAccepted(tx, sentAt)
 
-	return errors.Wrapf(err, "failed to mark batch %v with TX %v as accepted", data.ID, txHash)
+	return nil
 }
 
-func (proc *coinProcessor) BatchMarkRejected(ctx context.Context, data *batch) error {
+// BatchMarkSigned stores the TX signed for the batch before it's sent, so the batch is never left without it,
+// whatever the outcome of sending it is. The TXs signed before it for the same nonce are kept as replaced ones.
+func (proc *coinProcessor) BatchMarkSigned(ctx context.Context, data *batch, tx *airdropTransaction) error {
 	const stmt = `
 update pending_coin_distributions
 set
-	eth_status = 'REJECTED'
+	eth_tx = $1,
+	eth_nonce = $2,
+	eth_batch_id = $3,
+	eth_replaced_txs = $4,
+	eth_fencing_token = $7
 where
 	eth_status = 'PENDING' and
-	user_id = ANY($1)
+	target = $5 and
+	user_id = ANY($6) and
+	coalesce(eth_fencing_token, 0) <= $7
 `
-	_, err := storage.Exec(ctx, proc.DB, stmt, data.Users())
+
+	replaced := data.ReplacedTXs
+	if data.TX != "" {
+		replaced = append(replaced, data.TX)
+	}
+	err := fenced(storage.Exec(ctx, proc.DB, stmt, tx.Hash, int64(tx.Nonce), data.ID, replaced, proc.Target.Name, data.Users(), proc.FencingToken()))
+	if err != nil {
+		return errors.Wrapf(err, "failed to mark batch %v with signed TX %v", data.ID, tx.Hash)
+	}
+	data.TX, data.ReplacedTXs, data.Signed = tx.Hash, replaced, tx
+	for idx := range data.Records {
+		data.Records[idx].EthTX = &tx.Hash
+	}
+
+	return nil
+}
+
+// BatchMarkReplacementSigned adds the replacement signed for the batch to its replaced TXs before it's sent, it becomes the TX of the batch once it's accepted.
+func (proc *coinProcessor) BatchMarkReplacementSigned(ctx context.Context, data *batch, tx *airdropTransaction) error {
+	const stmt = `
+update pending_coin_distributions
+set
+	eth_replaced_txs = array_append(coalesce(eth_replaced_txs, '{}'), $1),
+	eth_fencing_token = $3
+where
+	eth_status IN ('ACCEPTED', 'REVERIFY') and
+	eth_tx = $2 and
+	coalesce(eth_fencing_token, 0) <= $3
+`
+
+	if err := fenced(storage.Exec(ctx, proc.DB, stmt, tx.Hash, data.TX, proc.FencingToken())); err != nil {
+		return errors.Wrapf(err, "failed to mark batch %v with signed replacement %v", data.ID, tx.Hash)
+	}
+	data.ReplacedTXs = append(data.ReplacedTXs, tx.Hash)
+
+	return nil
+}
+
+// BatchMarkMined remembers the TX of the batch that was mined and its block, so we can check the block is still there once it's deep enough.
+func (proc *coinProcessor) BatchMarkMined(ctx context.Context, data *batch, hash string, receipt *txReceipt) error {
+	const stmt = `
+update pending_coin_distributions
+set
+	eth_status = 'ACCEPTED',
+	eth_mined_tx = $1,
+	eth_mined_block_hash = $2,
+	eth_mined_block_number = $3,
+	eth_fencing_token = $5
+where
+	eth_status IN ('ACCEPTED', 'REVERIFY') and
+	eth_tx = $4 and
+	coalesce(eth_fencing_token, 0) <= $5
+`
+
+	err := fenced(storage.Exec(ctx, proc.DB, stmt, hash, receipt.BlockHash, int64(receipt.BlockNumber), data.TX, proc.FencingToken()))
+	if err != nil {
+		return errors.Wrapf(err, "failed to mark TX %v of batch %v as mined in block %v", hash, data.ID, receipt.BlockHash)
+	}
+	data.Mined = &minedTransaction{Hash: hash, BlockHash: receipt.BlockHash, BlockNumber: receipt.BlockNumber}
+	data.SetStatus(ethApiStatusAccepted)
+
+	return nil
+}
+
+// BatchMarkReorged puts the batch whose block was reorged out to REVERIFY, it's tracked as in-flight until one of its TXs is mined again.
+func (proc *coinProcessor) BatchMarkReorged(ctx context.Context, data *batch) error {
+	const stmt = `
+update pending_coin_distributions
+set
+	eth_status = 'REVERIFY',
+	eth_mined_tx = null,
+	eth_mined_block_hash = null,
+	eth_mined_block_number = null,
+	eth_tx_sent_at = $1,
+	eth_fencing_token = $3
+where
+	eth_status = 'ACCEPTED' and
+	eth_tx = $2 and
+	coalesce(eth_fencing_token, 0) <= $3
+`
+
+	sentAt := time.Now()
+	if err := fenced(storage.Exec(ctx, proc.DB, stmt, sentAt.Time, data.TX, proc.FencingToken())); err != nil {
+		return errors.Wrapf(err, "failed to mark batch %v as reorged", data.ID)
+	}
+	log.Warn(fmt.Sprintf("batch %v: block %v (#%v) with transaction %v was reorged out",
+		data.ID, data.Mined.BlockHash, data.Mined.BlockNumber, data.Mined.Hash))
+	log.Error(errors.Wrap(sendCoinDistributerTransactionReorged(ctx, proc.Target.Name, data.Mined),
+		"failed to sendCoinDistributerTransactionReorged"))
+	// The TX is back in the pool of the node, or dropped, so it's given the whole replacement timeout again.
+	data.Mined, data.SentAt = nil, sentAt
+	data.SetStatus(ethApiStatusReverify)
+
+	return nil
+}
+
+func (proc *coinProcessor) BatchMarkRejected(ctx context.Context, data *batch, cause error) error {
+	const stmt = `
+update pending_coin_distributions
+set
+	eth_status = 'REJECTED',
+	eth_batch_id = $1,
+	eth_rejected_at = $2,
+	eth_error = $3,
+	eth_fencing_token = $6
+where
+	eth_status = 'PENDING' and
+	target = $4 and
+	user_id = ANY($5) and
+	coalesce(eth_fencing_token, 0) <= $6
+`
+	err := fenced(storage.Exec(ctx, proc.DB, stmt, data.ID, time.Now().Time, cause.Error(), proc.Target.Name, data.Users(), proc.FencingToken()))
 	data.SetStatus(ethApiStatusRejected)
 
 	return errors.Wrapf(err, "failed to mark batch %v with as rejected", data.ID)
@@ -121,7 +335,9 @@ with records as (
 	from
 		pending_coin_distributions
 	where
-		eth_status = 'NEW'
+		eth_status = 'NEW' and
+		target = $2 and
+		exists (select 1 from global where key = $3 and value::jsonb->>'holder' = $4 and (value::jsonb->>'token')::bigint = $5)
 	order by
 		created_at ASC
 	limit $1
@@ -129,52 +345,261 @@ with records as (
 )
 update pending_coin_distributions up
 set
-	eth_status = 'PENDING'
+	eth_status = 'PENDING',
+	eth_fencing_token = $5
 from
 	records
 where
-	up.user_id = records.user_id
+	up.user_id = records.user_id and
+	up.target = $2
 returning up.*
 `
 
-	result, err := storage.ExecMany[batchRecord](ctx, proc.DB, stmt, batchSize)
+	limit := proc.batchSize
+	if proc.isMerkleMode() {
+		// The whole cycle goes into a single tree, the only TX is the one publishing its root.
+		limit = maxMerkleTreeRecords
+	}
+	result, err := storage.ExecMany[batchRecord](ctx, proc.DB, stmt,
+		limit, proc.Target.Name, proc.targetKey(configKeyCoinDistributerLeaderLease), proc.leaderID, proc.FencingToken())
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to fetch pending coin distributions")
 	} else if len(result) == 0 {
 		return nil, errNotEnoughData
 	}
 
-	return &batch{
+	data := &batch{
 		ID:      ulid.Make().String(),
 		Records: result,
-	}, nil
+	}
+	if proc.isMerkleMode() {
+		err = proc.MerkleTreePrepare(ctx, data)
+	} else {
+		err = proc.BatchFitGas(ctx, data)
+	}
+	if err != nil {
+		log.Error(errors.Wrapf(proc.BatchRelease(ctx, data.Records), "failed to release batch %v", data.ID))
+
+		return nil, err
+	}
+
+	return data, nil
 }
 
-func (proc *coinProcessor) DeleteTransactions(ctx context.Context, hash string) error {
-	const stmt = `delete from pending_coin_distributions where eth_status = 'ACCEPTED' and eth_tx = $1`
+// BatchRelease puts the records back to the queue, so they're picked up by one of the next batches.
+func (proc *coinProcessor) BatchRelease(ctx context.Context, records []*batchRecord) error {
+	const stmt = `
+update pending_coin_distributions
+set
+	eth_status = 'NEW'
+where
+	eth_status = 'PENDING' and
+	target = $1 and
+	user_id = ANY($2) and
+	coalesce(eth_fencing_token, 0) <= $3
+`
+	if len(records) == 0 {
+		return nil
+	}
+
+	err := fenced(storage.Exec(ctx, proc.DB, stmt, proc.Target.Name, (&batch{Records: records}).Users(), proc.FencingToken()))
+
+	return errors.Wrapf(err, "failed to release %v record(s)", len(records))
+}
+
+// GetAllowedBatchGas returns how much gas an airdrop TX can use: the block gas limit minus the safety margin,
+// but no more than `coin_distributer_gas_limit_units`, if set.
+func (proc *coinProcessor) GetAllowedBatchGas(ctx context.Context) (uint64, error) {
+	blockGasLimit, err := proc.Client.BlockGasLimit(ctx)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get block gas limit")
+	}
+	margin, err := proc.GetBlockGasLimitMargin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	gasLimit, err := proc.GetGasLimit(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	allowed := blockGasLimit - blockGasLimit*min(margin, 100)/100 //nolint:gomnd // Percent.
+	if gasLimit != 0 && gasLimit < allowed {
+		allowed = gasLimit
+	}
 
-	r, err := storage.Exec(ctx, proc.DB, stmt, hash)
+	return allowed, nil
+}
+
+// BatchFitGas estimates the gas of the batch airdrop and drops the newest users until it fits in the allowed gas.
+// The next batch is sized based on the estimate of this one.
+func (proc *coinProcessor) BatchFitGas(ctx context.Context, data *batch) error { //nolint:funlen //.
+	allowed, err := proc.GetAllowedBatchGas(ctx)
 	if err != nil {
-		return errors.Wrap(err, "failed to delete transactions")
+		return err
 	}
 
-	log.Info(fmt.Sprintf("transaction: %v: deleted: %v", hash, r))
+	for {
+		// All the records of a user go to the same batch, they're released by user.
+		size := data.UserCount()
+		recipients, amounts := data.Prepare()
+		estimate, eErr := proc.Client.EstimateAirdropGas(ctx, recipients, amounts)
+		switch {
+		case eErr == nil:
+			data.GasEstimate = estimate
+			if data.GasLimit() <= allowed {
+				proc.batchSize = max(1, fitBatchSize(size, data.GasLimit(), allowed))
+				log.Info(fmt.Sprintf("batch %v: %v record(s) of %v user(s), estimated gas %v of %v allowed, next batch size %v",
+					data.ID, len(data.Records), size, estimate, allowed, proc.batchSize))
+
+				return nil
+			}
+			size = min(fitBatchSize(size, data.GasLimit(), allowed), size-1)
+
+		case errors.Is(eErr, errGasEstimation) && size > 1:
+			// Most likely it does not fit in a block at all, so we have no estimate to scale it with.
+			log.Warn(fmt.Sprintf("batch %v: failed to estimate gas for %v record(s): %v", data.ID, size, eErr))
+			size /= 2
+
+		default:
+			return errors.Wrapf(eErr, "failed to estimate gas for batch %v", data.ID)
+		}
+
+		if size == 0 {
+			return errors.Errorf("batch %v: a single record needs more than %v gas", data.ID, allowed)
+		}
+		log.Info(fmt.Sprintf("batch %v: shrinking from %v to %v user(s)", data.ID, data.UserCount(), size))
+		kept, released := data.SplitUsers(size)
+		if err = proc.BatchRelease(ctx, released); err != nil {
+			return err
+		}
+		data.Records = kept
+	}
+}
+
+// GetInFlightTransactions returns the accepted, but not yet confirmed, batches and the ones being re-verified, the oldest nonce first.
+func (proc *coinProcessor) GetInFlightTransactions(ctx context.Context) ([]*batch, error) {
+	const stmt = `
+select
+	*
+from
+	pending_coin_distributions
+where
+	eth_status IN ('ACCEPTED', 'REVERIFY') and
+	target = $1
+order by
+	eth_nonce ASC NULLS FIRST,
+	created_at ASC
+`
+
+	result, err := storage.Select[batchRecord](ctx, proc.DB, stmt, proc.Target.Name)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch accepted coin distributions")
+	}
+
+	batches := make([]*batch, 0)
+	byTX := make(map[string]*batch)
+	for _, record := range result {
+		if record.EthTX == nil {
+			log.Panic(fmt.Sprintf("accepted coin distribution of user %q has no TX", record.UserID))
+		}
+		data, found := byTX[*record.EthTX]
+		if !found {
+			data = &batch{
+				ID:          ulid.Make().String(),
+				TX:          *record.EthTX,
+				SentAt:      record.EthTXSentAt,
+				ReplacedTXs: record.EthReplacedTXs,
+			}
+			if record.EthMinedTX != nil && record.EthMinedBlockHash != nil && record.EthMinedBlockNumber != nil {
+				data.Mined = &minedTransaction{
+					Hash:        *record.EthMinedTX,
+					BlockHash:   *record.EthMinedBlockHash,
+					BlockNumber: uint64(*record.EthMinedBlockNumber),
+				}
+			}
+			if record.EthBatchID != nil {
+				data.ID = *record.EthBatchID
+			}
+			if record.EthGasEstimate != nil {
+				data.GasEstimate = uint64(*record.EthGasEstimate)
+			}
+			if data.SentAt.IsNil() {
+				// Accepted before we started tracking it, so we count from now on.
+				data.SentAt = time.Now()
+			}
+			if record.EthNonce != nil {
+				nonce := uint64(*record.EthNonce)
+				data.Nonce = &nonce
+			}
+			if price, ok := new(big.Int).SetString(record.EthTXGasPrice, 10); ok { //nolint:gomnd // Base.
+				data.GasPrice = price
+			}
+			// Legacy transactions have no priority fee.
+			if tip, ok := new(big.Int).SetString(record.EthTXGasTipCap, 10); ok && tip.Sign() > 0 { //nolint:gomnd // Base.
+				data.GasTipCap = tip
+			}
+			byTX[data.TX] = data
+			batches = append(batches, data)
+		}
+		data.Records = append(data.Records, record)
+	}
+
+	return batches, proc.MerkleTreesLoad(ctx, batches)
+}
+
+// SettleTransaction moves the records of the batch mined in the given TX from `pending_coin_distributions`
+// to the `settled_coin_distributions` ledger.
+func (proc *coinProcessor) SettleTransaction(ctx context.Context, data *batch, minedHash string, receipt *txReceipt) error {
+	const stmt = `
+with settled as (
+	delete from pending_coin_distributions
+	where
+		eth_status = 'ACCEPTED' and
+		eth_tx = $1 and
+		coalesce(eth_fencing_token, 0) <= $9
+	returning *
+)
+insert into settled_coin_distributions
+	(settled_at, created_at, internal_id, day, iceflakes, user_id, eth_address, eth_tx, eth_block_number, eth_block_hash, eth_gas_used, eth_effective_gas_price, batch_id, target)
+select
+	$2, created_at, internal_id, day, iceflakes, user_id, eth_address, $3, $4, $5, $6, $7::text::uint256, $8, target
+from
+	settled
+`
+
+	effectiveGasPrice := "0"
+	if receipt.EffectiveGasPrice != nil {
+		effectiveGasPrice = receipt.EffectiveGasPrice.String()
+	}
+	r, err := storage.Exec(ctx, proc.DB, stmt,
+		data.TX, time.Now().Time, minedHash, int64(receipt.BlockNumber), receipt.BlockHash, int64(receipt.GasUsed), effectiveGasPrice, data.ID,
+		proc.FencingToken())
+	if err = fenced(r, err); err != nil {
+		return errors.Wrapf(err, "failed to settle transaction %v", minedHash)
+	}
+
+	log.Info(fmt.Sprintf("transaction: %v: settled: %v", minedHash, r))
 
 	return nil
 }
 
-func (proc *coinProcessor) RejectTransaction(ctx context.Context, hash string) error {
+func (proc *coinProcessor) RejectTransaction(ctx context.Context, hash, reason string) error {
 	const stmt = `
 update pending_coin_distributions
 set
-	eth_status = 'REJECTED'
+	eth_status = 'REJECTED',
+	eth_rejected_at = $2,
+	eth_error = $3,
+	eth_fencing_token = $4
 where
 	eth_status = 'ACCEPTED' and
-	eth_tx = $1
+	eth_tx = $1 and
+	coalesce(eth_fencing_token, 0) <= $4
 `
 
-	r, err := storage.Exec(ctx, proc.DB, stmt, hash)
-	if err != nil {
+	r, err := storage.Exec(ctx, proc.DB, stmt, hash, time.Now().Time, reason, proc.FencingToken())
+	if err = fenced(r, err); err != nil {
 		return errors.Wrap(err, "failed to update transactions")
 	}
 
@@ -183,74 +608,178 @@ where
 	return nil
 }
 
-func (proc *coinProcessor) GetGasOptions(ctx context.Context) (price *big.Int, limit uint64, err error) {
+// GetGasOptions returns the gas options for the next airdrop TX:
+// a legacy TX if `coin_distributer_gas_price_override` is set or dynamic fees are disabled, a dynamic fee (EIP-1559) one otherwise.
+func (proc *coinProcessor) GetGasOptions(ctx context.Context) (*gasOptions, error) {
+	limit, err := proc.GetGasLimit(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	gasOverride, err := proc.GetGasPriceOverride(ctx)
 	if err != nil {
-		return nil, 0, err
+		return nil, err
+	}
+	if gasOverride != 0 {
+		return &gasOptions{Price: new(big.Int).SetUint64(gasOverride), Limit: limit}, nil
 	}
 
-	if gasOverride == 0 {
-		price, err = proc.GetGasPrice(ctx)
-		if err != nil {
-			return nil, 0, err
+	dynamicFees, err := proc.IsDynamicFeesEnabled(ctx)
+	if err != nil {
+		return nil, err
+	}
+	maxFeeCap, maxTipCap, err := proc.GetGasFeeCaps(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !dynamicFees {
+		price, pErr := proc.GetGasPrice(ctx)
+		if pErr != nil {
+			return nil, pErr
 		}
-	} else {
-		price = big.NewInt(0).SetUint64(gasOverride)
+		if maxFeeCap != 0 && price.Cmp(new(big.Int).SetUint64(maxFeeCap)) > 0 {
+			return nil, errors.Wrapf(errGasFeeCapExceeded, "gas price %v > %v", price, maxFeeCap)
+		}
+
+		return &gasOptions{Price: price, Limit: limit}, nil
 	}
 
-	limit, err = proc.GetGasLimit(ctx)
+	baseFee, suggestedTipCap, err := proc.GetGasFees(ctx)
 	if err != nil {
-		return nil, 0, err
+		return nil, err
+	}
+	feeCap, tipCap, err := dynamicGasFees(baseFee, suggestedTipCap, maxFeeCap, maxTipCap)
+	if err != nil {
+		return nil, err
 	}
 
-	return price, limit, nil
+	return &gasOptions{Price: feeCap, TipCap: tipCap, Limit: limit}, nil
 }
 
-func (proc *coinProcessor) Distribute(ctx context.Context, data *batch) (string, error) {
-	recipients, amounts := data.Prepare()
-	for recordNum := range data.Records {
-		log.Info(fmt.Sprintf("batch %v: distributing %v iceflakes to address %v for user %q",
-			data.ID,
-			data.Records[recordNum].Iceflakes,
-			data.Records[recordNum].EthAddress,
-			data.Records[recordNum].UserID,
-		))
+// NextNonce returns the nonce for the next airdrop TX, it's fetched from the node once and then tracked locally.
+func (proc *coinProcessor) NextNonce(ctx context.Context) (uint64, error) {
+	const stmt = `select coalesce(max(eth_nonce) + 1, 0) from pending_coin_distributions where eth_status IN ('ACCEPTED', 'REVERIFY') and target = $1`
+
+	if proc.nonce != nil {
+		return *proc.nonce, nil
+	}
+
+	nonce, err := proc.Client.PendingNonceAt(ctx)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get pending nonce")
 	}
 
-	txHash, err := proc.Client.Airdrop(ctx, big.NewInt(proc.Conf.Ethereum.ChainID), proc, recipients, amounts)
+	// The node may have dropped some of our in-flight transactions from its pool, we must not reuse their nonces.
+	tracked, err := storage.ExecOne[int64](ctx, proc.DB, stmt, proc.Target.Name)
 	if err != nil {
+		return 0, errors.Wrap(err, "failed to get the last tracked nonce")
+	}
+	if uint64(*tracked) > nonce {
+		nonce = uint64(*tracked)
+	}
+	proc.nonce = &nonce
+
+	return nonce, nil
+}
+
+func (proc *coinProcessor) Distribute(ctx context.Context, data *batch) (*airdropTransaction, error) {
+	nonce, err := proc.NextNonce(ctx)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get nonce for batch %v", data.ID)
+	}
+
+	if data.Merkle == nil {
+		for recordNum := range data.Records {
+			log.Info(fmt.Sprintf("batch %v: distributing %v iceflakes to address %v for user %q",
+				data.ID,
+				data.Records[recordNum].Iceflakes,
+				data.Records[recordNum].EthAddress,
+				data.Records[recordNum].UserID,
+			))
+		}
+	} else {
+		log.Info(fmt.Sprintf("batch %v: publishing merkle root %v of cycle %v for %v record(s)",
+			data.ID, data.Merkle.Root.Hex(), data.Merkle.Cycle, len(data.Records)))
+	}
+
+	tx, err := proc.SendTransaction(ctx, data, data.GasGetter(proc), nonce, func(tx *airdropTransaction) error {
+		return proc.BatchMarkSigned(ctx, data, tx)
+	})
+	if err != nil {
+		// We don't know if the node has seen the nonce or not, so we ask it again next time.
+		proc.nonce = nil
 		log.Error(errors.Wrapf(err, "batch %v: failed to run contract", data.ID))
 
-		return "", errors.Wrapf(err, "failed to run contract on batch %v", data.ID)
+		return nil, errors.Wrapf(err, "failed to run contract on batch %v", data.ID)
 	}
+	nonce++
+	proc.nonce = &nonce
+
+	log.Info(fmt.Sprintf("batch %v: transaction hash: %v, nonce: %v", data.ID, tx.Hash, tx.Nonce))
+
+	return tx, nil
+}
 
-	log.Info(fmt.Sprintf("batch %v: transaction hash: %v", data.ID, txHash))
+// SendTransaction airdrops the coins of the batch or, in the Merkle-claim mode, publishes the root of its tree.
+func (proc *coinProcessor) SendTransaction(
+	ctx context.Context, data *batch, gas gasGetter, nonce uint64, signed signedTxHandler,
+) (*airdropTransaction, error) {
+	// Every attempt gets the gas options right before it's signed, so it's the last chance to make sure we're still the leader.
+	gas = &leaderGasGetter{gasGetter: gas, proc: proc}
+	if data.Merkle != nil {
+		return proc.Client.PublishMerkleRoot(ctx, big.NewInt(proc.Target.ChainID), gas, nonce, data.Merkle.Cycle, data.Merkle.Root, signed) //nolint:wrapcheck,lll //.
+	}
+	recipients, amounts := data.Prepare()
 
-	return txHash, nil
+	return proc.Client.Airdrop(ctx, big.NewInt(proc.Target.ChainID), gas, nonce, recipients, amounts, signed) //nolint:wrapcheck //.
 }
 
 func (proc *coinProcessor) Do(ctx context.Context) (*batch, error) {
+	if err := proc.EnsureBalances(ctx); err != nil {
+		return nil, err
+	}
+
 	data, err := proc.BatchPrepareFetch(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	txHash, err := proc.Distribute(ctx, data)
+	tx, err := proc.Distribute(ctx, data)
 	if err != nil {
 		err = errors.Wrapf(err, "failed to distribute batch")
 		log.Error(err)
-		if err2 := proc.BatchMarkRejected(ctx, data); err2 != nil {
+		if errors.Is(err, errLeadershipLost) {
+			// The batch belongs to the new leader now.
+			return data, err
+		}
+		// The distribution might have been interrupted by the shutdown, the batch must not be left PENDING because of it.
+		reqCtx, cancel := context.WithTimeout(context.Background(), requestDeadline)
+		defer cancel()
+		if data.Signed != nil {
+			// The signed TX might have been broadcast and still be mined, so it's tracked, and replaced if it's not, as any other.
+			// Its nonce is taken, if it was never broadcast, the replacement fills the gap.
+			log.Warn(fmt.Sprintf("batch %v: keeping signed transaction %v with nonce %v in flight", data.ID, data.Signed.Hash, data.Signed.Nonce))
+			if err2 := proc.BatchMarkAccepted(reqCtx, data, data.Signed); err2 != nil {
+				log.Error(errors.Wrapf(err2, "failed to mark batch %v as accepted", data.ID))
+
+				return nil, err
+			}
+			nonce := data.Signed.Nonce + 1
+			proc.nonce = &nonce
+
+			return data, err
+		}
+		if err2 := proc.BatchMarkRejected(reqCtx, data, err); err2 != nil {
 			log.Error(errors.Wrapf(err2, "failed to mark batch %v as rejected", data.ID))
 		}
 
-		return data, err
+		return nil, err
 	}
 
-	data.TX = txHash
-	if err = proc.BatchMarkAccepted(ctx, data, txHash); err != nil {
+	if err = proc.BatchMarkAccepted(ctx, data, tx); err != nil {
 		log.Error(errors.Wrapf(err, "failed to mark batch %v as accepted", data.ID))
 
-		return data, err
+		return nil, err
 	}
 
 	return data, nil
@@ -317,17 +846,15 @@ func (proc *coinProcessor) maybeSendMessage(ctx context.Context, key string, f f
 func (proc *coinProcessor) Controller(ctx context.Context, notify chan<- *batch) {
 	const tickInternal = stdlibtime.Minute
 
-	log.Info("controller started")
-	defer log.Info("controller stopped")
+	log.Info(fmt.Sprintf("controller[%v] started", proc.Target.Name))
+	defer log.Info(fmt.Sprintf("controller[%v] stopped", proc.Target.Name))
 
-	if proc.HasPendingTransactions(ctx, ethApiStatusAccepted) {
-		log.Info("controller: waiting for all accepted transactions to finish")
-		err := proc.WaitForAllAcceptedTransactions(ctx, notify)
-		if err != nil {
-			log.Error(errors.Wrapf(err, "failed to WaitForAllAcceptedTransactions"))
-			proc.MustDisable(err.Error())
-		}
-	}
+	log.Error(errors.Wrapf(proc.InitTargetGlobals(ctx), "failed to InitTargetGlobals for %v", proc.Target.Name))
+	defer func() {
+		reqCtx, cancel := context.WithTimeout(context.Background(), requestDeadline)
+		defer cancel()
+		log.Error(errors.Wrapf(proc.ReleaseLeadership(reqCtx), "failed to ReleaseLeadership for %v", proc.Target.Name))
+	}()
 
 	ticker := stdlibtime.NewTicker(tickInternal)
 	defer ticker.Stop()
@@ -348,27 +875,33 @@ func (proc *coinProcessor) Controller(ctx context.Context, notify chan<- *batch)
 	for {
 		select {
 		case <-ctx.Done():
-			log.Info(fmt.Sprintf("controller: context: %v", ctx.Err()))
+			log.Info(fmt.Sprintf("controller[%v]: context: %v", proc.Target.Name, ctx.Err()))
 
 			return
 
 		case <-proc.CancelSignal:
-			log.Info("controller: exit signal")
+			log.Info(fmt.Sprintf("controller[%v]: exit signal", proc.Target.Name))
 
 			return
 
 		case <-signals:
+			if !proc.maybeLead(ctx, notify) {
+				prevAction = workerActionBlocked
+
+				continue
+			}
+
 			action := proc.GetAction(ctx)
 			if action == workerActionDisabled || action == workerActionBlocked {
-				log.Info(fmt.Sprintf("controller: disabled or blocked (%v)", action))
+				log.Info(fmt.Sprintf("controller[%v]: disabled or blocked (%v)", proc.Target.Name, action))
 				if prevAction == workerActionRun {
-					proc.maybeSendMessage(ctx, configKeyCoinDistributerMsgOffline, func(ctx context.Context) (err error) {
+					proc.maybeSendMessage(ctx, proc.targetKey(configKeyCoinDistributerMsgOffline), func(ctx context.Context) (err error) {
 						if proc.HasPendingTransactions(ctx, ethApiStatusNew) {
-							err = errors.Wrap(sendCoinDistributerHasUnfinishedWork(ctx), "failed to sendCoinDistributerHasUnfinishedWork")
+							err = errors.Wrap(sendCoinDistributerHasUnfinishedWork(ctx, proc.Target.Name), "failed to sendCoinDistributerHasUnfinishedWork")
 						}
 
 						return multierror.Append(err,
-							errors.Wrap(sendCoinDistributerIsNowOfflineSlackMessage(ctx),
+							errors.Wrap(sendCoinDistributerIsNowOfflineSlackMessage(ctx, proc.Target.Name),
 								"failed to sendCoinDistributerIsNowOfflineSlackMessage"),
 						)
 					})
@@ -379,102 +912,101 @@ func (proc *coinProcessor) Controller(ctx context.Context, notify chan<- *batch)
 			}
 
 			if action == workerActionRun {
-				log.Info("controller: unblocked")
-				proc.maybeSendMessage(ctx, configKeyCoinDistributerMsgOnline, sendCoinDistributerIsNowOnlineSlackMessage)
+				log.Info(fmt.Sprintf("controller[%v]: unblocked", proc.Target.Name))
+				proc.maybeSendMessage(ctx, proc.targetKey(configKeyCoinDistributerMsgOnline), func(ctx context.Context) error {
+					return sendCoinDistributerIsNowOnlineSlackMessage(ctx, proc.Target.Name)
+				})
 			} else if action == workerActionOnDemand {
-				log.Info("controller: on demand mode trigger")
+				log.Info(fmt.Sprintf("controller[%v]: on demand mode trigger", proc.Target.Name))
 				log.Error(errors.Wrapf(proc.DisableOnDemand(ctx), "failed to DisableOnDemand"))
 			}
 			prevAction = action
 
 			if !proc.HasPendingTransactions(ctx, ethApiStatusNew) {
-				log.Info("controller: no pending transactions")
+				log.Info(fmt.Sprintf("controller[%v]: no pending transactions", proc.Target.Name))
 
 				continue
 			}
 
-			log.Info(fmt.Sprintf("controller: running action %v", action))
-			log.Error(errors.Wrap(sendCoinDistributerStartedProcessingSlackMessage(ctx),
+			log.Info(fmt.Sprintf("controller[%v]: running action %v", proc.Target.Name, action))
+			log.Error(errors.Wrap(sendCoinDistributerStartedProcessingSlackMessage(ctx, proc.Target.Name),
 				"failed to send DistributerStartedProcessingSlackMessage"))
 			err := proc.RunDistribution(ctx, action == workerActionOnDemand, notify)
-			if err != nil {
-				log.Error(errors.Wrapf(err, "controller: action %v failed with error %v", action, err))
+			if errors.Is(err, errLeadershipLost) {
+				log.Warn(fmt.Sprintf("controller[%v]: leadership lost during action %v: %v", proc.Target.Name, action, err))
+				proc.fencingToken.Store(0)
+
+				continue
+			} else if err != nil {
+				log.Error(errors.Wrapf(err, "controller[%v]: action %v failed with error %v", proc.Target.Name, action, err))
 				proc.MustDisable(err.Error())
 
 				continue
 			}
 
 			if !proc.HasPendingTransactions(ctx, ethApiStatusNew) {
-				proc.maybeSendMessage(
-					ctx,
-					configKeyCoinDistributerMsgFinished,
-					sendAllCurrentCoinDistributionsWereCommittedInEthereumSlackMessage)
+				proc.maybeSendMessage(ctx, proc.targetKey(configKeyCoinDistributerMsgFinished), func(ctx context.Context) error {
+					return sendAllCurrentCoinDistributionsWereCommittedInEthereumSlackMessage(ctx, proc.Target.Name)
+				})
 			}
 
-			log.Info(fmt.Sprintf("controller: action %v finished", action))
+			log.Info(fmt.Sprintf("controller[%v]: action %v finished", proc.Target.Name, action))
 		}
 	}
 }
 
-func (proc *coinProcessor) GetNextPendingTransaction(ctx context.Context) (string, error) {
-	const stmt = `
-select
-	eth_tx
-from
-	pending_coin_distributions
-where
-	eth_status = 'ACCEPTED'
-order by
-	created_at ASC
-limit 1
-`
-
-	val, err := storage.Get[string](ctx, proc.DB, stmt)
+// maybeLead acquires (or renews) the leadership of the target, only the leader sends (and tracks) the TXs, the rest are on standby.
+// The new leader finishes the TXs in flight of its predecessor first.
+func (proc *coinProcessor) maybeLead(ctx context.Context, notify chan<- *batch) bool {
+	wasLeader := proc.IsLeader()
+	leader, err := proc.AcquireLeadership(ctx)
 	if err != nil {
-		if errors.Is(err, storage.ErrNotFound) {
-			err = nil
-		}
+		log.Error(errors.Wrapf(err, "controller[%v]: failed to AcquireLeadership", proc.Target.Name))
+
+		return false
+	} else if !leader {
+		log.Info(fmt.Sprintf("controller[%v]: standby, another instance is the leader", proc.Target.Name))
 
-		return "", errors.Wrap(err, "failed to get next pending transaction")
+		return false
+	} else if wasLeader {
+		return true
 	}
+	// The previous leader might have used more nonces since we've cached ours.
+	proc.nonce = nil
 
-	return *val, nil
-}
+	if proc.HasPendingTransactions(ctx, ethApiStatusAccepted) || proc.HasPendingTransactions(ctx, ethApiStatusReverify) {
+		log.Info(fmt.Sprintf("controller[%v]: waiting for all accepted transactions to finish", proc.Target.Name))
+		if err = proc.WaitForAllAcceptedTransactions(ctx, notify); errors.Is(err, errLeadershipLost) {
+			log.Warn(fmt.Sprintf("controller[%v]: leadership lost while waiting for accepted transactions: %v", proc.Target.Name, err))
+			proc.fencingToken.Store(0)
 
-func (proc *coinProcessor) WaitForAllAcceptedTransactions(ctx context.Context, notify chan<- *batch) error {
-	for ctx.Err() == nil {
-		txHash, err := proc.GetNextPendingTransaction(ctx)
-		if err != nil {
-			return err
+			return false
+		} else if err != nil {
+			log.Error(errors.Wrapf(err, "failed to WaitForAllAcceptedTransactions"))
+			proc.MustDisable(err.Error())
 		}
+	}
 
-		if txHash == "" {
-			return nil
-		}
+	return proc.IsLeader()
+}
 
-		status, err := proc.WaitForTransaction(ctx, txHash)
-		if err != nil {
+func (proc *coinProcessor) WaitForAllAcceptedTransactions(ctx context.Context, notify chan<- *batch) error {
+	inFlight, err := proc.GetInFlightTransactions(ctx)
+	if err != nil {
+		return err
+	}
+
+	for len(inFlight) != 0 && ctx.Err() == nil {
+		if err = proc.RenewLeadership(ctx); err != nil {
 			return err
 		}
-
-		switch status {
-		case ethTxStatusSuccessful:
-			err = proc.DeleteTransactions(ctx, txHash)
-
-		case ethTxStatusFailed:
-			proc.MustDisable(fmt.Sprintf("accepted transaction %v failed", txHash))
-			err = proc.RejectTransaction(ctx, txHash)
+		if inFlight, err = proc.TrackInFlightTransactions(ctx, inFlight, notify); err != nil {
+			return err
 		}
 
-		if err != nil {
-			return errors.Wrapf(err, "failed to update transaction status: %v", txHash)
+		if len(inFlight) != 0 {
+			sleepWithContext(ctx, transactionStatusPollInterval)
 		}
-
-		sendNotify(notify, &batch{TX: txHash, Status: status, Records: []*batchRecord{
-			{
-				EthTX: &txHash,
-			},
-		}})
 	}
 
 	return ctx.Err()
@@ -490,84 +1022,228 @@ func sleepWithContext(ctx context.Context, d stdlibtime.Duration) {
 	}
 }
 
-func (proc *coinProcessor) WaitForTransaction(ctx context.Context, hash string) (ethTxStatus, error) {
-	start := time.Now()
+// TrackInFlightTransactions checks all the in-flight batches once, settles the ones mined `coin_distributer_confirmation_blocks` deep,
+// re-verifies the ones whose block was reorged out and speeds up the stuck ones. It returns the batches that are still in flight.
+func (proc *coinProcessor) TrackInFlightTransactions(ctx context.Context, inFlight []*batch, notify chan<- *batch) ([]*batch, error) { //nolint:funlen,gocognit //.
+	hashes := make([]*string, 0, len(inFlight))
+	for _, data := range inFlight {
+		for _, hash := range data.Hashes() {
+			hashes = append(hashes, &hash)
+		}
+	}
 
-	for ctx.Err() == nil {
-		status, err := proc.Client.TransactionStatus(ctx, hash)
-		if err != nil {
-			return "", errors.Wrapf(err, "failed to get transaction status: %v", hash)
+	mined, err := proc.Client.TransactionsReceipts(ctx, hashes)
+	if err != nil {
+		return inFlight, errors.Wrap(err, "failed to get transactions receipts")
+	}
+
+	replacementTimeout, err := proc.GetTransactionReplacementTimeout(ctx)
+	if err != nil {
+		return inFlight, err
+	}
+	depth, err := proc.GetConfirmationBlocks(ctx)
+	if err != nil {
+		return inFlight, err
+	}
+	var latestBlock uint64
+	if len(mined) != 0 {
+		if latestBlock, err = proc.Client.LatestBlockNumber(ctx); err != nil {
+			return inFlight, errors.Wrap(err, "failed to get latest block number")
+		}
+	}
+
+	pending := make([]*batch, 0, len(inFlight))
+	for _, data := range inFlight {
+		hash, receipt, state := data.Confirmation(mined, latestBlock, depth)
+		if data.Mined == nil && (state == confirmationStateConfirming || state == confirmationStateConfirmed) {
+			if err = proc.BatchMarkMined(ctx, data, hash, receipt); err != nil {
+				return inFlight, err
+			}
 		}
 
-		if status == ethTxStatusPending {
-			sleepDuration := stdlibtime.Second * 3
+		status := ethTxStatusPending
+		switch state {
+		case confirmationStatePending:
+			proc.maybeReplaceTransaction(ctx, data, replacementTimeout)
+			pending = append(pending, data)
 
-			if d := stdlibtime.Since(*start.Time); d > stdlibtime.Hour {
-				sleepDuration = stdlibtime.Hour
-				log.Warn(fmt.Sprintf("transaction %v is in PENDING state since %v (%v)", hash, start, d))
-				if d > stdlibtime.Hour*24 {
-					sendCoinDistributerTransactionStuck(ctx, hash, start)
-				}
+			continue
+
+		case confirmationStateReorged:
+			if err = proc.BatchMarkReorged(ctx, data); err != nil {
+				return inFlight, err
 			}
+			pending = append(pending, data)
 
-			sleepWithContext(ctx, sleepDuration)
+			continue
+
+		case confirmationStateConfirming:
+			pending = append(pending, data)
 
 			continue
+
+		case confirmationStateConfirmed:
+			status = receipt.Status
+			if status == ethTxStatusSuccessful {
+				if data.Merkle != nil {
+					err = proc.MerkleTreePublished(ctx, data, hash)
+				}
+				if err == nil {
+					err = proc.SettleTransaction(ctx, data, hash, receipt)
+				}
+			} else {
+				reason := fmt.Sprintf("transaction %v failed in block %v (#%v)", hash, receipt.BlockHash, receipt.BlockNumber)
+				proc.MustDisable(reason)
+				err = proc.RejectTransaction(ctx, data.TX, reason)
+			}
 		}
 
-		log.Info(fmt.Sprintf("transaction %v: status: %v, duration: %v", hash, status, stdlibtime.Since(*start.Time)))
+		if err != nil {
+			return inFlight, errors.Wrapf(err, "failed to update transaction status: %v", data.TX)
+		}
 
-		return status, nil
+		if hash != data.TX {
+			log.Info(fmt.Sprintf("batch %v: transaction %v was mined instead of its replacement %v", data.ID, hash, data.TX))
+		}
+		log.Info(fmt.Sprintf("transaction %v: status: %v, block: %v (#%v), duration: %v",
+			hash, status, receipt.BlockHash, receipt.BlockNumber, stdlibtime.Since(*data.SentAt.Time)))
+		data.TX, data.Status = hash, status
+		sendNotify(notify, data)
 	}
 
-	return "", ctx.Err()
+	return pending, nil
 }
 
-func (proc *coinProcessor) RunDistribution(ctx context.Context, ondemand bool, notify chan<- *batch) error {
-	for it := 1; ctx.Err() == nil; it++ {
-		if !proc.IsEnabled(ctx) {
-			log.Info(fmt.Sprintf("distribution: iteration %v: disabled", it))
+func (proc *coinProcessor) maybeReplaceTransaction(ctx context.Context, data *batch, timeout stdlibtime.Duration) {
+	pendingFor := stdlibtime.Since(*data.SentAt.Time)
+	if pendingFor < timeout {
+		return
+	}
+
+	if data.Nonce == nil || len(data.ReplacedTXs) >= maxTransactionReplacements {
+		if !data.stuckSent {
+			log.Error(errors.Wrap(sendCoinDistributerTransactionStuck(ctx, proc.Target.Name, data.TX, len(data.ReplacedTXs), data.SentAt),
+				"failed to sendCoinDistributerTransactionStuck"))
+			data.stuckSent = true
+		}
 
-			return nil
-		} else if proc.isBlocked() && !ondemand {
-			log.Info(fmt.Sprintf("distribution: iteration %v: blocked", it))
+		return
+	}
 
-			return nil
+	log.Warn(fmt.Sprintf("batch %v: transaction %v is in PENDING state for %v, replacing it", data.ID, data.TX, pendingFor))
+	if err := proc.ReplaceTransaction(ctx, data); err != nil {
+		log.Error(errors.Wrapf(err, "batch %v: failed to replace transaction %v", data.ID, data.TX))
+		if !containsEthError(err, txpool.ErrReplaceUnderpriced) {
+			// Give the current transaction another chance before trying again, an underpriced one is outbid right away.
+			data.SentAt = time.Now()
 		}
+	}
+}
 
-		log.Info(fmt.Sprintf("distribution: iteration %v", it))
-		b, err := proc.Do(context.WithoutCancel(ctx))
-		if err != nil {
-			if errors.Is(err, errNotEnoughData) {
-				err = nil
-			}
+// ReplaceTransaction re-sends the batch with the same nonce and a higher gas price, so the stuck transaction gets mined faster.
+func (proc *coinProcessor) ReplaceTransaction(ctx context.Context, data *batch) error {
+	maxFeeCap, _, err := proc.GetGasFeeCaps(ctx)
+	if err != nil {
+		return err
+	}
+	gas := &replacementGasGetter{gasGetter: data.GasGetter(proc), MinPrice: data.ReplacementGasPrice(), MinTipCap: data.ReplacementGasTipCap()}
+	if maxFeeCap != 0 {
+		gas.MaxPrice = new(big.Int).SetUint64(maxFeeCap)
+	}
+	// Checked upfront, because the airdrop would keep retrying until the fees go down, blocking all the other in-flight transactions.
+	if _, err = gas.GetGasOptions(ctx); err != nil {
+		return errors.Wrapf(err, "failed to get gas options for batch %v", data.ID)
+	}
 
-			return err
+	tx, err := proc.SendTransaction(ctx, data, gas, *data.Nonce, func(tx *airdropTransaction) error {
+		return proc.BatchMarkReplacementSigned(ctx, data, tx)
+	})
+	if err != nil {
+		if containsEthError(err, txpool.ErrReplaceUnderpriced) && gas.Last != nil {
+			// A pricier TX with the nonce is in the pool, I.E. a replacement whose outcome was lost, so the next one bumps from what we just offered.
+			data.GasPrice, data.GasTipCap = gas.Last.Price, gas.Last.TipCap
 		}
 
-		status, err := proc.WaitForTransaction(ctx, b.TX)
-		if err != nil {
-			return err
+		return errors.Wrapf(err, "failed to run contract on batch %v", data.ID)
+	}
+
+	log.Info(fmt.Sprintf("batch %v: transaction %v replaced with %v, nonce: %v, gas price: %v -> %v, tip: %v -> %v",
+		data.ID, data.TX, tx.Hash, tx.Nonce, data.GasPrice, tx.GasPrice, data.GasTipCap, tx.GasTipCap))
+
+	return proc.BatchMarkReplaced(ctx, data, tx)
+}
+
+func (proc *coinProcessor) canSendTransactions(ctx context.Context, ondemand bool) bool {
+	if !proc.IsEnabled(ctx) {
+		log.Info("distribution: disabled")
+
+		return false
+	} else if proc.isBlocked() && !ondemand {
+		log.Info("distribution: blocked")
+
+		return false
+	}
+
+	return true
+}
+
+func (proc *coinProcessor) fillInFlightTransactions(ctx context.Context, inFlight []*batch) ([]*batch, error) {
+	maxInFlight, err := proc.GetMaxInFlightTransactions(ctx)
+	if err != nil {
+		return inFlight, err
+	}
+
+	for uint64(len(inFlight)) < maxInFlight {
+		log.Info(fmt.Sprintf("distribution: sending transaction %v of %v in flight", len(inFlight)+1, maxInFlight))
+		data, doErr := proc.Do(ctx)
+		if data != nil && data.SentAt != nil {
+			// Kept in flight, even if sending it failed.
+			inFlight = append(inFlight, data)
 		}
+		if doErr != nil {
+			return inFlight, doErr
+		}
+	}
 
-		b.Status = status
-		sendNotify(notify, b)
+	return inFlight, nil
+}
 
-		switch status {
-		case ethTxStatusSuccessful:
-			err = proc.DeleteTransactions(ctx, b.TX)
+// RunDistribution keeps up to `coin_distributer_max_in_flight_transactions` airdrop transactions in flight
+// until there is nothing left to distribute or the distributer gets disabled or blocked.
+func (proc *coinProcessor) RunDistribution(ctx context.Context, ondemand bool, notify chan<- *batch) error {
+	inFlight, err := proc.GetInFlightTransactions(ctx)
+	if err != nil {
+		return err
+	}
 
-		case ethTxStatusFailed:
-			proc.MustDisable(fmt.Sprintf("transaction %v failed", b.TX))
+	var sendErr error
+	sending := true
+	for ctx.Err() == nil {
+		if err = proc.RenewLeadership(ctx); err != nil {
+			return err
+		}
+		if sending = sending && proc.canSendTransactions(ctx, ondemand); sending {
+			if inFlight, sendErr = proc.fillInFlightTransactions(ctx, inFlight); sendErr != nil {
+				if errors.Is(sendErr, errNotEnoughData) || errors.Is(sendErr, errBalanceTooLow) {
+					// Nothing left to send, or paused until the balances are topped up.
+					sendErr = nil
+				} else if errors.Is(sendErr, errLeadershipLost) {
+					return sendErr
+				}
+				sending = false
+			}
+		}
 
-			return errors.Wrapf(proc.RejectTransaction(ctx, b.TX), "failed to reject transaction %v", b.TX)
+		if len(inFlight) == 0 {
+			return sendErr
+		}
 
-		default:
-			log.Panic(fmt.Sprintf("unexpected transaction status: %v (%v)", status, b.TX))
+		if inFlight, err = proc.TrackInFlightTransactions(ctx, inFlight, notify); err != nil {
+			return multierror.Append(sendErr, err).ErrorOrNil() //nolint:wrapcheck // .
 		}
 
-		if err != nil {
-			return errors.Wrapf(err, "failed to update transaction status: %v", b.TX)
+		if len(inFlight) != 0 {
+			sleepWithContext(ctx, transactionStatusPollInterval)
 		}
 	}
 
@@ -592,7 +1268,7 @@ func isInTimeWindow(now *time.Time, startHour, endHour int) bool {
 }
 
 func (proc *coinProcessor) isBlocked() bool {
-	return !isInTimeWindow(time.Now(), proc.Conf.StartHours, proc.Conf.EndHours)
+	return !isInTimeWindow(time.Now(), proc.Target.StartHours, proc.Target.EndHours)
 }
 
 func (proc *coinProcessor) Close() error {
@@ -5,6 +5,7 @@ package coindistribution
 import (
 	"context"
 	"errors"
+	"math/big"
 	"math/rand"
 	"os"
 	"testing"
@@ -68,11 +69,15 @@ func helperTruncatePendingTransactions(ctx context.Context, t *testing.T, db *st
 func TestBatchPrepareFetch(t *testing.T) { //nolint:paralleltest //.
 	maybeSkipTest(t)
 	ctx := context.TODO()
-	proc := newCoinProcessor(nil, storage.MustConnect(ctx, ddl, applicationYamlKey), &config{})
+	proc := newCoinProcessor(new(mockedDummyEthClient), storage.MustConnect(ctx, ddl, applicationYamlKey), &config{})
 	require.NotNil(t, proc)
 	defer proc.Close()
 
 	helperTruncatePendingTransactions(ctx, t, proc.DB)
+	leader, err := proc.AcquireLeadership(ctx)
+	require.NoError(t, err)
+	require.True(t, leader)
+	defer func() { require.NoError(t, proc.ReleaseLeadership(ctx)) }()
 
 	t.Run("NotEnoughData", func(t *testing.T) { //nolint:paralleltest //.
 		_, err := proc.BatchPrepareFetch(ctx)
@@ -129,7 +134,7 @@ func TestProcessorDistributeAccepted(t *testing.T) { //nolint:paralleltest //.
 	defer proc.Close()
 
 	helperTruncatePendingTransactions(ctx, t, proc.DB)
-	helperAddNewPendingTransaction(ctx, t, proc, batchSize*3)
+	helperAddNewPendingTransaction(ctx, t, proc, maxBatchSize*3)
 
 	ch := make(chan *batch, 3)
 	proc.Start(ctx, ch)
@@ -218,7 +223,7 @@ func TestProcessorTriggerOnDemand(t *testing.T) { //nolint:paralleltest //.
 	defer proc.Close()
 
 	helperTruncatePendingTransactions(ctx, t, proc.DB)
-	helperAddNewPendingTransaction(ctx, t, proc, batchSize*4)
+	helperAddNewPendingTransaction(ctx, t, proc, maxBatchSize*4)
 
 	ch := make(chan *batch, 4)
 	proc.Start(ctx, ch)
@@ -247,3 +252,136 @@ func TestProcessorTriggerOnDemand(t *testing.T) { //nolint:paralleltest //.
 
 	require.False(t, proc.IsOnDemandMode(ctx))
 }
+
+func TestBatchReplacementGasPrice(t *testing.T) {
+	t.Parallel()
+
+	nonce := uint64(7)
+	data := &batch{TX: "0x3", ReplacedTXs: []string{"0x1", "0x2"}, Nonce: &nonce, GasPrice: big.NewInt(1_000)}
+	require.Equal(t, []string{"0x3", "0x1", "0x2"}, data.Hashes())
+	require.EqualValues(t, 1_201, data.ReplacementGasPrice().Int64())
+	require.EqualValues(t, 1_201, data.ReplacementGasTipCap().Int64())
+
+	gas := &replacementGasGetter{gasGetter: &mockedGasGetter{val: 9}, MinPrice: data.ReplacementGasPrice(), MinTipCap: data.ReplacementGasTipCap()}
+	options, err := gas.GetGasOptions(context.TODO())
+	require.NoError(t, err)
+	require.EqualValues(t, 1_201, options.Price.Int64())
+	require.Nil(t, options.TipCap)
+	require.EqualValues(t, 10, options.Limit)
+
+	gas = &replacementGasGetter{gasGetter: &mockedGasGetter{val: 2_000}, MinPrice: data.ReplacementGasPrice(), MaxPrice: big.NewInt(2_001)}
+	options, err = gas.GetGasOptions(context.TODO())
+	require.NoError(t, err)
+	require.EqualValues(t, 2_001, options.Price.Int64())
+	require.Equal(t, options, gas.Last)
+
+	gas.MaxPrice = big.NewInt(1_500)
+	_, err = gas.GetGasOptions(context.TODO())
+	require.ErrorIs(t, err, errGasFeeCapExceeded)
+	require.Equal(t, options, gas.Last, "the options over the cap are never offered")
+
+	data.SetAccepted(&airdropTransaction{Hash: "0x4", Nonce: nonce, GasPrice: options.Price, GasTipCap: big.NewInt(100)}, time.Now())
+	require.Equal(t, "0x4", data.TX)
+	require.Equal(t, options.Price, data.GasPrice)
+	require.EqualValues(t, 121, data.ReplacementGasTipCap().Int64())
+}
+
+func TestDynamicGasFees(t *testing.T) {
+	t.Parallel()
+
+	feeCap, tipCap, err := dynamicGasFees(big.NewInt(100), big.NewInt(10), 0, 0)
+	require.NoError(t, err)
+	require.EqualValues(t, 210, feeCap.Int64())
+	require.EqualValues(t, 10, tipCap.Int64())
+
+	feeCap, tipCap, err = dynamicGasFees(big.NewInt(100), big.NewInt(10), 150, 5)
+	require.NoError(t, err)
+	require.EqualValues(t, 150, feeCap.Int64())
+	require.EqualValues(t, 5, tipCap.Int64())
+
+	_, _, err = dynamicGasFees(big.NewInt(100), big.NewInt(10), 104, 0)
+	require.ErrorIs(t, err, errGasFeeCapExceeded)
+}
+
+func TestFitBatchSize(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, 100, fitBatchSize(100, 0, 1_000))
+	require.Equal(t, 50, fitBatchSize(100, 2_000, 1_000))
+	require.Equal(t, 199, fitBatchSize(100, 1_000, 1_999))
+	require.Equal(t, maxBatchSize, fitBatchSize(maxBatchSize, 1_000, 10_000))
+	require.Zero(t, fitBatchSize(1, 2_000, 1_000))
+
+	data := &batch{GasEstimate: 1_000}
+	require.EqualValues(t, 1_100, data.GasLimit())
+	options, err := data.GasGetter(&mockedGasGetter{}).GetGasOptions(context.TODO())
+	require.NoError(t, err)
+	require.EqualValues(t, 1_100, options.Limit)
+	options, err = new(batch).GasGetter(&mockedGasGetter{}).GetGasOptions(context.TODO())
+	require.NoError(t, err)
+	require.EqualValues(t, 1, options.Limit)
+}
+
+func TestBatchSplitUsers(t *testing.T) {
+	t.Parallel()
+
+	records := []*batchRecord{{UserID: "a", Iceflakes: "1"}, {UserID: "b", Iceflakes: "2"}, {UserID: "a", Iceflakes: "3"}, {UserID: "c", Iceflakes: "4"}}
+	data := &batch{Records: records}
+	require.Equal(t, 3, data.UserCount())
+
+	// The second record of `a` is past the cut, but it stays with the first one.
+	kept, released := data.SplitUsers(2)
+	require.Equal(t, []*batchRecord{records[0], records[1], records[2]}, kept)
+	require.Equal(t, []*batchRecord{records[3]}, released)
+
+	kept, released = data.SplitUsers(1)
+	require.Equal(t, []*batchRecord{records[0], records[2]}, kept)
+	require.Equal(t, []*batchRecord{records[1], records[3]}, released)
+	require.Equal(t, 1, (&batch{Records: kept}).UserCount())
+	require.Equal(t, []string{"b", "c"}, (&batch{Records: released}).Users())
+
+	kept, released = data.SplitUsers(3)
+	require.Equal(t, records, kept)
+	require.Empty(t, released)
+}
+
+func TestBatchConfirmation(t *testing.T) {
+	t.Parallel()
+
+	data := &batch{TX: "0x3", ReplacedTXs: []string{"0x1", "0x2"}}
+	hash, receipt, state := data.Confirmation(map[string]*txReceipt{}, 100, 12)
+	require.Equal(t, "0x3", hash)
+	require.Nil(t, receipt)
+	require.Equal(t, confirmationStatePending, state)
+
+	receipts := map[string]*txReceipt{"0x2": {Status: ethTxStatusSuccessful, BlockHash: "0xa", BlockNumber: 90}}
+	hash, receipt, state = data.Confirmation(receipts, 100, 12)
+	require.Equal(t, "0x2", hash)
+	require.Equal(t, receipts["0x2"], receipt)
+	require.Equal(t, confirmationStateConfirming, state)
+
+	_, _, state = data.Confirmation(receipts, 101, 12)
+	require.Equal(t, confirmationStateConfirmed, state)
+	_, _, state = data.Confirmation(receipts, 90, 0)
+	require.Equal(t, confirmationStateConfirmed, state)
+
+	data.Mined = &minedTransaction{Hash: "0x2", BlockHash: "0xa", BlockNumber: 90}
+	_, _, state = data.Confirmation(receipts, 101, 12)
+	require.Equal(t, confirmationStateConfirmed, state)
+
+	// Mined again in another block.
+	receipts["0x2"] = &txReceipt{Status: ethTxStatusSuccessful, BlockHash: "0xb", BlockNumber: 91}
+	hash, _, state = data.Confirmation(receipts, 101, 12)
+	require.Equal(t, "0x2", hash)
+	require.Equal(t, confirmationStateReorged, state)
+
+	// Not mined anymore, even if its replacement is.
+	receipts = map[string]*txReceipt{"0x3": {Status: ethTxStatusSuccessful, BlockHash: "0xc", BlockNumber: 95}}
+	_, _, state = data.Confirmation(receipts, 101, 12)
+	require.Equal(t, confirmationStateReorged, state)
+
+	data.Mined = nil
+	hash, _, state = data.Confirmation(receipts, 101, 12)
+	require.Equal(t, "0x3", hash)
+	require.Equal(t, confirmationStateConfirming, state)
+}
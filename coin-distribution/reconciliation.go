@@ -0,0 +1,240 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package coindistribution
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	stdlibtime "time"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/wintr/connectors/storage/v2"
+	"github.com/ice-blockchain/wintr/log"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+func (cd *coinDistributer) startReconciliationMonitor(ctx context.Context) {
+	ticker := stdlibtime.NewTicker(reconciliationInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if !cd.Processor.IsLeader() {
+				// Only the leader of the default target reconciles, the standby instances would ingest the same logs.
+				continue
+			}
+			reqCtx, cancel := context.WithTimeout(ctx, reconciliationInterval)
+			log.Error(errors.Wrap(cd.IngestTransferLogs(reqCtx), "failed to IngestTransferLogs"))
+			cd.Processor.maybeSendMessage(reqCtx, configKeyCoinDistributerMsgReconciliation, func(ctx context.Context) error {
+				summary, err := cd.Reconcile(ctx)
+				if err != nil {
+					return errors.Wrap(err, "failed to Reconcile")
+				}
+
+				return errors.Wrap(sendCoinDistributionsReconciliationSlackMessage(ctx, summary),
+					"failed to sendCoinDistributionsReconciliationSlackMessage")
+			})
+			cancel()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// IngestTransferLogs copies the airdrop transfers from the contract logs into `coin_distribution_transfers`,
+// from `coin_distributer_reconciliation_next_block` (but not before `coin_distributer_reconciliation_start_block`)
+// up to the latest block `coin_distributer_confirmation_blocks` deep.
+func (cd *coinDistributer) IngestTransferLogs(ctx context.Context) error {
+	var nextBlock, startBlock uint64
+	if err := databaseGetValue(ctx, cd.DB, configKeyCoinDistributerReconciliationNextBlock, &nextBlock); err != nil {
+		return errors.Wrapf(err, "failed to get %v", configKeyCoinDistributerReconciliationNextBlock)
+	}
+	if err := databaseGetValue(ctx, cd.DB, configKeyCoinDistributerReconciliationStartBlock, &startBlock); err != nil {
+		return errors.Wrapf(err, "failed to get %v", configKeyCoinDistributerReconciliationStartBlock)
+	}
+	nextBlock = max(nextBlock, startBlock)
+	confirmations, err := cd.Processor.GetConfirmationBlocks(ctx)
+	if err != nil {
+		return err
+	}
+	latestBlock, err := cd.Client.LatestBlockNumber(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to get latest block number")
+	}
+	if latestBlock < confirmations {
+		return nil
+	}
+	lastBlock := latestBlock - confirmations
+
+	for fromBlock := nextBlock; fromBlock <= lastBlock && ctx.Err() == nil; fromBlock += reconciliationBlocksPerQuery {
+		toBlock := min(fromBlock+reconciliationBlocksPerQuery-1, lastBlock)
+		logs, lErr := cd.Client.TransferLogs(ctx, fromBlock, toBlock)
+		if lErr != nil {
+			return errors.Wrapf(lErr, "failed to get transfer logs for blocks [%v, %v]", fromBlock, toBlock)
+		}
+		if err = cd.insertTransferLogs(ctx, logs, toBlock+1); err != nil {
+			return errors.Wrapf(err, "failed to insert transfer logs for blocks [%v, %v]", fromBlock, toBlock)
+		}
+		if len(logs) != 0 {
+			log.Info(fmt.Sprintf("reconciliation: %v transfer(s) in blocks [%v, %v]", len(logs), fromBlock, toBlock))
+		}
+	}
+
+	return errors.Wrap(ctx.Err(), "ingestion interrupted")
+}
+
+func (cd *coinDistributer) insertTransferLogs(ctx context.Context, logs []*transferLog, nextBlock uint64) error {
+	const stmt = `
+INSERT INTO coin_distribution_transfers(block_number, log_index, tx_hash, eth_address, iceflakes)
+	SELECT block_number, log_index, tx_hash, eth_address, iceflakes::uint256
+	FROM unnest($1::bigint[], $2::bigint[], $3::text[], $4::text[], $5::text[]) AS t(block_number, log_index, tx_hash, eth_address, iceflakes)
+ON CONFLICT (tx_hash, log_index) DO NOTHING
+`
+	blocks, indexes := make([]int64, 0, len(logs)), make([]int64, 0, len(logs))
+	hashes, addresses, values := make([]string, 0, len(logs)), make([]string, 0, len(logs)), make([]string, 0, len(logs))
+	for _, transfer := range logs {
+		blocks = append(blocks, int64(transfer.BlockNumber))
+		indexes = append(indexes, int64(transfer.LogIndex))
+		hashes = append(hashes, transfer.TxHash)
+		addresses = append(addresses, transfer.EthAddress)
+		values = append(values, transfer.Value.String())
+	}
+
+	return errors.Wrap(storage.DoInTransaction(ctx, cd.DB, func(conn storage.QueryExecer) error {
+		if len(logs) != 0 {
+			if _, err := storage.Exec(ctx, conn, stmt, blocks, indexes, hashes, addresses, values); err != nil {
+				return errors.Wrap(err, "failed to insert coin_distribution_transfers")
+			}
+		}
+
+		return databaseSetValue(ctx, conn, configKeyCoinDistributerReconciliationNextBlock, nextBlock)
+	}), "transaction failed")
+}
+
+// Reconcile compares, per eth address, the ICE approved in `reviewed_coin_distributions` (and not pending anymore)
+// since `coin_distributer_reconciliation_start_date` with the ICE transferred on-chain since `coin_distributer_reconciliation_start_block`
+// and stores the discrepancies in `coin_distribution_reconciliations`, unless the last one stored for the address is the same.
+// Only the default target is reconciled, the distributions settled in any other target or published as a Merkle root,
+// for the users to claim them, are left out, and so are the ones an operator gave up on (`failed_coin_distributions`).
+//
+//nolint:funlen // .
+func (cd *coinDistributer) Reconcile(ctx context.Context) (*reconciliationSummary, error) {
+	const (
+		approvedSQL = `
+	SELECT lower(r.eth_address) AS eth_address,
+		   count(1) AS approved_count,
+		   sum(r.iceflakes) AS approved_iceflakes
+	FROM reviewed_coin_distributions r
+	WHERE r.decision IN ('approve', 'approve-and-process-immediately')
+	  AND r.reviewed_at >= $1
+	  AND NOT EXISTS (SELECT 1 FROM pending_coin_distributions p WHERE p.day = r.day AND p.user_id = r.user_id)
+	  AND NOT EXISTS (SELECT 1 FROM settled_coin_distributions s WHERE s.day = r.day AND s.user_id = r.user_id AND s.target != $2)
+	  AND NOT EXISTS (SELECT 1 FROM settled_coin_distributions s JOIN coin_distribution_merkle_trees m ON m.batch_id = s.batch_id WHERE s.day = r.day AND s.user_id = r.user_id)
+	  AND NOT EXISTS (SELECT 1 FROM failed_coin_distributions f WHERE f.day = r.day AND f.user_id = r.user_id)
+	GROUP BY 1`
+		selectStmt = `
+WITH approved AS (` + approvedSQL + `
+), transferred AS (
+	SELECT eth_address,
+		   count(1) AS transfers_count,
+		   sum(iceflakes) AS transferred_iceflakes
+	FROM coin_distribution_transfers
+	WHERE block_number >= $3
+	GROUP BY 1
+)
+SELECT coalesce(a.eth_address, t.eth_address) AS eth_address,
+	   '' AS status,
+	   coalesce(a.approved_iceflakes, 0)::text AS approved_iceflakes,
+	   coalesce(t.transferred_iceflakes, 0)::text AS transferred_iceflakes,
+	   coalesce(a.approved_count, 0) AS approved_count,
+	   coalesce(t.transfers_count, 0) AS transfers_count
+FROM approved a
+	FULL OUTER JOIN transferred t
+		ON a.eth_address = t.eth_address
+WHERE coalesce(a.approved_iceflakes, 0) != coalesce(t.transferred_iceflakes, 0)`
+		totalsStmt = `
+SELECT (SELECT count(1) FROM (` + approvedSQL + `) a) AS addresses,
+	   (SELECT count(1) FROM coin_distribution_transfers WHERE block_number >= $3) AS transfers`
+		insertStmt = `
+INSERT INTO coin_distribution_reconciliations(reconciled_at, approved_count, transfers_count, approved_iceflakes, transferred_iceflakes, eth_address, status)
+	SELECT $1, approved_count, transfers_count, approved_iceflakes::uint256, transferred_iceflakes::uint256, eth_address, status
+	FROM unnest($2::bigint[], $3::bigint[], $4::text[], $5::text[], $6::text[], $7::text[])
+		AS t(approved_count, transfers_count, approved_iceflakes, transferred_iceflakes, eth_address, status)
+	WHERE NOT EXISTS (
+		SELECT 1
+		FROM (SELECT approved_iceflakes, transferred_iceflakes, status
+			  FROM coin_distribution_reconciliations r
+			  WHERE r.eth_address = t.eth_address
+			  ORDER BY r.reconciled_at DESC
+			  LIMIT 1) last
+		WHERE last.approved_iceflakes = t.approved_iceflakes::uint256
+		  AND last.transferred_iceflakes = t.transferred_iceflakes::uint256
+		  AND last.status = t.status)`
+	)
+	var (
+		startDate  time.Time
+		startBlock uint64
+	)
+	if err := databaseGetValue(ctx, cd.DB, configKeyCoinDistributerReconciliationStartDate, &startDate); err != nil {
+		return nil, errors.Wrapf(err, "failed to get %v", configKeyCoinDistributerReconciliationStartDate)
+	}
+	if err := databaseGetValue(ctx, cd.DB, configKeyCoinDistributerReconciliationStartBlock, &startBlock); err != nil {
+		return nil, errors.Wrapf(err, "failed to get %v", configKeyCoinDistributerReconciliationStartBlock)
+	}
+	records, err := storage.Select[reconciliationRecord](ctx, cd.DB, selectStmt, startDate.Time, defaultDistributionTarget, int64(startBlock))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to select reconciliation discrepancies")
+	}
+	totals, err := storage.Get[struct {
+		Addresses uint64
+		Transfers uint64
+	}](ctx, cd.DB, totalsStmt, startDate.Time, defaultDistributionTarget, int64(startBlock))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to select reconciliation totals")
+	}
+	summary := &reconciliationSummary{ReconciledAt: time.Now(), Records: records, Addresses: totals.Addresses, Transfers: totals.Transfers}
+	if len(records) == 0 {
+		return summary, nil
+	}
+
+	approvedCounts, transfersCounts := make([]int64, 0, len(records)), make([]int64, 0, len(records))
+	approved, transferred := make([]string, 0, len(records)), make([]string, 0, len(records))
+	addresses, statuses := make([]string, 0, len(records)), make([]string, 0, len(records))
+	for _, record := range records {
+		record.Status = record.reconciliationStatus()
+		approvedCounts = append(approvedCounts, int64(record.ApprovedCount))
+		transfersCounts = append(transfersCounts, int64(record.TransfersCount))
+		approved = append(approved, record.ApprovedIceflakes)
+		transferred = append(transferred, record.TransferredIceflakes)
+		addresses = append(addresses, record.EthAddress)
+		statuses = append(statuses, string(record.Status))
+	}
+	changed, err := storage.Exec(ctx, cd.DB, insertStmt,
+		summary.ReconciledAt.Time, approvedCounts, transfersCounts, approved, transferred, addresses, statuses)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to insert coin_distribution_reconciliations")
+	}
+	log.Info(fmt.Sprintf("reconciliation: %v discrepancies (%v new or changed) out of %v eth addresses", len(records), changed, summary.Addresses))
+
+	return summary, nil
+}
+
+// reconciliationStatus tells what's wrong with an eth address whose approved and transferred amounts differ.
+func (r *reconciliationRecord) reconciliationStatus() reconciliationStatus {
+	const base = 10
+	approved, _ := new(big.Int).SetString(r.ApprovedIceflakes, base)
+	transferred, _ := new(big.Int).SetString(r.TransferredIceflakes, base)
+	switch {
+	case approved == nil || transferred == nil:
+		return reconciliationStatusMismatched
+	case transferred.Sign() == 0:
+		return reconciliationStatusMissing
+	case approved.Sign() != 0 && transferred.Cmp(approved) > 0 && r.TransfersCount > 1:
+		return reconciliationStatusDuplicate
+	default:
+		return reconciliationStatusMismatched
+	}
+}
@@ -0,0 +1,29 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package coindistribution
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReconciliationStatus(t *testing.T) {
+	t.Parallel()
+
+	record := func(approved, transferred string, approvedCount, transfersCount uint64) *reconciliationRecord {
+		return &reconciliationRecord{
+			ApprovedIceflakes:    approved,
+			TransferredIceflakes: transferred,
+			ApprovedCount:        approvedCount,
+			TransfersCount:       transfersCount,
+		}
+	}
+
+	assert.Equal(t, reconciliationStatusMissing, record("100", "0", 1, 0).reconciliationStatus())
+	assert.Equal(t, reconciliationStatusDuplicate, record("100", "200", 1, 2).reconciliationStatus())
+	assert.Equal(t, reconciliationStatusMismatched, record("100", "200", 1, 1).reconciliationStatus())
+	assert.Equal(t, reconciliationStatusMismatched, record("200", "100", 2, 1).reconciliationStatus())
+	assert.Equal(t, reconciliationStatusMismatched, record("0", "100", 0, 1).reconciliationStatus())
+	assert.Equal(t, reconciliationStatusMismatched, record("bogus", "100", 1, 1).reconciliationStatus())
+}
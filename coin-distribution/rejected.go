@@ -0,0 +1,239 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package coindistribution
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/wintr/connectors/storage/v2"
+	"github.com/ice-blockchain/wintr/log"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+const (
+	// The rejected coin distributions are grouped by their TX, or by their batch if they were never sent.
+	rejectedBatchSQL = `SELECT max(eth_rejected_at) AS rejected_at,
+						   coalesce(eth_batch_id, '') AS batch_id,
+						   coalesce(eth_tx, '') AS tx_hash,
+						   target,
+						   coalesce(max(eth_error), '') AS error,
+						   sum(iceflakes)::text AS iceflakes,
+						   coalesce(max(eth_replaced_txs), '{}') AS replaced_txs,
+						   max(eth_nonce) AS nonce,
+						   array_agg(user_id ORDER BY user_id) AS user_ids,
+						   count(1) AS records
+					FROM pending_coin_distributions
+					WHERE eth_status = 'REJECTED'
+					  AND %v
+					GROUP BY target, coalesce(eth_batch_id, ''), coalesce(eth_tx, '')`
+	rejectedBatchWhere = `target = $1 AND coalesce(eth_batch_id, '') = $2 AND coalesce(eth_tx, '') = $3`
+)
+
+func (r *repository) GetRejectedCoinDistributions(ctx context.Context, arg *GetRejectedCoinDistributionsArg) (*RejectedCoinDistributions, error) {
+	conditions, whereArgs := []string{"1=1"}, []any{arg.Cursor, arg.Limit}
+	if arg.Target != "" {
+		conditions = append(conditions, "target = $3")
+		whereArgs = append(whereArgs, arg.Target)
+	}
+	sql := fmt.Sprintf(rejectedBatchSQL, strings.Join(conditions, " AND ")) + `
+					ORDER BY rejected_at DESC NULLS LAST, batch_id ASC, tx_hash ASC
+					LIMIT $2 OFFSET $1`
+	batches, err := storage.Select[RejectedCoinDistributionBatch](ctx, r.db, sql, whereArgs...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to select rejected coin distributions for %#v", arg)
+	}
+
+	return &RejectedCoinDistributions{
+		Batches: batches,
+		Cursor:  arg.Cursor + uint64(len(batches)),
+	}, nil
+}
+
+// ResolveRejectedCoinDistributions either puts the rejected coin distributions of a TX (or batch) back to the queue,
+// after checking the TX really failed on-chain, or moves them to the `failed_coin_distributions` ledger, for good.
+// Both decisions are recorded in `rejected_coin_distributions_audit`.
+//
+//nolint:funlen // .
+func (r *repository) ResolveRejectedCoinDistributions(ctx context.Context, operatorUserID string, arg *ResolveRejectedCoinDistributionsArg) error {
+	const (
+		auditSQL = `
+					INSERT INTO rejected_coin_distributions_audit(created_at, records, iceflakes, operator_user_id, decision, target, batch_id, eth_tx, eth_error, verification, user_ids)
+					SELECT $4, count(1), coalesce(sum(iceflakes), 0), $5, $6, $1, $2, $3, $7, $8, array_agg(user_id)
+					FROM resolved
+					HAVING count(1) > 0`
+		requeueSQL = `WITH resolved AS (
+						UPDATE pending_coin_distributions
+						SET eth_status = 'NEW',
+							eth_tx = NULL,
+							eth_nonce = NULL,
+							eth_tx_gas_price = 0,
+							eth_tx_gas_tip_cap = 0,
+							eth_tx_sent_at = NULL,
+							eth_gas_estimate = NULL,
+							eth_batch_id = NULL,
+							eth_replaced_txs = NULL,
+							eth_mined_tx = NULL,
+							eth_mined_block_hash = NULL,
+							eth_mined_block_number = NULL,
+							eth_rejected_at = NULL,
+							eth_error = NULL,
+							eth_fencing_token = NULL
+						WHERE eth_status = 'REJECTED'
+						  AND ` + rejectedBatchWhere + `
+						RETURNING user_id, iceflakes
+					 )` + auditSQL
+		failSQL = `WITH resolved AS (
+						DELETE FROM pending_coin_distributions
+						WHERE eth_status = 'REJECTED'
+						  AND ` + rejectedBatchWhere + `
+						RETURNING *
+					 ),
+					 ledger AS (
+						INSERT INTO failed_coin_distributions(failed_at, created_at, internal_id, day, iceflakes, user_id, eth_address, eth_tx, eth_replaced_txs, eth_error, batch_id, target, operator_user_id)
+						SELECT $4, created_at, internal_id, day, iceflakes, user_id, eth_address, coalesce(eth_tx, ''), eth_replaced_txs, coalesce(eth_error, ''), coalesce(eth_batch_id, ''), target, $5
+						FROM resolved
+					 )` + auditSQL
+		orphanTreeSQL = `DELETE FROM coin_distribution_merkle_trees WHERE batch_id = $1 AND published_at IS NULL`
+	)
+
+	rejected, err := storage.ExecOne[RejectedCoinDistributionBatch](ctx, r.db, fmt.Sprintf(rejectedBatchSQL, rejectedBatchWhere), arg.Target, arg.BatchID, arg.TxHash) //nolint:lll // .
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			err = ErrNotFound
+		}
+
+		return errors.Wrapf(err, "failed to get rejected coin distributions for %#v", arg)
+	}
+
+	var sql, verification string
+	switch strings.ToLower(arg.Decision) {
+	case rejectedDecisionRequeue:
+		if verification, err = r.checkRejectedTransactions(ctx, rejected); err != nil {
+			return err
+		}
+		sql = requeueSQL
+	case rejectedDecisionFail:
+		sql, verification = failSQL, "not checked"
+	default:
+		return errors.Errorf("unknown decision %q", arg.Decision)
+	}
+
+	return errors.Wrapf(storage.DoInTransaction(ctx, r.db, func(conn storage.QueryExecer) error {
+		rows, eErr := storage.Exec(ctx, conn, sql,
+			arg.Target, arg.BatchID, arg.TxHash, time.Now().Time, operatorUserID, strings.ToLower(arg.Decision), rejected.Error, verification)
+		if eErr != nil {
+			return errors.Wrap(eErr, "failed to resolve rejected coin distributions")
+		} else if rows == 0 {
+			// Resolved by someone else in the meantime.
+			return ErrNotFound
+		}
+		if rejected.BatchID != "" {
+			if _, eErr = storage.Exec(ctx, conn, orphanTreeSQL, rejected.BatchID); eErr != nil {
+				return errors.Wrapf(eErr, "failed to delete unpublished merkle tree of batch %v", rejected.BatchID)
+			}
+		}
+		log.Info(fmt.Sprintf("rejected coin distributions of target %v, batch %q, TX %q: %v by %v (%v record(s), %v)",
+			arg.Target, arg.BatchID, arg.TxHash, arg.Decision, operatorUserID, rejected.Records, verification))
+
+		return nil
+	}), "failed to %v rejected coin distributions for %#v", arg.Decision, arg)
+}
+
+// checkRejectedTransactions makes sure the rejected coin distributions were never paid, so they can be requeued.
+func (r *repository) checkRejectedTransactions(ctx context.Context, rejected *RejectedCoinDistributionBatch) (string, error) {
+	hashes := rejected.Hashes()
+	if len(hashes) == 0 {
+		return "never sent", nil
+	}
+
+	state, err := r.rejectedChainState(ctx, rejected, hashes)
+	if err != nil {
+		return "", err
+	}
+
+	return verifyRejectedTransactions(hashes, rejected.Nonce, state)
+}
+
+// rejectedChainState gets the nonce of the sender first, then the TXs known by the node and their receipts,
+// so a TX mined in the meantime is always found in the receipts, if the nonce moved because of it.
+func (r *repository) rejectedChainState(ctx context.Context, rejected *RejectedCoinDistributionBatch, hashes []string) (*rejectedChainState, error) {
+	chain := r.cfg.Target(rejected.Target)
+	if chain == nil {
+		return nil, errors.Errorf("unknown target %q", rejected.Target)
+	}
+	rpcClient, err := ethclient.DialContext(ctx, chain.RPC)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to connect to %v RPC", rejected.Target)
+	}
+	defer rpcClient.Close()
+
+	client, state := &ethClientImpl{RPC: rpcClient}, new(rejectedChainState)
+	if rejected.Nonce != nil {
+		if sender, sErr := chain.SenderAddress(); sErr != nil {
+			log.Warn(fmt.Sprintf("failed to get the sender of %v, its nonce is not checked: %v", rejected.Target, sErr))
+		} else {
+			nonce, nErr := client.ConfirmedNonceAt(ctx, sender)
+			if nErr != nil {
+				return nil, errors.Wrapf(nErr, "failed to get the nonce of %v", sender)
+			}
+			state.ConfirmedNonce = &nonce
+		}
+	}
+	if state.Known, err = client.KnownTransactions(ctx, hashes); err != nil {
+		return nil, errors.Wrapf(err, "failed to get transactions %v", hashes)
+	}
+	refs := make([]*string, 0, len(hashes))
+	for idx := range hashes {
+		refs = append(refs, &hashes[idx])
+	}
+	if state.Receipts, err = client.TransactionsReceipts(ctx, refs); err != nil {
+		return nil, errors.Wrapf(err, "failed to get receipts of %v", hashes)
+	}
+
+	return state, nil
+}
+
+// verifyRejectedTransactions allows to requeue only if none of the TXs was mined successfully and either
+//   - at least one of them failed,
+//   - or the nonce of the batch was used by another TX of the sender, so none of them can be mined anymore,
+//   - or the node knows none of them, I.E. they were signed, but never broadcast, because sending them failed.
+//
+// Otherwise, a TX that is not mined yet could still be.
+func verifyRejectedTransactions(hashes []string, nonce *int64, state *rejectedChainState) (string, error) {
+	var failed string
+	for _, hash := range hashes {
+		receipt := state.Receipts[hash]
+		switch {
+		case receipt == nil:
+			continue
+		case receipt.Status == ethTxStatusSuccessful:
+			return "", errors.Wrapf(ErrRequeueNotAllowed, "transaction %v was mined successfully in block %v (#%v)", hash, receipt.BlockHash, receipt.BlockNumber) //nolint:lll // .
+		case failed == "":
+			failed = fmt.Sprintf("transaction %v failed in block %v (#%v)", hash, receipt.BlockHash, receipt.BlockNumber)
+		}
+	}
+	switch {
+	case failed != "":
+		return failed, nil
+	case nonce != nil && state.ConfirmedNonce != nil && *state.ConfirmedNonce > uint64(*nonce):
+		return fmt.Sprintf("none of the transactions %v is mined, their nonce %v was used by another one", hashes, *nonce), nil
+	case len(state.Known) == 0:
+		return fmt.Sprintf("none of the transactions %v is known by the node", hashes), nil
+	default:
+		return "", errors.Wrapf(ErrRequeueNotAllowed, "none of the transactions %v is mined", hashes)
+	}
+}
+
+// Hashes returns the TX of the rejected coin distributions and all the ones it replaced, empty if they were never sent.
+func (b *RejectedCoinDistributionBatch) Hashes() []string {
+	if b.TxHash == "" {
+		return nil
+	}
+
+	return append([]string{b.TxHash}, b.ReplacedTXs...)
+}
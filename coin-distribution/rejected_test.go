@@ -0,0 +1,62 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package coindistribution
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyRejectedTransactions(t *testing.T) {
+	t.Parallel()
+
+	rejected := &RejectedCoinDistributionBatch{BatchID: "01HN7W4RQ0JXZ8K3GSM4YJ1D2V"}
+	require.Empty(t, rejected.Hashes())
+	rejected.TxHash, rejected.ReplacedTXs = "0x3", []string{"0x1", "0x2"}
+	hashes := rejected.Hashes()
+	require.Equal(t, []string{"0x3", "0x1", "0x2"}, hashes)
+
+	nonce, confirmedNonce := int64(7), uint64(7)
+	_, err := verifyRejectedTransactions(hashes, &nonce, &rejectedChainState{Known: []string{"0x3"}, ConfirmedNonce: &confirmedNonce})
+	require.ErrorIs(t, err, ErrRequeueNotAllowed, "not mined yet, it could still be")
+	_, err = verifyRejectedTransactions(hashes, nil, &rejectedChainState{Known: []string{"0x3"}})
+	require.ErrorIs(t, err, ErrRequeueNotAllowed, "not mined yet, it could still be")
+
+	verification, err := verifyRejectedTransactions(hashes, &nonce, &rejectedChainState{
+		Receipts: map[string]*txReceipt{"0x1": {Status: ethTxStatusFailed, BlockHash: "0xa", BlockNumber: 10}},
+		Known:    hashes,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "transaction 0x1 failed in block 0xa (#10)", verification)
+
+	_, err = verifyRejectedTransactions(hashes, &nonce, &rejectedChainState{
+		Receipts: map[string]*txReceipt{
+			"0x1": {Status: ethTxStatusFailed, BlockHash: "0xa", BlockNumber: 10},
+			"0x2": {Status: ethTxStatusSuccessful, BlockHash: "0xb", BlockNumber: 11},
+		},
+		Known: hashes,
+	})
+	require.ErrorIs(t, err, ErrRequeueNotAllowed, "already paid")
+
+	confirmedNonce = 8
+	verification, err = verifyRejectedTransactions(hashes, &nonce, &rejectedChainState{Known: []string{"0x3"}, ConfirmedNonce: &confirmedNonce})
+	require.NoError(t, err)
+	require.Equal(t, "none of the transactions [0x3 0x1 0x2] is mined, their nonce 7 was used by another one", verification)
+}
+
+func TestVerifyRejectedTransactionsSignedButNeverBroadcast(t *testing.T) {
+	t.Parallel()
+
+	// Sending failed, I.E. with insufficient funds, after the TX was signed and stored, so the node never got it.
+	rejected := &RejectedCoinDistributionBatch{BatchID: "01HN7W4RQ0JXZ8K3GSM4YJ1D2V", TxHash: "0x1"}
+	nonce, confirmedNonce := int64(7), uint64(7)
+	verification, err := verifyRejectedTransactions(rejected.Hashes(), &nonce, &rejectedChainState{ConfirmedNonce: &confirmedNonce})
+	require.NoError(t, err)
+	require.Equal(t, "none of the transactions [0x1] is known by the node", verification)
+
+	// Same without the sender nonce.
+	verification, err = verifyRejectedTransactions(rejected.Hashes(), &nonce, new(rejectedChainState))
+	require.NoError(t, err)
+	require.Equal(t, "none of the transactions [0x1] is known by the node", verification)
+}
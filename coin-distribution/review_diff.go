@@ -0,0 +1,135 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package coindistribution
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/wintr/connectors/storage/v2"
+)
+
+const (
+	defaultReviewDiffLimit       = 100
+	defaultReviewDiffJumpPercent = 100
+	// The previous cycle is the last one approved before today.
+	previousReviewDaySQL = `(SELECT max(review_day) FROM reviewed_coin_distributions WHERE decision != 'deny' AND review_day < current_date)`
+	// Both cycles are compared per user.
+	reviewDiffCyclesSQL = `WITH previous AS (
+								SELECT user_id,
+									   lower(max(eth_address)) AS eth_address,
+									   sum(ice) AS ice,
+									   max(country) AS country,
+									   max(referred_by_username) AS referred_by_username
+								FROM reviewed_coin_distributions
+								WHERE decision != 'deny'
+								  AND review_day = ` + previousReviewDaySQL + `
+								GROUP BY user_id
+							), pending AS (
+								SELECT user_id,
+									   max(username) AS username,
+									   lower(max(eth_address)) AS eth_address,
+									   sum(ice) AS ice,
+									   max(country) AS country,
+									   max(referred_by_username) AS referred_by_username
+								FROM coin_distributions_pending_review
+								GROUP BY user_id
+							)
+							`
+	reviewDiffRecordsSQL = reviewDiffCyclesSQL + `SELECT c.user_id,
+														 c.username,
+														 c.eth_address,
+														 coalesce(p.eth_address, '') AS previous_eth_address,
+														 c.ice,
+														 coalesce(p.ice, 0) AS previous_ice
+												  FROM pending c
+													   LEFT JOIN previous p ON p.user_id = c.user_id
+												  WHERE %v
+												  ORDER BY c.ice DESC, c.user_id ASC
+												  LIMIT $1`
+	reviewDiffAggregatesSQL = reviewDiffCyclesSQL + `SELECT coalesce(c.key, p.key) AS key,
+															coalesce(c.users, 0) AS users,
+															coalesce(c.ice, 0) AS ice,
+															coalesce(p.users, 0) AS previous_users,
+															coalesce(p.ice, 0) AS previous_ice
+													 FROM (SELECT %[1]v AS key, count(1) AS users, sum(ice) AS ice FROM pending GROUP BY 1) c
+														  FULL OUTER JOIN (SELECT %[1]v AS key, count(1) AS users, sum(ice) AS ice FROM previous GROUP BY 1) p ON p.key = c.key
+													 ORDER BY coalesce(c.ice, 0) DESC, coalesce(p.ice, 0) DESC, 1 ASC
+													 LIMIT $1`
+	reviewDiffSharedEthAddressesSQL = reviewDiffCyclesSQL + `SELECT eth_address,
+																	count(1) AS users,
+																	sum(ice) AS ice,
+																	array_agg(user_id ORDER BY user_id) AS user_ids
+															 FROM pending
+															 GROUP BY eth_address
+															 HAVING count(1) > 1
+															 ORDER BY count(1) DESC, eth_address ASC
+															 LIMIT $1`
+)
+
+// GetCoinDistributionsReviewDiff compares the coin distributions pending review with the ones approved in the previous cycle.
+//
+//nolint:funlen // .
+func (r *repository) GetCoinDistributionsReviewDiff(ctx context.Context, arg *GetCoinDistributionsReviewDiffArg) (*CoinDistributionsReviewDiff, error) {
+	if arg.Limit == 0 {
+		arg.Limit = defaultReviewDiffLimit
+	}
+	if arg.JumpPercent <= 0 {
+		arg.JumpPercent = defaultReviewDiffJumpPercent
+	}
+	previousReviewDay, err := storage.ExecOne[string](ctx, r.db, `SELECT coalesce(`+previousReviewDaySQL+`::text, '')`)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to select the previous review day")
+	}
+	diff := &CoinDistributionsReviewDiff{PreviousReviewDay: *previousReviewDay}
+	for _, section := range []struct {
+		dest  *[]*CoinDistributionsReviewDiffRecord
+		where string
+	}{
+		{dest: &diff.NewEthAddresses, where: `NOT EXISTS (SELECT 1 FROM previous pp WHERE pp.eth_address = c.eth_address)`},
+		{dest: &diff.ChangedEthAddresses, where: `p.eth_address != c.eth_address`},
+		{dest: &diff.AmountJumps, where: `p.ice > 0 AND (c.ice - p.ice) * 100.0 / p.ice > $2`},
+	} {
+		args := []any{arg.Limit}
+		if section.dest == &diff.AmountJumps {
+			args = append(args, arg.JumpPercent)
+		}
+		if *section.dest, err = storage.Select[CoinDistributionsReviewDiffRecord](ctx, r.db, fmt.Sprintf(reviewDiffRecordsSQL, section.where), args...); err != nil {
+			return nil, errors.Wrapf(err, "failed to select review diff records where %v", section.where)
+		}
+		for _, record := range *section.dest {
+			record.setIce()
+		}
+	}
+	if diff.SharedEthAddresses, err = storage.Select[CoinDistributionsReviewDiffSharedEthAddress](ctx, r.db, reviewDiffSharedEthAddressesSQL, arg.Limit); err != nil {
+		return nil, errors.Wrap(err, "failed to select review diff shared eth addresses")
+	}
+	for _, shared := range diff.SharedEthAddresses {
+		shared.Ice = float64(shared.IceInternal) / 100
+	}
+	for _, section := range []struct {
+		dest *[]*CoinDistributionsReviewDiffAggregate
+		key  string
+	}{
+		{dest: &diff.ByCountry, key: "country"},
+		{dest: &diff.ByReferredByUsername, key: "referred_by_username"},
+	} {
+		if *section.dest, err = storage.Select[CoinDistributionsReviewDiffAggregate](ctx, r.db, fmt.Sprintf(reviewDiffAggregatesSQL, section.key), arg.Limit); err != nil {
+			return nil, errors.Wrapf(err, "failed to select review diff aggregates by %v", section.key)
+		}
+		for _, aggregate := range *section.dest {
+			aggregate.Ice, aggregate.PreviousIce = float64(aggregate.IceInternal)/100, float64(aggregate.PreviousIceInternal)/100
+		}
+	}
+
+	return diff, nil
+}
+
+func (r *CoinDistributionsReviewDiffRecord) setIce() {
+	r.Ice, r.PreviousIce = float64(r.IceInternal)/100, float64(r.PreviousIceInternal)/100
+	if r.PreviousIceInternal > 0 {
+		r.IncreasePercent = float64(r.IceInternal-r.PreviousIceInternal) * 100 / float64(r.PreviousIceInternal)
+	}
+}
@@ -0,0 +1,25 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package coindistribution
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCoinDistributionsReviewDiffRecordSetIce(t *testing.T) {
+	t.Parallel()
+
+	record := &CoinDistributionsReviewDiffRecord{IceInternal: 25050, PreviousIceInternal: 10020}
+	record.setIce()
+	assert.InDelta(t, 250.5, record.Ice, 0.001)
+	assert.InDelta(t, 100.2, record.PreviousIce, 0.001)
+	assert.InDelta(t, 150, record.IncreasePercent, 0.001)
+
+	record = &CoinDistributionsReviewDiffRecord{IceInternal: 25050}
+	record.setIce()
+	assert.InDelta(t, 250.5, record.Ice, 0.001)
+	assert.Zero(t, record.PreviousIce)
+	assert.Zero(t, record.IncreasePercent)
+}
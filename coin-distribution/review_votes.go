@@ -0,0 +1,101 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package coindistribution
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/wintr/connectors/storage/v2"
+	"github.com/ice-blockchain/wintr/log"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+const (
+	reviewVotesSQL = `SELECT created_at, snapshot, reviewer_user_id, decision, filter
+					  FROM coin_distribution_review_votes
+					  WHERE closed_at IS NULL
+						AND %v
+					  ORDER BY created_at ASC`
+)
+
+// castReviewVote records the vote of the reviewer on the current snapshot of the coin distributions matching the filter.
+// An approval is executed only once `coin_distributer_review_quorum` distinct reviewers approved the same snapshot,
+// a deny is executed right away and closes the open votes of the snapshot (or all of them, if the whole cycle is denied).
+//
+//nolint:funlen // .
+func (r *repository) castReviewVote(
+	ctx context.Context, conn storage.QueryExecer, reviewerUserID string, arg *ReviewCoinDistributionsArg, votes *CoinDistributionsReviewVotes,
+) error {
+	const (
+		// Locks the quorum, so the concurrent votes are counted one after another.
+		quorumSQL = `SELECT value::bigint FROM global WHERE key = $1 FOR UPDATE`
+		voteSQL   = `INSERT INTO coin_distribution_review_votes(created_at, snapshot, reviewer_user_id, decision, filter, closed_at)
+					 VALUES ($1, $2, $3, $4, $5, (CASE WHEN $4 = 'deny' THEN $1::timestamp END))
+					 ON CONFLICT (snapshot, reviewer_user_id) WHERE closed_at IS NULL DO UPDATE
+					 SET created_at = EXCLUDED.created_at,
+						 decision = EXCLUDED.decision,
+						 filter = EXCLUDED.filter,
+						 closed_at = EXCLUDED.closed_at`
+		closeSQL = `UPDATE coin_distribution_review_votes SET closed_at = $1 WHERE closed_at IS NULL AND (snapshot = $2 OR $3)`
+	)
+	quorum, err := storage.ExecOne[uint64](ctx, conn, quorumSQL, configKeyCoinDistributerReviewQuorum)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get %v", configKeyCoinDistributerReviewQuorum)
+	}
+	if votes.Snapshot, err = reviewSnapshot(ctx, conn, &arg.CoinDistributionsForReviewFilter); err != nil {
+		return err
+	}
+	if arg.Snapshot != "" && arg.Snapshot != votes.Snapshot {
+		return errors.Wrapf(ErrReviewSnapshotChanged, "reviewed %v, current %v", arg.Snapshot, votes.Snapshot)
+	}
+	votes.Quorum = max(*quorum, 1)
+
+	now, decision, filter := time.Now(), strings.ToLower(arg.Decision), arg.CoinDistributionsForReviewFilter.String()
+	if _, err = storage.Exec(ctx, conn, voteSQL, now.Time, votes.Snapshot, reviewerUserID, decision, filter); err != nil {
+		return errors.Wrapf(err, "failed to record the %v vote of %v", decision, reviewerUserID)
+	}
+	if decision != "deny" {
+		if votes.Votes, err = storage.Select[CoinDistributionsReviewVote](ctx, conn, fmt.Sprintf(reviewVotesSQL, "snapshot = $1"), votes.Snapshot); err != nil {
+			return errors.Wrapf(err, "failed to select the votes of snapshot %v", votes.Snapshot)
+		}
+		if votes.Executed = uint64(len(votes.Votes)) >= votes.Quorum; !votes.Executed {
+			log.Info(fmt.Sprintf("coin distributions snapshot %v (%v): %v/%v approval(s)", votes.Snapshot, filter, len(votes.Votes), votes.Quorum))
+
+			return errors.Wrap(r.sendCoinDistributionsReviewVoteSlackMessage(ctx, decision, filter, votes),
+				"failed to sendCoinDistributionsReviewVoteSlackMessage")
+		}
+	}
+	votes.Executed = true
+	if _, err = storage.Exec(ctx, conn, closeSQL, now.Time, votes.Snapshot, decision == "deny" && arg.isEmpty()); err != nil {
+		return errors.Wrapf(err, "failed to close the votes of snapshot %v", votes.Snapshot)
+	}
+
+	return nil
+}
+
+// reviewSnapshot identifies the coin distributions pending review matching the filter, it changes if any of them changes.
+func reviewSnapshot(ctx context.Context, db storage.Querier, filter *CoinDistributionsForReviewFilter) (string, error) {
+	conditions, whereArgs := filter.where(1)
+	sql := fmt.Sprintf(`SELECT coalesce(md5(string_agg(user_id || ':' || day || ':' || ice || ':' || eth_address, ',' ORDER BY user_id, day)), '')
+						FROM coin_distributions_pending_review
+						WHERE %v`, strings.Join(append(conditions, "1=1"), " AND "))
+	rows, err := storage.ExecOne[string](ctx, db, sql, whereArgs...)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to hash coin_distributions_pending_review matching %v", filter)
+	}
+	sum := sha256.Sum256([]byte(filter.String() + "|" + *rows))
+
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func (r *repository) getOpenReviewVotes(ctx context.Context) ([]*CoinDistributionsReviewVote, error) {
+	votes, err := storage.Select[CoinDistributionsReviewVote](ctx, r.db, fmt.Sprintf(reviewVotesSQL, "1=1"))
+
+	return votes, errors.Wrap(err, "failed to select open coin_distribution_review_votes")
+}
@@ -0,0 +1,165 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package coindistribution
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"os"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/goccy/go-json"
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/wintr/log"
+)
+
+func mustNewSigner(ctx context.Context, chain *chainConfig) signer {
+	switch {
+	case chain.Signer.Remote.URL != "":
+		client, err := rpc.DialContext(ctx, chain.Signer.Remote.URL)
+		log.Panic(errors.Wrap(err, "failed to connect to the remote signer")) //nolint:revive,nolintlint //.
+
+		return &remoteSigner{RPC: client, Account: common.HexToAddress(chain.Signer.Remote.Address)}
+
+	case chain.Signer.Keystore.File != "":
+		keyJSON, err := os.ReadFile(chain.Signer.Keystore.File)
+		log.Panic(errors.Wrapf(err, "failed to read keystore file %v", chain.Signer.Keystore.File)) //nolint:revive,nolintlint //.
+		key, err := keystore.DecryptKey(keyJSON, os.Getenv(chain.Signer.Keystore.PassphraseEnv))
+		log.Panic(errors.Wrapf(err, "failed to decrypt keystore file %v", chain.Signer.Keystore.File)) //nolint:revive,nolintlint //.
+
+		return &localSigner{Key: key.PrivateKey}
+
+	default:
+		key, err := crypto.HexToECDSA(chain.PrivateKey)
+		log.Panic(errors.Wrap(err, "failed to parse private key")) //nolint:revive,nolintlint //.
+
+		return &localSigner{Key: key}
+	}
+}
+
+// SenderAddress returns the address the TXs of the chain are sent from, without decrypting the keystore,
+// so it's available to the services that do not sign anything.
+func (c *chainConfig) SenderAddress() (common.Address, error) {
+	switch {
+	case c.Signer.Remote.URL != "":
+		return common.HexToAddress(c.Signer.Remote.Address), nil
+
+	case c.Signer.Keystore.File != "":
+		keyJSON, err := os.ReadFile(c.Signer.Keystore.File)
+		if err != nil {
+			return common.Address{}, errors.Wrapf(err, "failed to read keystore file %v", c.Signer.Keystore.File)
+		}
+		var key struct {
+			Address string `json:"address"`
+		}
+		if err = json.Unmarshal(keyJSON, &key); err != nil {
+			return common.Address{}, errors.Wrapf(err, "failed to parse keystore file %v", c.Signer.Keystore.File)
+		} else if !common.IsHexAddress(key.Address) {
+			return common.Address{}, errors.Errorf("invalid address %q in keystore file %v", key.Address, c.Signer.Keystore.File)
+		}
+
+		return common.HexToAddress(key.Address), nil
+
+	case c.PrivateKey != "":
+		key, err := crypto.HexToECDSA(c.PrivateKey)
+		if err != nil {
+			return common.Address{}, errors.Wrap(err, "failed to parse private key")
+		}
+
+		return crypto.PubkeyToAddress(key.PublicKey), nil
+
+	default:
+		return common.Address{}, errors.New("no signer configured")
+	}
+}
+
+func (s *localSigner) Address() common.Address {
+	return crypto.PubkeyToAddress(s.Key.PublicKey)
+}
+
+func (s *localSigner) SignTx(_ context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	signed, err := types.SignTx(tx, types.LatestSignerForChainID(chainID), s.Key)
+
+	return signed, errors.Wrap(err, "failed to sign transaction")
+}
+
+func (*localSigner) Close() error {
+	return nil
+}
+
+func (s *remoteSigner) Address() common.Address {
+	return s.Account
+}
+
+// SignTx asks the remote signer (Clef compatible `account_signTransaction`) to sign the transaction and
+// checks that what we got back is the very same transaction, signed by our account.
+func (s *remoteSigner) SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	args := &remoteSignTxArgs{
+		From:    s.Account,
+		To:      tx.To(),
+		Gas:     hexutil.Uint64(tx.Gas()),
+		Value:   (*hexutil.Big)(tx.Value()),
+		Nonce:   hexutil.Uint64(tx.Nonce()),
+		Data:    tx.Data(),
+		ChainID: (*hexutil.Big)(chainID),
+	}
+	if tx.Type() == types.DynamicFeeTxType {
+		args.MaxFeePerGas, args.MaxPriorityFeePerGas = (*hexutil.Big)(tx.GasFeeCap()), (*hexutil.Big)(tx.GasTipCap())
+	} else {
+		args.GasPrice = (*hexutil.Big)(tx.GasPrice())
+	}
+
+	var result remoteSignTxResult
+	if err := s.RPC.CallContext(ctx, &result, "account_signTransaction", args); err != nil {
+		var rpcErr rpc.Error
+		if errors.As(err, &rpcErr) {
+			// The signer got the request and refused it, i.e. it was denied by its rules or by the operator.
+			return nil, errors.Wrapf(errSigningRejected, "%v", err)
+		}
+
+		return nil, errors.Wrap(err, "failed to call account_signTransaction")
+	}
+
+	signed := new(types.Transaction)
+	if err := signed.UnmarshalBinary(result.Raw); err != nil {
+		return nil, errors.Wrap(err, "failed to decode signed transaction")
+	}
+
+	return signed, errors.Wrap(verifySignedTx(tx, signed, s.Account, chainID), "remote signer returned an unexpected transaction")
+}
+
+func (s *remoteSigner) Close() error {
+	s.RPC.Close()
+
+	return nil
+}
+
+func verifySignedTx(tx, signed *types.Transaction, account common.Address, chainID *big.Int) error {
+	sender, err := types.Sender(types.LatestSignerForChainID(chainID), signed)
+	if err != nil {
+		return errors.Wrap(err, "failed to recover sender")
+	}
+	if sender != account {
+		return errors.Errorf("signed by %v instead of %v", sender.Hex(), account.Hex())
+	}
+	if signed.Type() != tx.Type() ||
+		signed.Nonce() != tx.Nonce() ||
+		signed.Gas() != tx.Gas() ||
+		signed.GasFeeCap().Cmp(tx.GasFeeCap()) != 0 ||
+		signed.GasTipCap().Cmp(tx.GasTipCap()) != 0 ||
+		signed.Value().Cmp(tx.Value()) != 0 ||
+		(signed.To() == nil) != (tx.To() == nil) ||
+		(tx.To() != nil && *signed.To() != *tx.To()) ||
+		!bytes.Equal(signed.Data(), tx.Data()) {
+		return errors.Errorf("transaction %v does not match the requested one", signed.Hash().Hex())
+	}
+
+	return nil
+}
@@ -0,0 +1,167 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package coindistribution
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type (
+	mockedRemoteSigner struct {
+		key         *ecdsa.PrivateKey
+		reject      bool
+		nonceOffset uint64
+	}
+)
+
+func (m *mockedRemoteSigner) SignTransaction(_ context.Context, args *remoteSignTxArgs) (*remoteSignTxResult, error) {
+	if m.reject {
+		return nil, errors.New("request denied") //nolint:goerr113 // .
+	}
+	tx := types.NewTx(&types.DynamicFeeTx{
+		ChainID:   args.ChainID.ToInt(),
+		Nonce:     uint64(args.Nonce) + m.nonceOffset,
+		GasTipCap: args.MaxPriorityFeePerGas.ToInt(),
+		GasFeeCap: args.MaxFeePerGas.ToInt(),
+		Gas:       uint64(args.Gas),
+		To:        args.To,
+		Value:     args.Value.ToInt(),
+		Data:      args.Data,
+	})
+	signed, err := types.SignTx(tx, types.LatestSignerForChainID(args.ChainID.ToInt()), m.key)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // .
+	}
+	raw, err := signed.MarshalBinary()
+
+	return &remoteSignTxResult{Raw: raw}, err //nolint:wrapcheck // .
+}
+
+func newMockedRemoteSigner(t *testing.T, mock *mockedRemoteSigner) *remoteSigner {
+	t.Helper()
+
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("account", mock))
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(httpServer.Close)
+	t.Cleanup(server.Stop)
+
+	client, err := rpc.DialContext(context.Background(), httpServer.URL)
+	require.NoError(t, err)
+
+	return &remoteSigner{RPC: client, Account: crypto.PubkeyToAddress(mock.key.PublicKey)}
+}
+
+func testTransaction(chainID *big.Int) *types.Transaction {
+	return types.NewTx(&types.DynamicFeeTx{
+		ChainID:   chainID,
+		Nonce:     42,
+		GasTipCap: big.NewInt(2),
+		GasFeeCap: big.NewInt(30),
+		Gas:       21000,
+		To:        &common.Address{1},
+		Value:     big.NewInt(0),
+		Data:      []byte{1, 2, 3},
+	})
+}
+
+func TestRemoteSigner(t *testing.T) {
+	t.Parallel()
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	chainID := big.NewInt(97)
+
+	t.Run("Signed", func(t *testing.T) {
+		t.Parallel()
+
+		s := newMockedRemoteSigner(t, &mockedRemoteSigner{key: key})
+		defer s.Close()
+
+		signed, sErr := s.SignTx(context.Background(), testTransaction(chainID), chainID)
+		require.NoError(t, sErr)
+		sender, sErr := types.Sender(types.LatestSignerForChainID(chainID), signed)
+		require.NoError(t, sErr)
+		assert.Equal(t, s.Address(), sender)
+		assert.EqualValues(t, 42, signed.Nonce())
+	})
+	t.Run("Rejected", func(t *testing.T) {
+		t.Parallel()
+
+		s := newMockedRemoteSigner(t, &mockedRemoteSigner{key: key, reject: true})
+		defer s.Close()
+
+		_, sErr := s.SignTx(context.Background(), testTransaction(chainID), chainID)
+		require.ErrorIs(t, sErr, errSigningRejected)
+		assert.Zero(t, handleRPCError(context.Background(), sErr))
+	})
+	t.Run("Tampered", func(t *testing.T) {
+		t.Parallel()
+
+		s := newMockedRemoteSigner(t, &mockedRemoteSigner{key: key, nonceOffset: 1})
+		defer s.Close()
+
+		_, sErr := s.SignTx(context.Background(), testTransaction(chainID), chainID)
+		require.ErrorContains(t, sErr, "does not match")
+	})
+	t.Run("WrongAccount", func(t *testing.T) {
+		t.Parallel()
+
+		s := newMockedRemoteSigner(t, &mockedRemoteSigner{key: key})
+		defer s.Close()
+		s.Account = common.Address{2}
+
+		_, sErr := s.SignTx(context.Background(), testTransaction(chainID), chainID)
+		require.ErrorContains(t, sErr, "instead of")
+	})
+}
+
+func TestKeystoreSigner(t *testing.T) { //nolint:paralleltest // It sets an environment variable.
+	const passphraseEnv = "TEST_COIN_DISTRIBUTION_KEYSTORE_PASSPHRASE"
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	keyJSON, err := keystore.EncryptKey(&keystore.Key{
+		Address:    crypto.PubkeyToAddress(key.PublicKey),
+		PrivateKey: key,
+	}, "secret", keystore.LightScryptN, keystore.LightScryptP)
+	require.NoError(t, err)
+
+	chain := new(chainConfig)
+	chain.Signer.Keystore.File = filepath.Join(t.TempDir(), "keystore.json")
+	chain.Signer.Keystore.PassphraseEnv = passphraseEnv
+	require.NoError(t, os.WriteFile(chain.Signer.Keystore.File, keyJSON, 0o600))
+
+	// The sender is known without the passphrase.
+	sender, err := chain.SenderAddress()
+	require.NoError(t, err)
+	require.Equal(t, crypto.PubkeyToAddress(key.PublicKey), sender)
+
+	t.Setenv(passphraseEnv, "wrong")
+	require.Panics(t, func() { mustNewSigner(context.Background(), chain) })
+
+	t.Setenv(passphraseEnv, "secret")
+	s := mustNewSigner(context.Background(), chain)
+	defer s.Close()
+	require.Equal(t, crypto.PubkeyToAddress(key.PublicKey), s.Address())
+
+	chainID := big.NewInt(1)
+	signed, err := s.SignTx(context.Background(), testTransaction(chainID), chainID)
+	require.NoError(t, err)
+	require.NoError(t, verifySignedTx(testTransaction(chainID), signed, s.Address(), chainID))
+}
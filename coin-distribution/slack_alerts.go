@@ -34,8 +34,49 @@ func (r *repository) sendCurrentCoinDistributionsAvailableForReviewAreDeniedSlac
 	return errors.Wrap(sendSlackMessage(ctx, text, r.cfg.AlertSlackWebhook), "failed to sendSlackMessage")
 }
 
-func sendNewCoinDistributionsAvailableForReviewSlackMessage(ctx context.Context) error {
+func (r *repository) sendCoinDistributionsReviewVoteSlackMessage(ctx context.Context, decision, filter string, votes *CoinDistributionsReviewVotes) error {
+	if filter == "" {
+		filter = "all"
+	}
+	text := fmt.Sprintf(":ballot_box_with_check:`%v` pending coin distributions matching `%v` got an `%v` vote, `%v/%v` approvals so far :ballot_box_with_check:\n`snapshot`: `%v`", r.cfg.Environment, filter, decision, len(votes.Votes), votes.Quorum, votes.Snapshot) //nolint:lll // .
+
+	return errors.Wrap(sendSlackMessage(ctx, text, r.cfg.AlertSlackWebhook), "failed to sendSlackMessage")
+}
+
+func (r *repository) sendFilteredCoinDistributionsReviewedSlackMessage(ctx context.Context, decision, filter string, recipients uint64, iceCoins float64) error {
+	emoji := ":white_check_mark:"
+	if decision == "deny" {
+		emoji = ":no_entry:"
+	}
+	text := fmt.Sprintf("%[1]v`%[2]v` pending coin distributions matching `%[3]v` got `%[4]v` decision %[1]v\n`users`: `%[5]v`\n`coins`: `%[6]v`", emoji, r.cfg.Environment, filter, decision, recipients, fmt.Sprintf("%.2f", iceCoins)) //nolint:lll // .
+
+	return errors.Wrap(sendSlackMessage(ctx, text, r.cfg.AlertSlackWebhook), "failed to sendSlackMessage")
+}
+
+func (r *repository) sendCoinDistributionsHeldSlackMessage(ctx context.Context, arg *HoldCoinDistributionsArg, held *HeldCoinDistributions) error {
+	until := "the next cycle"
+	if arg.UntilReleased {
+		until = "they're released"
+	}
+	text := fmt.Sprintf(":hourglass:`%v` coin distributions of `%v` user(s) are on hold until %v :hourglass:\n`reason`: `%v`\n`rows`: `%v`\n`coins`: `%v`", r.cfg.Environment, len(arg.UserIDs), until, arg.Reason, held.Rows, fmt.Sprintf("%.2f", held.Ice)) //nolint:lll // .
+
+	return errors.Wrap(sendSlackMessage(ctx, text, r.cfg.AlertSlackWebhook), "failed to sendSlackMessage")
+}
+
+func (r *repository) sendCoinDistributionHoldsReleasedSlackMessage(ctx context.Context, reviewerUserID string, holds uint64) error {
+	text := fmt.Sprintf(":arrow_forward:`%v` `%v` coin distribution hold(s) are released by `%v`, they'll be carried into the next cycle :arrow_forward:", r.cfg.Environment, holds, reviewerUserID) //nolint:lll // .
+
+	return errors.Wrap(sendSlackMessage(ctx, text, r.cfg.AlertSlackWebhook), "failed to sendSlackMessage")
+}
+
+func sendNewCoinDistributionsAvailableForReviewSlackMessage(ctx context.Context, screened []*screeningSummary, unscreened uint64) error {
 	text := fmt.Sprintf(":eyes:`%v` <%v|new coin distributions are available for review> :eyes:", cfg.Environment, cfg.ReviewURL)
+	for _, summary := range screened {
+		text += fmt.Sprintf("\n`denied (%v)`: `%v`", summary.Reason, summary.Rows)
+	}
+	if unscreened != 0 {
+		text += fmt.Sprintf("\n:warning: `not screened (%v)`: `%v` eth address(es)", screeningReasonContractAddress, unscreened)
+	}
 
 	return errors.Wrap(sendSlackMessage(ctx, text, cfg.AlertSlackWebhook), "failed to sendSlackMessage")
 }
@@ -52,42 +93,63 @@ func SendNewCoinDistributionCollectionCycleEndedPrematurelySlackMessage(ctx cont
 	return errors.Wrap(sendSlackMessage(ctx, text, cfg.AlertSlackWebhook), "failed to sendSlackMessage")
 }
 
-func sendCoinDistributerIsNowOnlineSlackMessage(ctx context.Context) error {
-	text := fmt.Sprintf(":sun_with_face:`%v` coin distributer is now online :sun_with_face:", cfg.Environment)
+func sendCoinDistributerIsNowOnlineSlackMessage(ctx context.Context, target string) error {
+	text := fmt.Sprintf(":sun_with_face:`%v` coin distributer is now online :sun_with_face:", environment(target))
 
 	return errors.Wrap(sendSlackMessage(ctx, text, cfg.AlertSlackWebhook), "failed to sendSlackMessage")
 }
 
-func sendCoinDistributerIsNowOfflineSlackMessage(ctx context.Context) error {
-	text := fmt.Sprintf(":sleeping:`%v` coin distributer is now offline :sleeping:", cfg.Environment)
+func sendCoinDistributerIsNowOfflineSlackMessage(ctx context.Context, target string) error {
+	text := fmt.Sprintf(":sleeping:`%v` coin distributer is now offline :sleeping:", environment(target))
 
 	return errors.Wrap(sendSlackMessage(ctx, text, cfg.AlertSlackWebhook), "failed to sendSlackMessage")
 }
 
-func sendCoinDistributerHasUnfinishedWork(ctx context.Context) error {
-	text := fmt.Sprintf(":octagonal_sign:`%v` coin distributer has unfinished work :octagonal_sign:", cfg.Environment)
+func sendCoinDistributerHasUnfinishedWork(ctx context.Context, target string) error {
+	text := fmt.Sprintf(":octagonal_sign:`%v` coin distributer has unfinished work :octagonal_sign:", environment(target))
 
 	return errors.Wrap(sendSlackMessage(ctx, text, cfg.AlertSlackWebhook), "failed to sendSlackMessage")
 }
 
-func sendCoinDistributerTransactionStuck(ctx context.Context, hash string, start *time.Time) error {
-	text := fmt.Sprintf(":octagonal_sign:`%v` transaction `%v` stuck in PENDING state since `%v` :octagonal_sign:",
-		cfg.Environment,
+func sendCoinDistributerTransactionStuck(ctx context.Context, target, hash string, replacements int, start *time.Time) error {
+	text := fmt.Sprintf(":octagonal_sign:`%v` transaction `%v` stuck in PENDING state since `%v` after `%v` replacement(s) :octagonal_sign:",
+		environment(target),
 		hash,
 		start.Format(stdlibtime.RFC3339),
+		replacements,
+	)
+
+	return errors.Wrap(sendSlackMessage(ctx, text, cfg.AlertSlackWebhook), "failed to sendSlackMessage")
+}
+
+func sendCoinDistributerTransactionReorged(ctx context.Context, target string, mined *minedTransaction) error {
+	text := fmt.Sprintf(":warning:`%v` block `%v` (#%v) with transaction `%v` was reorged out, the transaction is being re-verified :warning:",
+		environment(target),
+		mined.BlockHash,
+		mined.BlockNumber,
+		mined.Hash,
+	)
+
+	return errors.Wrap(sendSlackMessage(ctx, text, cfg.AlertSlackWebhook), "failed to sendSlackMessage")
+}
+
+func sendCoinDistributerBalanceTooLowSlackMessage(ctx context.Context, target string, status *balanceStatus) error {
+	text := fmt.Sprintf(":money_with_wings:`%v` coin distribution is paused: %v. It resumes once the balances are topped up, or we could lower `coin_distributer_min_token_balance_percent` / `coin_distributer_min_gas_balance_gwei` :money_with_wings:", //nolint:lll // .
+		environment(target),
+		status,
 	)
 
 	return errors.Wrap(sendSlackMessage(ctx, text, cfg.AlertSlackWebhook), "failed to sendSlackMessage")
 }
 
-func sendAllCurrentCoinDistributionsWereCommittedInEthereumSlackMessage(ctx context.Context) error {
-	text := fmt.Sprintf(":tada:`%v` all coin distributions have been committed successfully in ethereum :tada:", cfg.Environment)
+func sendAllCurrentCoinDistributionsWereCommittedInEthereumSlackMessage(ctx context.Context, target string) error {
+	text := fmt.Sprintf(":tada:`%v` all coin distributions have been committed successfully in %v :tada:", environment(target), target)
 
 	return errors.Wrap(sendSlackMessage(ctx, text, cfg.AlertSlackWebhook), "failed to sendSlackMessage")
 }
 
-func sendCoinDistributerStartedProcessingSlackMessage(ctx context.Context) error {
-	text := fmt.Sprintf("🏁`%v` started processing pending ethereum distributions 🏁", cfg.Environment)
+func sendCoinDistributerStartedProcessingSlackMessage(ctx context.Context, target string) error {
+	text := fmt.Sprintf("🏁`%v` started processing pending %v distributions 🏁", environment(target), target)
 
 	return errors.Wrap(sendSlackMessage(ctx, text, cfg.AlertSlackWebhook), "failed to sendSlackMessage")
 }
@@ -98,12 +160,49 @@ func sendEthereumGasLimitTooLowSlackMessage(ctx context.Context, errMsg string)
 	return errors.Wrap(sendSlackMessage(ctx, text, cfg.AlertSlackWebhook), "failed to sendSlackMessage")
 }
 
-func sendCoinDistributionsProcessingStoppedDueToUnrecoverableFailureSlackMessage(ctx context.Context, reason string) error {
-	text := fmt.Sprintf(":bangbang:`%v` coin distribution processing stopped due to failure :bangbang:\n:rotating_light: reason: `%v` :rotating_light:", cfg.Environment, reason) //nolint:lll // .
+func sendEthereumGasFeeCapExceededSlackMessage(ctx context.Context, errMsg string) error {
+	text := fmt.Sprintf(":warning:`%v` ethereum %v. Coin distribution is paused until gas prices go down, or we could raise the `coin_distributer_max_fee_per_gas_cap` :warning:", cfg.Environment, errMsg) //nolint:lll // .
+
+	return errors.Wrap(sendSlackMessage(ctx, text, cfg.AlertSlackWebhook), "failed to sendSlackMessage")
+}
+
+func sendCoinDistributionsProcessingStoppedDueToUnrecoverableFailureSlackMessage(ctx context.Context, target, reason string) error {
+	text := fmt.Sprintf(":bangbang:`%v` coin distribution processing stopped due to failure :bangbang:\n:rotating_light: reason: `%v` :rotating_light:", environment(target), reason) //nolint:lll // .
 
 	return errors.Wrap(sendSlackMessage(ctx, text, cfg.AlertSlackWebhook), "failed to sendSlackMessage")
 }
 
+func sendCoinDistributionsReconciliationSlackMessage(ctx context.Context, summary *reconciliationSummary) error {
+	counts := make(map[reconciliationStatus]int, 3) //nolint:gomnd // 3 statuses.
+	for _, record := range summary.Records {
+		counts[record.Status]++
+	}
+	icon := ":white_check_mark:"
+	if len(summary.Records) != 0 {
+		icon = ":mag:"
+	}
+	text := fmt.Sprintf("%[1]v`%[2]v` coin distributions reconciliation: `%[3]v` eth addresses, `%[4]v` on-chain transfers, `%[5]v` missing, `%[6]v` duplicate, `%[7]v` mismatched payout(s) %[1]v", //nolint:lll // .
+		icon,
+		cfg.Environment,
+		summary.Addresses,
+		summary.Transfers,
+		counts[reconciliationStatusMissing],
+		counts[reconciliationStatusDuplicate],
+		counts[reconciliationStatusMismatched],
+	)
+
+	return errors.Wrap(sendSlackMessage(ctx, text, cfg.AlertSlackWebhook), "failed to sendSlackMessage")
+}
+
+// environment is the label of the messages about the given distribution target, the default one is not labeled.
+func environment(target string) string {
+	if target == "" || target == defaultDistributionTarget {
+		return cfg.Environment
+	}
+
+	return cfg.Environment + "/" + target
+}
+
 func sendSlackMessage(ctx context.Context, text, alertSlackWebhook string) error {
 	message := struct {
 		Text string `json:"text,omitempty"`
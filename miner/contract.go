@@ -33,6 +33,52 @@ type (
 		RemainingFreeMiningSessions uint64     `json:"remainingFreeMiningSessions,omitempty"`
 		MiningStreak                uint64     `json:"miningStreak,omitempty"`
 	}
+	SimulationArg struct {
+		// StartedAt is the moment the snapshot was taken at. The 1st iteration happens 1 hour after it.
+		StartedAt *time.Time
+		// BaseMiningRate overrides the adoption milestones, if set.
+		BaseMiningRate float64
+		// StartingMilestone is the adoption milestone (1 based) the simulation starts with, if BaseMiningRate is not set.
+		StartingMilestone uint64
+		Iterations        uint64
+		// ResumeMiningSessions extends the mining sessions of the users that are mining at StartedAt, so that they never stop mining.
+		ResumeMiningSessions bool
+	}
+	SimulationReport struct {
+		StartedAt  *time.Time             `json:"startedAt"`
+		EndedAt    *time.Time             `json:"endedAt"`
+		Iterations []*SimulationIteration `json:"iterations"`
+		Users      []*SimulatedUser       `json:"users"`
+	}
+	SimulationIteration struct {
+		At                     *time.Time `json:"at"`
+		BaseMiningRate         float64    `json:"baseMiningRate"`
+		BalanceTotalStandard   float64    `json:"balanceTotalStandard"`
+		BalanceTotalPreStaking float64    `json:"balanceTotalPreStaking"`
+		BalanceTotalMinted     float64    `json:"balanceTotalMinted"`
+		BalanceTotalSlashed    float64    `json:"balanceTotalSlashed"`
+		Milestone              uint64     `json:"milestone,omitempty"`
+		ActiveUsers            uint64     `json:"activeUsers"`
+	}
+	SimulatedUser struct {
+		UserID                 string  `json:"userId"`
+		Username               string  `json:"username,omitempty"`
+		ID                     int64   `json:"id"`
+		BalanceTotalStandard   float64 `json:"balanceTotalStandard"`
+		BalanceTotalPreStaking float64 `json:"balanceTotalPreStaking"`
+		BalanceTotalMinted     float64 `json:"balanceTotalMinted"`
+		BalanceTotalSlashed    float64 `json:"balanceTotalSlashed"`
+		BalanceSolo            float64 `json:"balanceSolo"`
+		BalanceT0              float64 `json:"balanceT0"`
+		BalanceT1              float64 `json:"balanceT1"`
+		BalanceT2              float64 `json:"balanceT2"`
+		SlashingRateSolo       float64 `json:"slashingRateSolo"`
+		SlashingRateT0         float64 `json:"slashingRateT0"`
+		SlashingRateForT0      float64 `json:"slashingRateForT0"`
+		SlashingRateForTMinus1 float64 `json:"slashingRateForTMinus1"`
+		ActiveT1Referrals      int32   `json:"activeT1Referrals"`
+		ActiveT2Referrals      int32   `json:"activeT2Referrals"`
+	}
 )
 
 // Private API.
@@ -150,11 +196,21 @@ type (
 		model.PreStakingBonusField
 	}
 
+	simulatedAdoption struct {
+		milestoneReachedAt *time.Time
+		baseMiningRate     float64
+		milestone          uint64
+	}
+
 	referralCountGuardUpdatedUser struct {
 		model.ReferralsCountChangeGuardUpdatedAtField
 		model.DeserializedUsersKey
 	}
 
+	tMinus1ReferralChanged struct {
+		ID, OldIDTMinus1, NewIDTMinus1 int64
+	}
+
 	referralThatStoppedMining struct {
 		StoppedMiningAt     *time.Time
 		ID, IDT0, IDTMinus1 int64
@@ -217,6 +217,7 @@ func (u *user) processEthereumCoinDistribution(
 			UserID:             u.UserID,
 			EarnerUserID:       u.UserID,
 			EthAddress:         u.MiningBlockchainAccountAddress,
+			Country:            u.Country,
 			InternalID:         u.ID,
 			Balance:            0,
 		}
@@ -153,8 +153,11 @@ func (m *miner) mine(ctx context.Context, workerNumber int64) {
 		extraBonusOnlyUpdatedUsers                                           = make([]*extrabonusnotifier.UpdatedUser, 0, batchSize)
 		referralsCountGuardOnlyUpdatedUsers                                  = make([]*referralCountGuardUpdatedUser, 0, batchSize)
 		referralsUpdated                                                     = make([]*referralUpdated, 0, batchSize)
+		tMinus1ReferralsChanged                                              = make([]*tMinus1ReferralChanged, 0, batchSize)
 		histories                                                            = make([]*model.User, 0, batchSize)
 		userGlobalRanks                                                      = make([]redis.Z, 0, batchSize)
+		userLeaderboardRanks                                                 = make(map[string][]redis.Z)
+		userLeaderboardUnranks                                               = make(map[string][]any)
 		historyColumns, historyInsertMetadata                                = dwh.InsertDDL(int(batchSize))
 		shouldSynchronizeBalanceFunc                                         = func(batchNumberArg uint64) bool { return false }
 		startedCoinDistributionCollecting                                    = isCoinDistributionCollectorEnabled(now)
@@ -199,8 +202,15 @@ func (m *miner) mine(ctx context.Context, workerNumber int64) {
 		extraBonusOnlyUpdatedUsers = extraBonusOnlyUpdatedUsers[:0]
 		referralsCountGuardOnlyUpdatedUsers = referralsCountGuardOnlyUpdatedUsers[:0]
 		referralsUpdated = referralsUpdated[:0]
+		tMinus1ReferralsChanged = tMinus1ReferralsChanged[:0]
 		histories = histories[:0]
 		userGlobalRanks = userGlobalRanks[:0]
+		for k := range userLeaderboardRanks {
+			delete(userLeaderboardRanks, k)
+		}
+		for k := range userLeaderboardUnranks {
+			delete(userLeaderboardUnranks, k)
+		}
 		referralsThatStoppedMining = referralsThatStoppedMining[:0]
 		coinDistributions = coinDistributions[:0]
 		for k := range t0Referrals {
@@ -371,6 +381,9 @@ func (m *miner) mine(ctx context.Context, workerNumber int64) {
 						tMinus1Ref = tMinus1Referrals[updatedUser.IDTMinus1]
 					}
 				}
+				if changed := didTMinus1ReferralChange(usr, updatedUser); changed != nil {
+					tMinus1ReferralsChanged = append(tMinus1ReferralsChanged, changed)
+				}
 				userCoinDistributions, balanceDistributedForT0, balanceDistributedForTMinus1 := updatedUser.processEthereumCoinDistribution(startedCoinDistributionCollecting, now, t0Ref, tMinus1Ref)
 				coinDistributions = append(coinDistributions, userCoinDistributions...)
 				if balanceDistributedForT0 > 0 {
@@ -411,6 +424,8 @@ func (m *miner) mine(ctx context.Context, workerNumber int64) {
 			totalBalance := totalStandardBalance + totalPreStakingBalance
 			if shouldSynchronizeBalance {
 				userGlobalRanks = append(userGlobalRanks, balancesynchronizer.GlobalRank(usr.ID, totalBalance))
+				miningStreak := model.CalculateMiningStreak(now, usr.MiningSessionSoloStartedAt, usr.MiningSessionSoloEndedAt, cfg.MiningSessionDuration.Max)
+				balancesynchronizer.AppendLeaderboardRanks(userLeaderboardRanks, userLeaderboardUnranks, usr.ID, usr.Country, totalBalance, usr.ActiveT1Referrals, miningStreak) //nolint:lll // .
 				msgs = append(msgs, balancesynchronizer.BalanceUpdatedMessage(reqCtx, usr.UserID, totalStandardBalance, totalPreStakingBalance))
 			}
 		}
@@ -508,7 +523,7 @@ func (m *miner) mine(ctx context.Context, workerNumber int64) {
 
 		var pipeliner redis.Pipeliner
 		var transactional bool
-		if len(pendingBalancesForTMinus1)+len(pendingBalancesForT0)+len(balanceT1EthereumIncr)+len(balanceT2EthereumIncr)+len(t1ReferralsToIncrementActiveValue)+len(t2ReferralsToIncrementActiveValue)+len(referralsCountGuardOnlyUpdatedUsers)+len(t1ReferralsThatStoppedMining)+len(t2ReferralsThatStoppedMining)+len(extraBonusOnlyUpdatedUsers)+len(referralsUpdated)+len(userGlobalRanks) > 0 {
+		if len(pendingBalancesForTMinus1)+len(pendingBalancesForT0)+len(balanceT1EthereumIncr)+len(balanceT2EthereumIncr)+len(t1ReferralsToIncrementActiveValue)+len(t2ReferralsToIncrementActiveValue)+len(referralsCountGuardOnlyUpdatedUsers)+len(t1ReferralsThatStoppedMining)+len(t2ReferralsThatStoppedMining)+len(extraBonusOnlyUpdatedUsers)+len(referralsUpdated)+len(tMinus1ReferralsChanged)+len(userGlobalRanks) > 0 {
 			pipeliner = m.db.TxPipeline()
 			transactional = true
 		} else {
@@ -558,10 +573,18 @@ func (m *miner) mine(ctx context.Context, workerNumber int64) {
 					return err
 				}
 			}
+			for _, value := range tMinus1ReferralsChanged {
+				if err := tokenomics.MoveReferral(reqCtx, pipeliner, tokenomics.T2ReferralTier, value.ID, value.OldIDTMinus1, value.NewIDTMinus1); err != nil {
+					return err
+				}
+			}
 			if len(userGlobalRanks) > 0 {
 				if err := pipeliner.ZAdd(reqCtx, "top_miners", userGlobalRanks...).Err(); err != nil {
 					return err
 				}
+				if err := balancesynchronizer.UpdateLeaderboards(reqCtx, pipeliner, userLeaderboardRanks, userLeaderboardUnranks); err != nil {
+					return err
+				}
 			}
 			for idT0, amount := range balanceT1EthereumIncr {
 				if amount == 0 {
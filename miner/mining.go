@@ -90,7 +90,7 @@ func mine(baseMiningRate float64, now *time.Time, usr *user, t0Ref, tMinus1Ref *
 			mintedAmount += rate
 		}
 		if t0Ref != nil && !t0Ref.MiningSessionSoloEndedAt.IsNil() && t0Ref.MiningSessionSoloEndedAt.After(*now.Time) {
-			rate := 25 * baseMiningRate * elapsedTimeFraction / 100
+			rate := float64(cfg.ReferralBonusMiningRates.T0) * baseMiningRate * elapsedTimeFraction / 100
 			updatedUser.BalanceForT0 += rate
 			updatedUser.BalanceT0 += rate
 			mintedAmount += rate
@@ -100,7 +100,7 @@ func mine(baseMiningRate float64, now *time.Time, usr *user, t0Ref, tMinus1Ref *
 			}
 		}
 		if tMinus1Ref != nil && !tMinus1Ref.MiningSessionSoloEndedAt.IsNil() && tMinus1Ref.MiningSessionSoloEndedAt.After(*now.Time) {
-			updatedUser.BalanceForTMinus1 += 5 * baseMiningRate * elapsedTimeFraction / 100
+			updatedUser.BalanceForTMinus1 += float64(cfg.ReferralBonusMiningRates.T2) * baseMiningRate * elapsedTimeFraction / 100
 
 			if updatedUser.SlashingRateForTMinus1 != 0 {
 				updatedUser.SlashingRateForTMinus1 = 0
@@ -112,8 +112,8 @@ func mine(baseMiningRate float64, now *time.Time, usr *user, t0Ref, tMinus1Ref *
 		if updatedUser.ActiveT2Referrals < 0 {
 			updatedUser.ActiveT2Referrals = 0
 		}
-		t1Rate := (25 * float64(updatedUser.ActiveT1Referrals)) * baseMiningRate * elapsedTimeFraction / 100
-		t2Rate := (5 * float64(updatedUser.ActiveT2Referrals)) * baseMiningRate * elapsedTimeFraction / 100
+		t1Rate := (float64(cfg.ReferralBonusMiningRates.T1) * float64(updatedUser.ActiveT1Referrals)) * baseMiningRate * elapsedTimeFraction / 100
+		t2Rate := (float64(cfg.ReferralBonusMiningRates.T2) * float64(updatedUser.ActiveT2Referrals)) * baseMiningRate * elapsedTimeFraction / 100
 		updatedUser.BalanceT1 += t1Rate
 		updatedUser.BalanceT2 += t2Rate
 		mintedAmount += t1Rate + t2Rate
@@ -31,6 +31,19 @@ func changeT0AndTMinus1Referrals(usr *user) (IDT0Changed, IDTMinus1Changed bool)
 	return IDT0Changed, IDTMinus1Changed
 }
 
+// didTMinus1ReferralChange checks if the T-1 of the user that is about to be stored differs from the one it had, so it's moved to the T2 referrals of the new one.
+func didTMinus1ReferralChange(before, after *user) *tMinus1ReferralChanged {
+	if before == nil || after == nil || after.IDTMinus1 == 0 || abs(after.IDTMinus1) == abs(before.IDTMinus1) {
+		return nil
+	}
+
+	return &tMinus1ReferralChanged{
+		ID:           before.ID,
+		OldIDTMinus1: before.IDTMinus1,
+		NewIDTMinus1: after.IDTMinus1,
+	}
+}
+
 func didReferralJustStopMining(now *time.Time, before *user, t0Ref, tMinus1Ref *referral) *referralThatStoppedMining {
 	if before == nil ||
 		before.MiningSessionSoloEndedAt.IsNil() ||
@@ -43,3 +43,37 @@ func Test_didReferralJustStopMining(t *testing.T) {
 		require.Equal(t, tMinus1Ref.ID, x.IDTMinus1)
 	})
 }
+
+func Test_didTMinus1ReferralChange(t *testing.T) {
+	t.Parallel()
+
+	t.Run("EmptyData", func(t *testing.T) {
+		require.Nil(t, didTMinus1ReferralChange(nil, nil))
+		require.Nil(t, didTMinus1ReferralChange(newUser(), nil))
+	})
+
+	t.Run("Unchanged", func(t *testing.T) {
+		before, after := newUser(), newUser()
+		before.ID, before.IDTMinus1 = 1, -3
+		after.IDTMinus1 = 3
+		require.Nil(t, didTMinus1ReferralChange(before, after))
+	})
+
+	t.Run("Not stored", func(t *testing.T) {
+		before, after := newUser(), newUser()
+		before.ID, before.IDTMinus1 = 1, 3
+		require.Nil(t, didTMinus1ReferralChange(before, after))
+	})
+
+	t.Run("Changed", func(t *testing.T) {
+		before, after := newUser(), newUser()
+		before.ID, before.IDTMinus1 = 1, 3
+		after.ID, after.IDTMinus1 = 1, -4
+
+		x := didTMinus1ReferralChange(before, after)
+		require.NotNil(t, x)
+		require.Equal(t, int64(1), x.ID)
+		require.Equal(t, int64(3), x.OldIDTMinus1)
+		require.Equal(t, int64(-4), x.NewIDTMinus1)
+	})
+}
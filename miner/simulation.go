@@ -0,0 +1,329 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package miner
+
+import (
+	"sort"
+	stdlibtime "time"
+
+	"github.com/ice-blockchain/freezer/model"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+// Simulate runs the same mining math the workers do, but in memory, over a snapshot of users, without touching any storage.
+//
+//nolint:funlen,gocognit,revive // It mirrors the worker loop on purpose.
+func Simulate(arg *SimulationArg, snapshot []*model.User) *SimulationReport {
+	users, ids := make(map[int64]*user, len(snapshot)), make([]int64, 0, len(snapshot))
+	wasMining := make(map[int64]bool, len(snapshot))
+	for _, snapshotUser := range snapshot {
+		usr := newSimulatedUser(snapshotUser)
+		if _, found := users[usr.ID]; !found {
+			ids = append(ids, usr.ID)
+		}
+		users[usr.ID] = usr
+		wasMining[usr.ID] = !usr.MiningSessionSoloEndedAt.IsNil() && usr.MiningSessionSoloEndedAt.After(*arg.StartedAt.Time)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	var (
+		report = &SimulationReport{
+			StartedAt:  arg.StartedAt,
+			EndedAt:    arg.StartedAt,
+			Iterations: make([]*SimulationIteration, 0, arg.Iterations),
+			Users:      make([]*SimulatedUser, 0, len(ids)),
+		}
+		adoption                                                             = newSimulatedAdoption(arg)
+		simulatedUsers                                                       = make(map[int64]*SimulatedUser, len(ids))
+		pendingBalancesForTMinus1, pendingBalancesForT0                      = make(map[int64]float64), make(map[int64]float64)
+		t1ReferralsToIncrementActiveValue, t2ReferralsToIncrementActiveValue = make(map[int64]int32), make(map[int64]int32)
+		t1ReferralsThatStoppedMining, t2ReferralsThatStoppedMining           = make(map[int64]int32), make(map[int64]int32)
+	)
+	for _, id := range ids {
+		simulatedUsers[id] = &SimulatedUser{ID: id, UserID: users[id].UserID, Username: users[id].Username}
+	}
+	for iteration := uint64(1); iteration <= arg.Iterations; iteration++ {
+		now := time.New(arg.StartedAt.Add(stdlibtime.Duration(iteration) * simulationStep()))
+		if arg.ResumeMiningSessions {
+			for _, id := range ids {
+				if usr := users[id]; wasMining[id] && !usr.MiningSessionSoloEndedAt.After(*now.Time) {
+					usr.MiningSessionSoloEndedAt = time.New(usr.MiningSessionSoloEndedAt.Add(simulatedMiningSessionDuration()))
+				}
+			}
+		}
+		baseMiningRate, milestone, activeUsers := adoption.next(now, users)
+		stats := &SimulationIteration{At: now, BaseMiningRate: baseMiningRate, Milestone: milestone, ActiveUsers: activeUsers}
+		for _, id := range ids {
+			usr := users[id]
+			t0Ref, tMinus1Ref := simulatedReferral(users, usr.IDT0), simulatedReferral(users, usr.IDTMinus1)
+			updatedUser, shouldGenerateHistory, IDT0Changed, pendingAmountForTMinus1, pendingAmountForT0 := mine(baseMiningRate, now, usr, t0Ref, tMinus1Ref)
+			if updatedUser == nil {
+				stats.BalanceTotalStandard += usr.BalanceTotalStandard
+				stats.BalanceTotalPreStaking += usr.BalanceTotalPreStaking
+
+				continue
+			}
+			if userStoppedMining := didReferralJustStopMining(now, usr, t0Ref, tMinus1Ref); userStoppedMining != nil {
+				if userStoppedMining.IDT0 > 0 {
+					t1ReferralsThatStoppedMining[userStoppedMining.IDT0]++
+				}
+				if userStoppedMining.IDTMinus1 > 0 {
+					t2ReferralsThatStoppedMining[userStoppedMining.IDTMinus1]++
+				}
+			}
+			if t0Ref != nil {
+				if IDT0Changed {
+					if !usr.BalanceLastUpdatedAt.IsNil() {
+						t1ReferralsToIncrementActiveValue[t0Ref.ID]++
+						if t0Ref.IDT0 != 0 {
+							t2ReferralsToIncrementActiveValue[t0Ref.IDT0]++
+						}
+					}
+					if usr.ActiveT1Referrals > 0 && t0Ref.ID != 0 {
+						t2ReferralsToIncrementActiveValue[t0Ref.ID] += usr.ActiveT1Referrals
+					}
+				}
+				if usr.IDTMinus1 != t0Ref.IDT0 {
+					updatedUser.IDTMinus1 = t0Ref.IDT0
+					tMinus1Ref = simulatedReferral(users, updatedUser.IDTMinus1)
+				}
+			}
+			if tMinus1Ref != nil && tMinus1Ref.ID != 0 && pendingAmountForTMinus1 != 0 {
+				pendingBalancesForTMinus1[tMinus1Ref.ID] += pendingAmountForTMinus1
+			}
+			if t0Ref != nil && t0Ref.ID != 0 && pendingAmountForT0 != 0 {
+				pendingBalancesForT0[t0Ref.ID] += pendingAmountForT0
+			}
+			mintedBefore, slashedBefore := usr.BalanceTotalMinted, usr.BalanceTotalSlashed
+			if shouldGenerateHistory {
+				mintedBefore, slashedBefore = 0, 0
+			}
+			simulatedUsers[id].BalanceTotalMinted += updatedUser.BalanceTotalMinted - mintedBefore
+			simulatedUsers[id].BalanceTotalSlashed += updatedUser.BalanceTotalSlashed - slashedBefore
+			stats.BalanceTotalMinted += updatedUser.BalanceTotalMinted - mintedBefore
+			stats.BalanceTotalSlashed += updatedUser.BalanceTotalSlashed - slashedBefore
+			stats.BalanceTotalStandard += updatedUser.BalanceTotalStandard
+			stats.BalanceTotalPreStaking += updatedUser.BalanceTotalPreStaking
+			usr.applySimulatedUpdate(updatedUser)
+		}
+		for id, amount := range pendingBalancesForT0 {
+			if usr, found := users[id]; found {
+				usr.BalanceT1Pending += amount
+			}
+			delete(pendingBalancesForT0, id)
+		}
+		for id, amount := range pendingBalancesForTMinus1 {
+			if usr, found := users[id]; found {
+				usr.BalanceT2Pending += amount
+			}
+			delete(pendingBalancesForTMinus1, id)
+		}
+		for id, value := range t1ReferralsToIncrementActiveValue {
+			if usr, found := users[id]; found {
+				usr.ActiveT1Referrals += value
+			}
+			delete(t1ReferralsToIncrementActiveValue, id)
+		}
+		for id, value := range t2ReferralsToIncrementActiveValue {
+			if usr, found := users[id]; found {
+				usr.ActiveT2Referrals += value
+			}
+			delete(t2ReferralsToIncrementActiveValue, id)
+		}
+		for id, value := range t1ReferralsThatStoppedMining {
+			if usr, found := users[id]; found {
+				usr.ActiveT1Referrals -= value
+			}
+			delete(t1ReferralsThatStoppedMining, id)
+		}
+		for id, value := range t2ReferralsThatStoppedMining {
+			if usr, found := users[id]; found {
+				usr.ActiveT2Referrals -= value
+			}
+			delete(t2ReferralsThatStoppedMining, id)
+		}
+		report.Iterations = append(report.Iterations, stats)
+		report.EndedAt = now
+	}
+	for _, id := range ids {
+		usr, simulatedUser := users[id], simulatedUsers[id]
+		simulatedUser.BalanceTotalStandard, simulatedUser.BalanceTotalPreStaking = usr.BalanceTotalStandard, usr.BalanceTotalPreStaking
+		simulatedUser.BalanceSolo, simulatedUser.BalanceT0, simulatedUser.BalanceT1, simulatedUser.BalanceT2 = usr.BalanceSolo, usr.BalanceT0, usr.BalanceT1, usr.BalanceT2
+		simulatedUser.SlashingRateSolo, simulatedUser.SlashingRateT0 = usr.SlashingRateSolo, usr.SlashingRateT0
+		simulatedUser.SlashingRateForT0, simulatedUser.SlashingRateForTMinus1 = usr.SlashingRateForT0, usr.SlashingRateForTMinus1
+		simulatedUser.ActiveT1Referrals, simulatedUser.ActiveT2Referrals = usr.ActiveT1Referrals, usr.ActiveT2Referrals
+		report.Users = append(report.Users, simulatedUser)
+	}
+
+	return report
+}
+
+func newSimulatedAdoption(arg *SimulationArg) *simulatedAdoption {
+	if arg.BaseMiningRate > 0 {
+		return &simulatedAdoption{baseMiningRate: arg.BaseMiningRate}
+	}
+	milestone := arg.StartingMilestone
+	if milestone == 0 {
+		milestone = 1
+	}
+	if milestones := uint64(len(cfg.AdoptionMilestoneSwitch.ActiveUserMilestones)); milestone > milestones {
+		milestone = milestones
+	}
+	adoption := &simulatedAdoption{milestone: milestone}
+	if milestone > 0 {
+		adoption.baseMiningRate = cfg.AdoptionMilestoneSwitch.ActiveUserMilestones[milestone-1].BaseMiningRate
+	}
+
+	return adoption
+}
+
+// next switches to the next adoption milestone, if the active users were above its threshold for long enough,
+// the same way tokenomics does, with the difference that the active users are counted only within the snapshot.
+func (a *simulatedAdoption) next(now *time.Time, users map[int64]*user) (baseMiningRate float64, milestone, activeUsers uint64) {
+	for _, usr := range users {
+		if !usr.MiningSessionSoloEndedAt.IsNil() && usr.MiningSessionSoloEndedAt.After(*now.Time) {
+			activeUsers++
+		}
+	}
+	if a.milestone == 0 || a.milestone >= uint64(len(cfg.AdoptionMilestoneSwitch.ActiveUserMilestones)) {
+		return a.baseMiningRate, a.milestone, activeUsers
+	}
+	if nextMilestone := cfg.AdoptionMilestoneSwitch.ActiveUserMilestones[a.milestone]; activeUsers < nextMilestone.Users {
+		a.milestoneReachedAt = nil
+	} else if a.milestoneReachedAt.IsNil() {
+		a.milestoneReachedAt = now
+	}
+	required := stdlibtime.Duration(cfg.AdoptionMilestoneSwitch.ConsecutiveDurationsRequired) * cfg.AdoptionMilestoneSwitch.Duration
+	if !a.milestoneReachedAt.IsNil() && now.Sub(*a.milestoneReachedAt.Time) >= required {
+		a.milestone++
+		a.baseMiningRate = cfg.AdoptionMilestoneSwitch.ActiveUserMilestones[a.milestone-1].BaseMiningRate
+		a.milestoneReachedAt = nil
+	}
+
+	return a.baseMiningRate, a.milestone, activeUsers
+}
+
+func simulationStep() stdlibtime.Duration {
+	if cfg.Development {
+		return stdlibtime.Minute
+	}
+
+	return stdlibtime.Hour
+}
+
+func simulatedMiningSessionDuration() stdlibtime.Duration {
+	if cfg.MiningSessionDuration.Max > 0 {
+		return cfg.MiningSessionDuration.Max
+	}
+
+	return 24 * stdlibtime.Hour //nolint:gomnd // .
+}
+
+func abs(id int64) int64 {
+	if id < 0 {
+		return -id
+	}
+
+	return id
+}
+
+func (u *user) applySimulatedUpdate(updatedUser *user) {
+	idT0, idTMinus1 := u.IDT0, u.IDTMinus1
+	u.UpdatedUser = updatedUser.UpdatedUser
+	if u.IDT0 == 0 { // Zero values are never persisted, because of `omitempty`.
+		u.IDT0 = idT0
+	}
+	if u.IDTMinus1 == 0 {
+		u.IDTMinus1 = idTMinus1
+	}
+}
+
+func simulatedReferral(users map[int64]*user, id int64) *referral {
+	u, found := users[abs(id)]
+	if id == 0 || !found {
+		return nil
+	}
+
+	return &referral{
+		MiningSessionSoloStartedAtField:         u.MiningSessionSoloStartedAtField,
+		MiningSessionSoloEndedAtField:           u.MiningSessionSoloEndedAtField,
+		MiningSessionSoloPreviouslyEndedAtField: u.MiningSessionSoloPreviouslyEndedAtField,
+		ResurrectSoloUsedAtField:                u.ResurrectSoloUsedAtField,
+		UserIDField:                             u.UserIDField,
+		CountryField:                            u.CountryField,
+		UsernameField:                           u.UsernameField,
+		MiningBlockchainAccountAddressField:     u.MiningBlockchainAccountAddressField,
+		KYCState:                                u.KYCState,
+		IDT0Field:                               model.IDT0Field{IDT0: abs(u.IDT0)},
+		DeserializedUsersKey:                    u.DeserializedUsersKey,
+		BalanceTotalStandardField:               u.BalanceTotalStandardField,
+		BalanceSoloEthereumField:                u.BalanceSoloEthereumField,
+		BalanceT0EthereumField:                  u.BalanceT0EthereumField,
+		BalanceT1EthereumField:                  u.BalanceT1EthereumField,
+		BalanceT2EthereumField:                  u.BalanceT2EthereumField,
+		PreStakingAllocationField:               u.PreStakingAllocationField,
+		PreStakingBonusField:                    u.PreStakingBonusField,
+	}
+}
+
+func newSimulatedUser(usr *model.User) *user {
+	return &user{
+		MiningSessionSoloLastStartedAtField:     usr.MiningSessionSoloLastStartedAtField,
+		MiningSessionSoloStartedAtField:         usr.MiningSessionSoloStartedAtField,
+		MiningSessionSoloEndedAtField:           usr.MiningSessionSoloEndedAtField,
+		MiningSessionSoloPreviouslyEndedAtField: usr.MiningSessionSoloPreviouslyEndedAtField,
+		ExtraBonusStartedAtField:                usr.ExtraBonusStartedAtField,
+		KYCState:                                usr.KYCState,
+		MiningBlockchainAccountAddressField:     usr.MiningBlockchainAccountAddressField,
+		CountryField:                            usr.CountryField,
+		UsernameField:                           usr.UsernameField,
+		UserIDField:                             usr.UserIDField,
+		UpdatedUser: UpdatedUser{
+			ExtraBonusLastClaimAvailableAtField:                    usr.ExtraBonusLastClaimAvailableAtField,
+			BalanceLastUpdatedAtField:                              usr.BalanceLastUpdatedAtField,
+			ResurrectSoloUsedAtField:                               usr.ResurrectSoloUsedAtField,
+			ResurrectT0UsedAtField:                                 usr.ResurrectT0UsedAtField,
+			ResurrectTMinus1UsedAtField:                            usr.ResurrectTMinus1UsedAtField,
+			SoloLastEthereumCoinDistributionProcessedAtField:       usr.SoloLastEthereumCoinDistributionProcessedAtField,
+			ForT0LastEthereumCoinDistributionProcessedAtField:      usr.ForT0LastEthereumCoinDistributionProcessedAtField,
+			ForTMinus1LastEthereumCoinDistributionProcessedAtField: usr.ForTMinus1LastEthereumCoinDistributionProcessedAtField,
+			DeserializedUsersKey:                                   usr.DeserializedUsersKey,
+			IDT0Field:                                              usr.IDT0Field,
+			IDTMinus1Field:                                         usr.IDTMinus1Field,
+			BalanceTotalStandardField:                              usr.BalanceTotalStandardField,
+			BalanceTotalPreStakingField:                            usr.BalanceTotalPreStakingField,
+			BalanceTotalMintedField:                                usr.BalanceTotalMintedField,
+			BalanceTotalSlashedField:                               usr.BalanceTotalSlashedField,
+			BalanceSoloPendingAppliedField:                         usr.BalanceSoloPendingAppliedField,
+			BalanceT1PendingAppliedField:                           usr.BalanceT1PendingAppliedField,
+			BalanceT2PendingAppliedField:                           usr.BalanceT2PendingAppliedField,
+			BalanceSoloField:                                       usr.BalanceSoloField,
+			BalanceT0Field:                                         usr.BalanceT0Field,
+			BalanceT1Field:                                         usr.BalanceT1Field,
+			BalanceT2Field:                                         usr.BalanceT2Field,
+			BalanceForT0Field:                                      usr.BalanceForT0Field,
+			BalanceForTMinus1Field:                                 usr.BalanceForTMinus1Field,
+			BalanceSoloEthereumField:                               usr.BalanceSoloEthereumField,
+			BalanceT0EthereumField:                                 usr.BalanceT0EthereumField,
+			BalanceT1EthereumField:                                 usr.BalanceT1EthereumField,
+			BalanceT2EthereumField:                                 usr.BalanceT2EthereumField,
+			BalanceForT0EthereumField:                              usr.BalanceForT0EthereumField,
+			BalanceForTMinus1EthereumField:                         usr.BalanceForTMinus1EthereumField,
+			SlashingRateSoloField:                                  usr.SlashingRateSoloField,
+			SlashingRateT0Field:                                    usr.SlashingRateT0Field,
+			SlashingRateT1Field:                                    usr.SlashingRateT1Field,
+			SlashingRateT2Field:                                    usr.SlashingRateT2Field,
+			SlashingRateForT0Field:                                 usr.SlashingRateForT0Field,
+			SlashingRateForTMinus1Field:                            usr.SlashingRateForTMinus1Field,
+			ExtraBonusDaysClaimNotAvailableField:                   usr.ExtraBonusDaysClaimNotAvailableField,
+		},
+		BalanceSoloPendingField:   usr.BalanceSoloPendingField,
+		BalanceT1PendingField:     usr.BalanceT1PendingField,
+		BalanceT2PendingField:     usr.BalanceT2PendingField,
+		ActiveT1ReferralsField:    usr.ActiveT1ReferralsField,
+		ActiveT2ReferralsField:    usr.ActiveT2ReferralsField,
+		PreStakingBonusField:      usr.PreStakingBonusField,
+		PreStakingAllocationField: usr.PreStakingAllocationField,
+		ExtraBonusField:           usr.ExtraBonusField,
+		UTCOffsetField:            usr.UTCOffsetField,
+	}
+}
@@ -0,0 +1,59 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package miner
+
+import (
+	"testing"
+	stdlibtime "time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ice-blockchain/freezer/model"
+)
+
+func TestSimulate(t *testing.T) {
+	t.Parallel()
+
+	newSnapshotUser := func(id, idT0 int64, activeT1Referrals int32) *model.User {
+		usr := new(model.User)
+		usr.ID = id
+		usr.UserID = "test_user_id_" + string(rune('0'+id))
+		usr.IDT0 = idT0
+		usr.ActiveT1Referrals = activeT1Referrals
+		usr.MiningSessionSoloStartedAt = testTime
+		usr.MiningSessionSoloEndedAt = timeDelta(90 * stdlibtime.Minute)
+		usr.BalanceLastUpdatedAt = testTime
+
+		return usr
+	}
+	snapshot := []*model.User{newSnapshotUser(2, 1, 0), newSnapshotUser(1, 0, 1)}
+
+	report := Simulate(&SimulationArg{StartedAt: testTime, BaseMiningRate: testMiningBase, Iterations: 1}, snapshot)
+	require.Len(t, report.Iterations, 1)
+	require.Len(t, report.Users, 2)
+	require.EqualValues(t, timeDelta(stdlibtime.Hour), report.EndedAt)
+	require.EqualValues(t, 1, report.Users[0].ID)
+	require.EqualValues(t, 2, report.Users[1].ID)
+	require.EqualValues(t, 2, report.Iterations[0].ActiveUsers)
+	require.EqualValues(t, testMiningBase, report.Users[0].BalanceSolo)
+	require.EqualValues(t, testMiningBase/4, report.Users[0].BalanceT1)
+	require.EqualValues(t, testMiningBase, report.Users[1].BalanceSolo)
+	require.EqualValues(t, testMiningBase/4, report.Users[1].BalanceT0)
+
+	report = Simulate(&SimulationArg{StartedAt: testTime, BaseMiningRate: testMiningBase, Iterations: 3}, snapshot)
+	require.Len(t, report.Iterations, 3)
+	require.EqualValues(t, 0, report.Iterations[2].ActiveUsers)
+	require.EqualValues(t, 0, report.Users[0].ActiveT1Referrals)
+	require.NotZero(t, report.Users[0].SlashingRateSolo)
+	require.NotZero(t, report.Iterations[2].BalanceTotalSlashed)
+
+	report = Simulate(&SimulationArg{StartedAt: testTime, BaseMiningRate: testMiningBase, Iterations: 3, ResumeMiningSessions: true}, snapshot)
+	require.EqualValues(t, 2, report.Iterations[2].ActiveUsers)
+	require.EqualValues(t, 3*testMiningBase, report.Users[0].BalanceSolo)
+	require.EqualValues(t, 3*testMiningBase/4, report.Users[0].BalanceT1)
+	require.EqualValues(t, 3*testMiningBase, report.Users[1].BalanceSolo)
+	require.EqualValues(t, 3*testMiningBase/4, report.Users[1].BalanceT0)
+	require.EqualValues(t, 1, report.Users[0].ActiveT1Referrals)
+	require.Zero(t, report.Users[0].BalanceTotalSlashed)
+	require.EqualValues(t, 3*(testMiningBase+testMiningBase/4), report.Users[0].BalanceTotalMinted)
+}
@@ -0,0 +1,225 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package tokenomics
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-json"
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+	"github.com/redis/go-redis/v9"
+
+	messagebroker "github.com/ice-blockchain/wintr/connectors/message_broker"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+//nolint:funlen,gocognit,revive // .
+func (r *repository) StreamBalance(ctx context.Context, userID, lastEventID string, send func(*BalanceStreamEvent) error) error {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.BalanceStream.MaxConnectionDuration)
+	defer cancel()
+	key, cursor := balanceStreamKey(userID), "0-0"
+	// Before reading the cursor, so that nothing is missed between it and the first read.
+	if err := r.refreshBalanceStreamSubscriber(ctx, userID); err != nil {
+		return err
+	}
+	lastRefreshedAt := time.Now()
+	if lastEventID != "" {
+		missed, err := r.db.XRange(ctx, key, "("+lastEventID, "+").Result()
+		if err != nil {
+			return errors.Wrapf(err, "failed to get the balance stream events of userID:%v after %v", userID, lastEventID)
+		}
+		cursor = lastEventID
+		for ix := range missed {
+			if err = send(balanceStreamEvent(&missed[ix])); err != nil {
+				return errors.Wrapf(err, "failed to send the missed balance stream event %v of userID:%v", missed[ix].ID, userID)
+			}
+			cursor = missed[ix].ID
+		}
+	} else if latest, err := r.db.XRevRangeN(ctx, key, "+", "-", 1).Result(); err != nil {
+		return errors.Wrapf(err, "failed to get the latest balance stream event of userID:%v", userID)
+	} else if len(latest) == 1 {
+		cursor = latest[0].ID
+	}
+	conditions, currentAdoption, err := r.getMiningConditions(ctx, userID)
+	if err != nil {
+		return errors.Wrapf(err, "failed to getMiningConditions for userID:%v", userID)
+	}
+	if err = send(&BalanceStreamEvent{Type: SnapshotBalanceStreamEventType, Data: r.balanceStreamState(conditions, currentAdoption)}); err != nil {
+		return errors.Wrapf(err, "failed to send the balance stream snapshot of userID:%v", userID)
+	}
+	for ctx.Err() == nil {
+		if now := time.Now(); now.Sub(*lastRefreshedAt.Time) >= r.cfg.BalanceStream.SubscriberTTL/2 { //nolint:gomnd // Half of it, so it never expires while connected.
+			if err = r.refreshBalanceStreamSubscriber(ctx, userID); err != nil {
+				if ctx.Err() != nil {
+					break
+				}
+
+				return err
+			}
+			lastRefreshedAt = now
+		}
+		streams, xErr := r.db.XRead(ctx, &redis.XReadArgs{Streams: []string{key, cursor}, Block: r.cfg.BalanceStream.InterpolationInterval}).Result()
+		if xErr != nil && errors.Is(xErr, redis.Nil) {
+			now := time.Now()
+			xErr = send(&BalanceStreamEvent{Type: BalanceBalanceStreamEventType, Data: &BalanceStreamState{
+				At:           now,
+				Balance:      fmt.Sprintf(floatToStringFormatter, roundFloat64(r.projectBalance(conditions, now))),
+				Interpolated: true,
+			}})
+			if xErr != nil {
+				return errors.Wrapf(xErr, "failed to send the interpolated balance of userID:%v", userID)
+			}
+
+			continue
+		}
+		if xErr != nil {
+			if ctx.Err() != nil {
+				break
+			}
+
+			return errors.Wrapf(xErr, "failed to read the balance stream of userID:%v after %v", userID, cursor)
+		}
+		for _, stream := range streams {
+			for ix := range stream.Messages {
+				event := balanceStreamEvent(&stream.Messages[ix])
+				if err = send(event); err != nil {
+					return errors.Wrapf(err, "failed to send the balance stream event %v of userID:%v", event.ID, userID)
+				}
+				cursor = event.ID
+			}
+		}
+		// Interpolates from the freshest state, the event having been produced after the miner or the user changed it.
+		if conditions, _, err = r.getMiningConditions(ctx, userID); err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+
+			return errors.Wrapf(err, "failed to getMiningConditions for userID:%v", userID)
+		}
+	}
+
+	return nil
+}
+
+func (r *repository) balanceStreamState(conditions *miningConditions, currentAdoption *Adoption[float64]) *BalanceStreamState {
+	breakdown := r.explainMiningRate(conditions)
+	breakdown.AdoptionMilestone = currentAdoption.Milestone
+
+	return &BalanceStreamState{
+		At:                   conditions.now,
+		MiningSessionEndedAt: conditions.miningSessionEndedAt,
+		MiningRate:           breakdown,
+		Balance:              fmt.Sprintf(floatToStringFormatter, roundFloat64(r.projectBalance(conditions, conditions.now))),
+	}
+}
+
+// refreshBalanceStreamSubscriber marks the user as subscribed to its balance stream for the next `subscriberTTL`,
+// which also covers the reconnections that resume from the last event they got.
+func (r *repository) refreshBalanceStreamSubscriber(ctx context.Context, userID string) error {
+	return errors.Wrapf(r.db.Set(ctx, balanceStreamSubscriberKey(userID), "", r.cfg.BalanceStream.SubscriberTTL).Err(),
+		"failed to refresh the balance stream subscriber userID:%v", userID)
+}
+
+// appendToBalanceStream stores the event in the user's stream, where the connections of the user, on any instance, read it from.
+// It does nothing if the user is not subscribed to it.
+func (r *repository) appendToBalanceStream(ctx context.Context, userID string, eventType BalanceStreamEventType, balance *float64) error {
+	if subscribed, err := r.db.Exists(ctx, balanceStreamSubscriberKey(userID)).Result(); err != nil || subscribed == 0 {
+		return errors.Wrapf(err, "failed to check if userID:%v is subscribed to its balance stream", userID)
+	}
+	conditions, currentAdoption, err := r.getMiningConditions(ctx, userID)
+	if err != nil {
+		return errors.Wrapf(err, "failed to getMiningConditions for userID:%v", userID)
+	}
+	state := r.balanceStreamState(conditions, currentAdoption)
+	if balance != nil {
+		state.Balance = fmt.Sprintf(floatToStringFormatter, roundFloat64(*balance))
+	}
+	data, err := json.MarshalContext(ctx, state)
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal %#v", state)
+	}
+	key := balanceStreamKey(userID)
+	results, err := r.db.TxPipelined(ctx, func(pipeliner redis.Pipeliner) error {
+		if err = pipeliner.XAdd(ctx, &redis.XAddArgs{
+			Stream: key,
+			MaxLen: r.cfg.BalanceStream.MaxLen,
+			Approx: true,
+			Values: []any{"type", string(eventType), "data", string(data)},
+		}).Err(); err != nil {
+			return err
+		}
+
+		return pipeliner.Expire(ctx, key, r.cfg.BalanceStream.Retention).Err()
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to append the %v event to the balance stream of userID:%v", eventType, userID)
+	}
+	errs := make([]error, 0, len(results))
+	for _, result := range results {
+		if err = result.Err(); err != nil {
+			errs = append(errs, errors.Wrapf(err, "failed to run `%#v`", result.FullName()))
+		}
+	}
+
+	return errors.Wrapf(multierror.Append(nil, errs...).ErrorOrNil(), "failed to append the %v event to the balance stream of userID:%v", eventType, userID)
+}
+
+func (s *balanceStreamMiningSessionsSource) Process(ctx context.Context, msg *messagebroker.Message) error {
+	if ctx.Err() != nil || len(msg.Value) == 0 {
+		return errors.Wrap(ctx.Err(), "unexpected deadline while processing message")
+	}
+	ms := new(MiningSession)
+	if err := json.UnmarshalContext(ctx, msg.Value, ms); err != nil || ms.UserID == nil {
+		return errors.Wrapf(err, "process: cannot unmarshall %v into %#v", string(msg.Value), ms)
+	}
+
+	return errors.Wrapf(s.appendToBalanceStream(ctx, *ms.UserID, MiningSessionBalanceStreamEventType, nil),
+		"failed to appendToBalanceStream for %#v", ms)
+}
+
+func (s *balanceStreamBalancesSource) Process(ctx context.Context, msg *messagebroker.Message) error {
+	if ctx.Err() != nil || len(msg.Value) == 0 {
+		return errors.Wrap(ctx.Err(), "unexpected deadline while processing message")
+	}
+	bal := new(Balances[float64])
+	if err := json.UnmarshalContext(ctx, msg.Value, bal); err != nil || bal.UserID == "" {
+		return errors.Wrapf(err, "process: cannot unmarshall %v into %#v", string(msg.Value), bal)
+	}
+
+	// The balance synchronizer sends only the standard and pre-staking totals.
+	total := bal.Standard + bal.PreStaking
+
+	return errors.Wrapf(s.appendToBalanceStream(ctx, bal.UserID, BalanceBalanceStreamEventType, &total),
+		"failed to appendToBalanceStream for %#v", bal)
+}
+
+func balanceStreamEvent(msg *redis.XMessage) *BalanceStreamEvent {
+	eventType, _ := msg.Values["type"].(string) //nolint:errcheck // Not needed, it's what we XAdd.
+	data, _ := msg.Values["data"].(string)      //nolint:errcheck // Not needed, it's what we XAdd.
+
+	return &BalanceStreamEvent{ID: msg.ID, Type: BalanceStreamEventType(eventType), Data: json.RawMessage(data)}
+}
+
+func balanceStreamKey(userID string) string {
+	return "balance_stream:" + userID
+}
+
+func balanceStreamSubscriberKey(userID string) string {
+	return "balance_stream_subscriber:" + userID
+}
+
+// IsValidBalanceStreamEventID checks that the id is one of a balance stream event, I.E. `1697000000000-0`.
+func IsValidBalanceStreamEventID(id string) bool {
+	millis, seq, found := strings.Cut(id, "-")
+	if !found {
+		return false
+	}
+	_, err1 := strconv.ParseUint(millis, 10, 64)
+	_, err2 := strconv.ParseUint(seq, 10, 64)
+
+	return err1 == nil && err2 == nil
+}
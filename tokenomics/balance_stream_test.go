@@ -0,0 +1,37 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package tokenomics
+
+import (
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsValidBalanceStreamEventID(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, IsValidBalanceStreamEventID("1697000000000-0"))
+	assert.True(t, IsValidBalanceStreamEventID("0-1"))
+	assert.False(t, IsValidBalanceStreamEventID(""))
+	assert.False(t, IsValidBalanceStreamEventID("1697000000000"))
+	assert.False(t, IsValidBalanceStreamEventID("1697000000000-"))
+	assert.False(t, IsValidBalanceStreamEventID("+"))
+	assert.False(t, IsValidBalanceStreamEventID("-1-0"))
+}
+
+func TestBalanceStreamEvent(t *testing.T) {
+	t.Parallel()
+
+	event := balanceStreamEvent(&redis.XMessage{
+		ID:     "1697000000000-0",
+		Values: map[string]any{"type": string(MiningSessionBalanceStreamEventType), "data": `{"balance":"10.00"}`},
+	})
+	assert.Equal(t, "1697000000000-0", event.ID)
+	assert.Equal(t, MiningSessionBalanceStreamEventType, event.Type)
+	data, err := json.Marshal(event.Data)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"balance":"10.00"}`, string(data))
+}
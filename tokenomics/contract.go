@@ -32,6 +32,24 @@ const (
 	NoneMiningRateType     MiningRateType = "none"
 )
 
+const (
+	T1ReferralTier ReferralTier = "t1"
+	T2ReferralTier ReferralTier = "t2"
+)
+
+const (
+	BalanceTopMinersDimension      TopMinersDimension = "balance"
+	CountryTopMinersDimension      TopMinersDimension = "country"
+	ReferralsTopMinersDimension    TopMinersDimension = "referrals"
+	MiningStreakTopMinersDimension TopMinersDimension = "miningStreak"
+)
+
+const (
+	SnapshotBalanceStreamEventType      BalanceStreamEventType = "snapshot"
+	BalanceBalanceStreamEventType       BalanceStreamEventType = "balance"
+	MiningSessionBalanceStreamEventType BalanceStreamEventType = "miningSession"
+)
+
 var (
 	ErrNotFound                                        = errors.New("not found")
 	ErrRelationNotFound                                = errors.New("relationship not found")
@@ -62,13 +80,46 @@ var (
 
 type (
 	MiningRateType string
-	Miner          struct {
+	ReferralTier   string
+	// TopMinersDimension is what the leaderboard ranks by: the total balance, globally or within a country,
+	// the number of active T1 referrals or the mining streak.
+	TopMinersDimension string
+	// BalanceStreamEventType is `snapshot` when the stream starts, `balance` for the balance updates, interpolated or not,
+	// and `miningSession` when a mining session starts or is extended.
+	BalanceStreamEventType string
+	BalanceStreamEvent     struct {
+		Data any                    `json:"data"`
+		ID   string                 `json:"id,omitempty" example:"1697000000000-0"`
+		Type BalanceStreamEventType `json:"type" example:"balance"`
+	}
+	BalanceStreamState struct {
+		At                   *time.Time           `json:"at" example:"2022-01-03T16:20:52.156534Z"`
+		MiningSessionEndedAt *time.Time           `json:"miningSessionEndedAt,omitempty" example:"2022-01-03T16:20:52.156534Z"`
+		MiningRate           *MiningRateBreakdown `json:"miningRate,omitempty"`
+		Balance              string               `json:"balance" example:"1,243.02"`
+		Interpolated         bool                 `json:"interpolated,omitempty" example:"true"`
+	}
+	Miner struct {
 		Balance           string `json:"balance,omitempty" example:"12345.6334"`
 		UserID            string `json:"userId,omitempty" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"`
 		Username          string `json:"username,omitempty" example:"jdoe"`
 		ProfilePictureURL string `json:"profilePictureUrl,omitempty" example:"https://somecdn.com/p1.jpg"`
+		ActiveT1Referrals int32  `json:"activeT1Referrals,omitempty" example:"11"`
+		MiningStreak      uint64 `json:"miningStreak,omitempty" example:"7"`
 		balance           float64
 	}
+	Referral struct {
+		UserID            string `json:"userId,omitempty" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"`
+		Username          string `json:"username,omitempty" example:"jdoe"`
+		ProfilePictureURL string `json:"profilePictureUrl,omitempty" example:"https://somecdn.com/p1.jpg"`
+		Contribution      string `json:"contribution" example:"1,243.02"`
+		Active            bool   `json:"active" example:"true"`
+		Slashing          bool   `json:"slashing" example:"false"`
+	}
+	Referrals struct {
+		Referrals []*Referral `json:"referrals"`
+		Total     uint64      `json:"total" example:"11"`
+	}
 	BalanceSummary struct {
 		Balances[string]
 	}
@@ -97,6 +148,13 @@ type (
 		Balance    *BalanceHistoryBalanceDiff `json:"balance"`
 		TimeSeries []*BalanceHistoryEntry     `json:"timeSeries"`
 	}
+	RankHistoryEntry struct {
+		Time       stdlibtime.Time     `json:"time" swaggertype:"string" example:"2022-01-03T16:20:52.156534Z"`
+		TimeSeries []*RankHistoryEntry `json:"timeSeries"`
+		GlobalRank uint64              `json:"globalRank" example:"12333"`
+		// How many places the user climbed since the previous entry, negative if it dropped.
+		Change int64 `json:"change" example:"-3"`
+	}
 	TotalCoins struct {
 		Total      float64 `json:"total" example:"111111.2423"`
 		Blockchain float64 `json:"blockchain" example:"111111.2423"`
@@ -176,6 +234,39 @@ type (
 		Extension                     stdlibtime.Duration `json:"extension,omitempty" swaggerignore:"true" example:"24h"`
 		MiningStreak                  uint64              `json:"miningStreak,omitempty" swaggerignore:"true" example:"11"`
 	}
+	MiningRateExplanation struct {
+		Breakdown                       *MiningRateBreakdown `json:"breakdown"`
+		MiningSessionEndedAt            *time.Time           `json:"miningSessionEndedAt,omitempty" example:"2022-01-03T16:20:52.156534Z"`
+		NextAdoptionMilestone           *Adoption[string]    `json:"nextAdoptionMilestone,omitempty"`
+		NextAdoptionMilestoneEarliestAt *time.Time           `json:"nextAdoptionMilestoneEarliestAt,omitempty" example:"2022-01-03T16:20:52.156534Z"`
+		Projections                     []*BalanceProjection `json:"projections"`
+	}
+	// MiningRateBreakdown amounts are per `GlobalAggregationInterval.Child`, before the pre-staking multiplier,
+	// except for Total, which is what the miner applies, including it.
+	MiningRateBreakdown struct {
+		ExtraBonusEndsAt     *time.Time     `json:"extraBonusEndsAt,omitempty" example:"2022-01-03T16:20:52.156534Z"`
+		Base                 string         `json:"base" example:"16.00"`
+		ExtraBonus           string         `json:"extraBonus" example:"1.60"`
+		T0                   string         `json:"t0" example:"4.00"`
+		T1                   string         `json:"t1" example:"20.00"`
+		T2                   string         `json:"t2" example:"5.00"`
+		Slashing             string         `json:"slashing" example:"0.00"`
+		Total                string         `json:"total" example:"46.60"`
+		Type                 MiningRateType `json:"type" example:"positive"`
+		ExtraBonusPercentage float64        `json:"extraBonusPercentage" example:"10"`
+		PreStakingAllocation float64        `json:"preStakingAllocation" example:"50"`
+		PreStakingBonus      float64        `json:"preStakingBonus" example:"70"`
+		PreStakingMultiplier float64        `json:"preStakingMultiplier" example:"1.35"`
+		AdoptionMilestone    uint64         `json:"adoptionMilestone" example:"1"`
+		ActiveT1Referrals    uint32         `json:"activeT1Referrals" example:"2"`
+		ActiveT2Referrals    uint32         `json:"activeT2Referrals" example:"5"`
+		T0Active             bool           `json:"t0Active" example:"true"`
+	}
+	BalanceProjection struct {
+		At      *time.Time `json:"at" example:"2022-01-03T16:20:52.156534Z"`
+		Horizon string     `json:"horizon" example:"24h"`
+		Balance string     `json:"balance" example:"1,243.02"`
+	}
 	ExtraBonusSummary struct {
 		UserID              string  `json:"userId,omitempty" swaggerignore:"true" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"`
 		AvailableExtraBonus float64 `json:"availableExtraBonus,omitempty" example:"2.00"`
@@ -187,11 +278,17 @@ type (
 		GetBalanceSummary(ctx context.Context, userID string) (*BalanceSummary, error)
 		GetTotalCoinsSummary(ctx context.Context, days uint64, utcOffset stdlibtime.Duration) (*TotalCoinsSummary, error)
 		GetRankingSummary(ctx context.Context, userID string) (*RankingSummary, error)
-		GetTopMiners(ctx context.Context, keyword string, limit, offset uint64) (topMiners []*Miner, nextOffset uint64, err error)
+		GetTopMiners(ctx context.Context, dimension TopMinersDimension, country, keyword string, limit, offset uint64) (topMiners []*Miner, nextOffset uint64, err error) //nolint:lll // .
+		GetReferrals(ctx context.Context, userID string, tier ReferralTier, limit, offset uint64) (referrals *Referrals, nextOffset uint64, err error)
 		GetMiningSummary(ctx context.Context, userID string) (*MiningSummary, error)
+		GetMiningRateExplanation(ctx context.Context, userID string) (*MiningRateExplanation, error)
 		GetPreStakingSummary(ctx context.Context, userID string) (*PreStakingSummary, error)
 		GetBalanceHistory(ctx context.Context, userID string, start, end *time.Time, utcOffset stdlibtime.Duration, limit, offset uint64) ([]*BalanceHistoryEntry, error) //nolint:lll // .
+		GetRankHistory(ctx context.Context, userID string, start, end *time.Time, utcOffset stdlibtime.Duration, limit, offset uint64) ([]*RankHistoryEntry, error)       //nolint:lll // .
 		GetAdoptionSummary(context.Context) (*AdoptionSummary, error)
+		// StreamBalance sends the balance and mining state updates of the user until the context ends or `send` fails.
+		// The events missed since lastEventID, if any, are sent first.
+		StreamBalance(ctx context.Context, userID, lastEventID string, send func(*BalanceStreamEvent) error) error
 	}
 	WriteRepository interface {
 		StartNewMiningSession(ctx context.Context, ms *MiningSummary, rollbackNegativeMiningProgress *bool, skipKYCSteps []users.KYCStep) error
@@ -214,6 +311,7 @@ type (
 
 const (
 	applicationYamlKey                  = "tokenomics"
+	balanceStreamApplicationYamlKey     = "tokenomics_balance_stream"
 	dayFormat, hourFormat, minuteFormat = "2006-01-02", "2006-01-02T15", "2006-01-02T15:04"
 	totalActiveUsersGlobalKey           = "TOTAL_ACTIVE_USERS"
 	requestingUserIDCtxValueKey         = "requestingUserIDCtxValueKey"
@@ -252,6 +350,14 @@ type (
 		*processor
 	}
 
+	balanceStreamMiningSessionsSource struct {
+		*repository
+	}
+
+	balanceStreamBalancesSource struct {
+		*repository
+	}
+
 	repository struct {
 		cfg                               *Config
 		extraBonusStartDate               *time.Time
@@ -363,6 +469,13 @@ type (
 			Min uint64 `yaml:"min"`
 			Max uint64 `yaml:"max"`
 		} `yaml:"consecutiveNaturalMiningSessionsRequiredFor1ExtraFreeArtificialMiningSession"`
+		BalanceStream struct {
+			InterpolationInterval stdlibtime.Duration `yaml:"interpolationInterval"`
+			MaxConnectionDuration stdlibtime.Duration `yaml:"maxConnectionDuration"`
+			Retention             stdlibtime.Duration `yaml:"retention"`
+			SubscriberTTL         stdlibtime.Duration `yaml:"subscriberTTL"`
+			MaxLen                int64               `yaml:"maxLen"`
+		} `yaml:"balanceStream"`
 		GlobalAggregationInterval struct {
 			Parent stdlibtime.Duration `yaml:"parent"`
 			Child  stdlibtime.Duration `yaml:"child"`
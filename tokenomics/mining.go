@@ -47,17 +47,26 @@ func (r *repository) GetRankingSummary(ctx context.Context, userID string) (*Ran
 			return nil, errors.Wrapf(err, "failed to set cached global_rank for id:%v", id)
 		}
 	}
+	if err = r.checkGlobalRankNotHidden(ctx, userID, id); err != nil {
+		return nil, err
+	}
+
+	return &RankingSummary{GlobalRank: rank}, nil
+}
+
+// checkGlobalRankNotHidden returns ErrGlobalRankHidden if the user hides its ranking from the others.
+func (r *repository) checkGlobalRankNotHidden(ctx context.Context, userID string, id int64) error {
 	if userID != requestingUserID(ctx) {
 		if usr, gErr := storage.Get[struct{ model.HideRankingField }](ctx, r.db, model.SerializedUsersKey(id)); gErr != nil || (len(usr) == 1 && usr[0].HideRanking) {
 			if gErr == nil {
 				gErr = ErrGlobalRankHidden
 			}
 
-			return nil, errors.Wrapf(gErr, "failed to get hide_ranking for id:%v", id)
+			return errors.Wrapf(gErr, "failed to get hide_ranking for id:%v", id)
 		}
 	}
 
-	return &RankingSummary{GlobalRank: rank}, nil
+	return nil
 }
 
 const (
@@ -66,8 +75,10 @@ const (
 
 var everythingNotAllowedInUsernamePattern = regexp.MustCompile(everythingNotAllowedInUsernameRegex)
 
-//nolint:funlen // .
-func (r *repository) GetTopMiners(ctx context.Context, keyword string, limit, offset uint64) (topMiners []*Miner, nextOffset uint64, err error) {
+//nolint:funlen,gocognit,gocyclo,revive,cyclop // .
+func (r *repository) GetTopMiners(
+	ctx context.Context, dimension TopMinersDimension, country, keyword string, limit, offset uint64,
+) (topMiners []*Miner, nextOffset uint64, err error) {
 	var (
 		ids           []string
 		sortTopMiners func(int, int) bool
@@ -76,10 +87,18 @@ func (r *repository) GetTopMiners(ctx context.Context, keyword string, limit, of
 	topMiners = make([]*Miner, 0)
 	for len(topMiners) < int(limit) && nextOffset != 0 {
 		if keyword == "" {
-			sortTopMiners = func(ii, jj int) bool { return topMiners[ii].balance > topMiners[jj].balance }
+			switch dimension {
+			case ReferralsTopMinersDimension:
+				sortTopMiners = func(ii, jj int) bool { return topMiners[ii].ActiveT1Referrals > topMiners[jj].ActiveT1Referrals }
+			case MiningStreakTopMinersDimension:
+				sortTopMiners = func(ii, jj int) bool { return topMiners[ii].MiningStreak > topMiners[jj].MiningStreak }
+			default:
+				sortTopMiners = func(ii, jj int) bool { return topMiners[ii].balance > topMiners[jj].balance }
+			}
+			key := TopMinersKey(dimension, country)
 			rangeBy := &redis.ZRangeBy{Min: "0", Max: "+inf", Offset: int64(offset), Count: int64(limit)}
-			if ids, err = r.db.ZRevRangeByScore(ctx, "top_miners", rangeBy).Result(); err != nil {
-				return nil, 0, errors.Wrapf(err, "failed to ZRevRangeByScore for miners for offset:%v,limit:%v", offset, limit)
+			if ids, err = r.db.ZRevRangeByScore(ctx, key, rangeBy).Result(); err != nil {
+				return nil, 0, errors.Wrapf(err, "failed to ZRevRangeByScore %v for miners for offset:%v,limit:%v", key, offset, limit)
 			}
 			if len(ids) > 0 {
 				nextOffset = offset + limit
@@ -108,24 +127,33 @@ func (r *repository) GetTopMiners(ctx context.Context, keyword string, limit, of
 			break
 		}
 		resp, err := storage.Get[struct {
+			model.MiningSessionSoloStartedAtField
+			model.MiningSessionSoloEndedAtField
 			model.UserIDField
 			model.LatestDeviceField
 			model.UsernameField
 			model.ProfilePictureNameField
+			model.CountryField
 			model.BalanceTotalStandardField
 			model.BalanceTotalPreStakingField
 			model.BalanceT2Field
 			model.PreStakingAllocationField
 			model.PreStakingBonusField
+			model.ActiveT1ReferralsField
 			model.HideRankingField
 		}](ctx, r.db, ids...)
 		if err != nil {
 			return nil, 0, errors.Wrapf(err, "failed to get miners for ids:%#v", ids)
 		}
+		now := time.Now()
 		for _, topMiner := range resp {
 			if topMiner.HideRanking {
 				continue
 			}
+			// The user might have moved to another country since the leaderboard of the previous one was updated.
+			if keyword == "" && dimension == CountryTopMinersDimension && !strings.EqualFold(topMiner.Country, country) {
+				continue
+			}
 			if r.isAdvancedTeamDisabled(topMiner.LatestDevice) {
 				t2Standard, t2PreStaking := ApplyPreStaking(topMiner.BalanceT2, topMiner.PreStakingAllocation, topMiner.PreStakingBonus)
 				topMiner.BalanceTotalStandard -= t2Standard
@@ -135,13 +163,21 @@ func (r *repository) GetTopMiners(ctx context.Context, keyword string, limit, of
 			if total < 0 {
 				total = 0
 			}
-			topMiners = append(topMiners, &Miner{
+			miner := &Miner{
 				Balance:           fmt.Sprintf(floatToStringFormatter, total),
 				balance:           total,
 				UserID:            topMiner.UserID,
 				Username:          topMiner.Username,
 				ProfilePictureURL: r.pictureClient.DownloadURL(topMiner.ProfilePictureName),
-			})
+			}
+			switch {
+			case keyword != "":
+			case dimension == ReferralsTopMinersDimension && topMiner.ActiveT1Referrals > 0:
+				miner.ActiveT1Referrals = topMiner.ActiveT1Referrals
+			case dimension == MiningStreakTopMinersDimension:
+				miner.MiningStreak = r.calculateMiningStreak(now, topMiner.MiningSessionSoloStartedAt, topMiner.MiningSessionSoloEndedAt)
+			}
+			topMiners = append(topMiners, miner)
 		}
 		offset = nextOffset
 	}
@@ -158,6 +194,20 @@ func (r *repository) GetTopMiners(ctx context.Context, keyword string, limit, of
 	return topMiners, nextOffset, nil
 }
 
+// TopMinersKey is the sorted set of the leaderboard, `top_miners` being the one by total balance, I.E. the global rank.
+func TopMinersKey(dimension TopMinersDimension, country string) string {
+	switch dimension {
+	case CountryTopMinersDimension:
+		return "top_miners_by_country:" + strings.ToLower(country)
+	case ReferralsTopMinersDimension:
+		return "top_referrers"
+	case MiningStreakTopMinersDimension:
+		return "top_mining_streaks"
+	default:
+		return "top_miners"
+	}
+}
+
 //nolint:funlen // .
 func (r *repository) GetMiningSummary(ctx context.Context, userID string) (*MiningSummary, error) {
 	id, err := GetOrInitInternalID(ctx, r.db, userID)
@@ -0,0 +1,220 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package tokenomics
+
+import (
+	"context"
+	"fmt"
+	stdlibtime "time"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/freezer/model"
+	"github.com/ice-blockchain/wintr/connectors/storage/v3"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+type (
+	// miningConditions are the current inputs of the miner for a user, the rates being per `GlobalAggregationInterval.Child`.
+	miningConditions struct {
+		now, balanceUpdatedAt, miningSessionEndedAt, extraBonusEndsAt                        *time.Time
+		balance, baseMiningRate, extraBonus, preStakingAllocation, preStakingBonus, slashing float64
+		t1, t2                                                                               uint32
+		t0                                                                                   uint16
+	}
+)
+
+const (
+	// The miner slashes the balance left when the mining session ended over 60 `GlobalAggregationInterval.Parent`s.
+	slashingParentIntervals = 60
+)
+
+//nolint:gochecknoglobals // .
+var balanceProjectionHorizons = []struct {
+	name     string
+	duration stdlibtime.Duration
+}{
+	{name: "24h", duration: 24 * stdlibtime.Hour},
+	{name: "7d", duration: 7 * 24 * stdlibtime.Hour},
+	{name: "30d", duration: 30 * 24 * stdlibtime.Hour},
+}
+
+func (r *repository) GetMiningRateExplanation(ctx context.Context, userID string) (*MiningRateExplanation, error) {
+	conditions, currentAdoption, err := r.getMiningConditions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	nextAdoption, err := getAdoption(ctx, r.db, currentAdoption.Milestone+1)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, errors.Wrapf(err, "failed to getAdoption for milestone:%v", currentAdoption.Milestone+1)
+	}
+	explanation := &MiningRateExplanation{
+		Breakdown:            r.explainMiningRate(conditions),
+		Projections:          make([]*BalanceProjection, 0, len(balanceProjectionHorizons)),
+		MiningSessionEndedAt: conditions.miningSessionEndedAt,
+	}
+	explanation.Breakdown.AdoptionMilestone = currentAdoption.Milestone
+	for _, horizon := range balanceProjectionHorizons {
+		at := time.New(conditions.now.Add(horizon.duration))
+		explanation.Projections = append(explanation.Projections, &BalanceProjection{
+			Horizon: horizon.name,
+			At:      at,
+			Balance: fmt.Sprintf(floatToStringFormatter, roundFloat64(r.projectBalance(conditions, at))),
+		})
+	}
+	if nextAdoption != nil {
+		explanation.NextAdoptionMilestone = &Adoption[string]{
+			BaseMiningRate:   fmt.Sprintf(floatToStringFormatter, nextAdoption.BaseMiningRate),
+			Milestone:        nextAdoption.Milestone,
+			TotalActiveUsers: nextAdoption.TotalActiveUsers,
+		}
+		explanation.NextAdoptionMilestoneEarliestAt = time.New(currentAdoption.AchievedAt.Add(
+			stdlibtime.Duration(r.cfg.AdoptionMilestoneSwitch.ConsecutiveDurationsRequired) * r.cfg.AdoptionMilestoneSwitch.Duration))
+	}
+
+	return explanation, nil
+}
+
+//nolint:funlen // .
+func (r *repository) getMiningConditions(ctx context.Context, userID string) (*miningConditions, *Adoption[float64], error) {
+	id, err := GetOrInitInternalID(ctx, r.db, userID)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to getOrInitInternalID for userID:%v", userID)
+	}
+	now := time.Now()
+	usr, err := storage.Get[struct {
+		model.MiningSessionSoloEndedAtField
+		model.ExtraBonusStartedAtField
+		model.BalanceLastUpdatedAtField
+		model.LatestDeviceField
+		model.BalanceTotalStandardField
+		model.BalanceTotalPreStakingField
+		model.SlashingRateSoloField
+		model.SlashingRateT0Field
+		model.SlashingRateT1Field
+		model.SlashingRateT2Field
+		model.PreStakingBonusField
+		model.PreStakingAllocationField
+		model.ExtraBonusField
+		model.IDT0Field
+		model.ActiveT1ReferralsField
+		model.ActiveT2ReferralsField
+	}](ctx, r.db, model.SerializedUsersKey(id))
+	if err != nil || len(usr) == 0 {
+		if err == nil {
+			err = errors.Wrapf(ErrRelationNotFound, "missing state for id:%v", id)
+		}
+
+		return nil, nil, errors.Wrapf(err, "failed to get the mining state for id:%v", id)
+	}
+	currentAdoption, err := GetCurrentAdoption(ctx, r.db)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to getCurrentAdoption")
+	}
+	t0, err := r.isT0Online(ctx, usr[0].IDT0, now)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to check if t0 is online for idT0:%v", usr[0].IDT0)
+	}
+	conditions := &miningConditions{
+		now:                  now,
+		balanceUpdatedAt:     usr[0].BalanceLastUpdatedAt,
+		miningSessionEndedAt: usr[0].MiningSessionSoloEndedAt,
+		balance:              usr[0].BalanceTotalStandard + usr[0].BalanceTotalPreStaking,
+		baseMiningRate:       currentAdoption.BaseMiningRate,
+		preStakingAllocation: usr[0].PreStakingAllocation,
+		preStakingBonus:      usr[0].PreStakingBonus,
+		slashing:             usr[0].SlashingRateSolo + usr[0].SlashingRateT0 + usr[0].SlashingRateT1 + usr[0].SlashingRateT2,
+		t0:                   t0,
+	}
+	if usr[0].ActiveT1Referrals > 0 {
+		conditions.t1 = uint32(usr[0].ActiveT1Referrals)
+	}
+	if usr[0].ActiveT2Referrals > 0 && r.isAdvancedTeamEnabled(usr[0].LatestDevice) {
+		conditions.t2 = uint32(usr[0].ActiveT2Referrals)
+	}
+	if !usr[0].ExtraBonusStartedAt.IsNil() && usr[0].ExtraBonusStartedAt.Add(r.cfg.ExtraBonuses.Duration).After(*now.Time) {
+		conditions.extraBonus = usr[0].ExtraBonus
+		conditions.extraBonusEndsAt = time.New(usr[0].ExtraBonusStartedAt.Add(r.cfg.ExtraBonuses.Duration))
+	}
+
+	return conditions, currentAdoption, nil
+}
+
+//nolint:gomnd // Percentages.
+func (r *repository) explainMiningRate(c *miningConditions) *MiningRateBreakdown {
+	breakdown := &MiningRateBreakdown{
+		Base:                 fmt.Sprintf(floatToStringFormatter, c.baseMiningRate),
+		ExtraBonusEndsAt:     c.extraBonusEndsAt,
+		ExtraBonusPercentage: c.extraBonus,
+		ActiveT1Referrals:    c.t1,
+		ActiveT2Referrals:    c.t2,
+		T0Active:             c.t0 == 1,
+		PreStakingAllocation: c.preStakingAllocation,
+		PreStakingBonus:      c.preStakingBonus,
+		PreStakingMultiplier: 1 + (c.preStakingAllocation*c.preStakingBonus)/(100*100),
+	}
+	var extraBonus, t0, t1, t2, slashing, total float64
+	switch {
+	case c.miningSessionEndedAt.IsNil():
+		breakdown.Type = NoneMiningRateType
+	case c.miningSessionEndedAt.After(*c.now.Time):
+		breakdown.Type = PositiveMiningRateType
+		extraBonus = c.baseMiningRate * c.extraBonus / 100
+		t0 = c.baseMiningRate * float64(c.t0*r.cfg.ReferralBonusMiningRates.T0) / 100
+		t1 = c.baseMiningRate * float64(c.t1*r.cfg.ReferralBonusMiningRates.T1) / 100
+		t2 = c.baseMiningRate * float64(c.t2*r.cfg.ReferralBonusMiningRates.T2) / 100
+		total = r.mintedCoins(c, c.extraBonus, r.cfg.GlobalAggregationInterval.Child)
+	case c.balance <= 0.0:
+		breakdown.Type = NoneMiningRateType
+	default:
+		breakdown.Type = NegativeMiningRateType
+		slashing = c.slashing
+		total = -slashing
+	}
+	breakdown.ExtraBonus = fmt.Sprintf(floatToStringFormatter, roundFloat64(extraBonus))
+	breakdown.T0 = fmt.Sprintf(floatToStringFormatter, roundFloat64(t0))
+	breakdown.T1 = fmt.Sprintf(floatToStringFormatter, roundFloat64(t1))
+	breakdown.T2 = fmt.Sprintf(floatToStringFormatter, roundFloat64(t2))
+	breakdown.Slashing = fmt.Sprintf(floatToStringFormatter, roundFloat64(slashing))
+	breakdown.Total = fmt.Sprintf(floatToStringFormatter, roundFloat64(total))
+
+	return breakdown
+}
+
+// projectBalance replays what `mine()` would do from the last balance update until `at` if nothing changes:
+// it mines until the session ends, the extra bonus expiring on the way, and slashes after that.
+func (r *repository) projectBalance(c *miningConditions, at *time.Time) float64 {
+	balance, from, slashing := c.balance, c.now, c.slashing
+	if !c.balanceUpdatedAt.IsNil() && c.balanceUpdatedAt.Before(*c.now.Time) {
+		from = c.balanceUpdatedAt
+	}
+	if !c.miningSessionEndedAt.IsNil() && c.miningSessionEndedAt.After(*from.Time) {
+		miningEndedAt := c.miningSessionEndedAt
+		if at.Before(*miningEndedAt.Time) {
+			miningEndedAt = at
+		}
+		if !c.extraBonusEndsAt.IsNil() && c.extraBonusEndsAt.Before(*miningEndedAt.Time) {
+			balance += r.mintedCoins(c, c.extraBonus, c.extraBonusEndsAt.Sub(*from.Time))
+			balance += r.mintedCoins(c, 0, miningEndedAt.Sub(*c.extraBonusEndsAt.Time))
+		} else {
+			balance += r.mintedCoins(c, c.extraBonus, miningEndedAt.Sub(*from.Time))
+		}
+		from, slashing = miningEndedAt, 0
+	}
+	if !at.After(*from.Time) || c.miningSessionEndedAt.IsNil() {
+		return balance
+	}
+	if slashing == 0 {
+		slashing = balance / slashingParentIntervals / float64(r.cfg.GlobalAggregationInterval.Parent/r.cfg.GlobalAggregationInterval.Child)
+	}
+	if balance -= slashing * float64(at.Sub(*from.Time)) / float64(r.cfg.GlobalAggregationInterval.Child); balance < 0 {
+		balance = 0
+	}
+
+	return balance
+}
+
+func (r *repository) mintedCoins(c *miningConditions, extraBonus float64, elapsed stdlibtime.Duration) float64 {
+	return r.calculateMintedStandardCoins(c.t0, extraBonus, c.preStakingAllocation, c.t1, c.t2, c.baseMiningRate, elapsed, false) +
+		r.calculateMintedPreStakingCoins(c.t0, extraBonus, c.preStakingAllocation, c.preStakingBonus, c.t1, c.t2, c.baseMiningRate, elapsed, false)
+}
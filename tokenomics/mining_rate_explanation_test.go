@@ -0,0 +1,62 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package tokenomics
+
+import (
+	"testing"
+	stdlibtime "time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ice-blockchain/wintr/time"
+)
+
+func TestRepositoryExplainMiningRateAndProjectBalance(t *testing.T) {
+	t.Parallel()
+	var cfg Config
+	cfg.GlobalAggregationInterval.Parent = 24 * stdlibtime.Hour
+	cfg.GlobalAggregationInterval.Child = stdlibtime.Hour
+	cfg.ReferralBonusMiningRates.T0 = 25
+	cfg.ReferralBonusMiningRates.T1 = 25
+	cfg.ReferralBonusMiningRates.T2 = 5
+	repo := &repository{cfg: &cfg}
+	now := time.Now()
+
+	mining := &miningConditions{
+		now:                  now,
+		miningSessionEndedAt: time.New(now.Add(12 * stdlibtime.Hour)),
+		balance:              100,
+		baseMiningRate:       16,
+		t1:                   2,
+	}
+	breakdown := repo.explainMiningRate(mining)
+	assert.Equal(t, PositiveMiningRateType, breakdown.Type)
+	assert.Equal(t, "8.00", breakdown.T1)
+	assert.Equal(t, "24.00", breakdown.Total)
+	assert.InDelta(t, 100+24*6, repo.projectBalance(mining, time.New(now.Add(6*stdlibtime.Hour))), 0.0001)
+	minedBalance := 100. + 24*12
+	assert.InDelta(t, minedBalance-(minedBalance/60/24)*12, repo.projectBalance(mining, time.New(now.Add(24*stdlibtime.Hour))), 0.0001)
+
+	mining.extraBonus, mining.extraBonusEndsAt = 100, time.New(now.Add(6*stdlibtime.Hour))
+	assert.Equal(t, "40.00", repo.explainMiningRate(mining).Total)
+	assert.InDelta(t, 100+40*6+24*6, repo.projectBalance(mining, time.New(now.Add(12*stdlibtime.Hour))), 0.0001)
+
+	mining.preStakingAllocation, mining.preStakingBonus = 100, 100
+	assert.InDelta(t, 2, repo.explainMiningRate(mining).PreStakingMultiplier, 0.0001)
+	assert.Equal(t, "80.00", repo.explainMiningRate(mining).Total)
+
+	slashing := &miningConditions{
+		now:                  now,
+		miningSessionEndedAt: time.New(now.Add(-stdlibtime.Hour)),
+		balance:              100,
+		baseMiningRate:       16,
+		slashing:             1,
+		t1:                   2,
+	}
+	breakdown = repo.explainMiningRate(slashing)
+	assert.Equal(t, NegativeMiningRateType, breakdown.Type)
+	assert.Equal(t, "0.00", breakdown.T1)
+	assert.Equal(t, "-1.00", breakdown.Total)
+	assert.InDelta(t, 76, repo.projectBalance(slashing, time.New(now.Add(24*stdlibtime.Hour))), 0.0001)
+	assert.Zero(t, repo.projectBalance(slashing, time.New(now.Add(30*24*stdlibtime.Hour))))
+}
@@ -37,3 +37,12 @@ func TestRepositoryCalculateMiningSession(t *testing.T) {
 	assert.EqualValues(t, time.New(start.Add(repo.cfg.MiningSessionDuration.Max)), actual.StartedAt)
 	assert.True(t, *actual.Free)
 }
+
+func TestTopMinersKey(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "top_miners", TopMinersKey(BalanceTopMinersDimension, "US"))
+	assert.Equal(t, "top_miners", TopMinersKey("", ""))
+	assert.Equal(t, "top_miners_by_country:us", TopMinersKey(CountryTopMinersDimension, "US"))
+	assert.Equal(t, "top_referrers", TopMinersKey(ReferralsTopMinersDimension, "US"))
+	assert.Equal(t, "top_mining_streaks", TopMinersKey(MiningStreakTopMinersDimension, ""))
+}
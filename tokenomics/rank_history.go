@@ -0,0 +1,95 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package tokenomics
+
+import (
+	"context"
+	"sort"
+	stdlibtime "time"
+
+	"github.com/pkg/errors"
+
+	dwh "github.com/ice-blockchain/freezer/bookkeeper/storage"
+	"github.com/ice-blockchain/wintr/log"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+func (r *repository) GetRankHistory(
+	ctx context.Context, userID string, start, end *time.Time, _ stdlibtime.Duration, limit, offset uint64,
+) ([]*RankHistoryEntry, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "unexpected deadline")
+	}
+	start, end = time.New(start.UTC()), time.New(end.UTC())
+	var factor stdlibtime.Duration
+	if start.After(*end.Time) {
+		factor = -1
+	} else {
+		factor = 1
+	}
+	dates, notBeforeTime, notAfterTime := r.calculateDates(limit, offset, start, end, factor)
+	id, err := GetOrInitInternalID(ctx, r.db, userID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to getOrInitInternalID for userID:%v", userID)
+	}
+	if err = r.checkGlobalRankNotHidden(ctx, userID, id); err != nil {
+		return nil, err
+	}
+	rankHistory, err := r.dwh.SelectRankHistory(ctx, id, dates)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to SelectRankHistory for id:%v,createdAts:%#v", id, dates)
+	}
+
+	return r.processRankHistory(rankHistory, factor > 0, notBeforeTime, notAfterTime), nil
+}
+
+// processRankHistory groups the ranks, ordered by their creation, by `GlobalAggregationInterval.Parent`,
+// the rank of a parent being the last one in it.
+//
+//nolint:funlen // .
+func (r *repository) processRankHistory(
+	res []*dwh.RankHistory, startDateIsBeforeEndDate bool, notBeforeTime, notAfterTime *time.Time,
+) []*RankHistoryEntry {
+	parentDateLayout := r.cfg.globalAggregationIntervalParentDateFormat()
+	history := make([]*RankHistoryEntry, 0, 1+1)
+	var (
+		parent                *RankHistoryEntry
+		prevChild, prevParent *RankHistoryEntry
+	)
+	for _, rank := range res {
+		child := &RankHistoryEntry{Time: *rank.CreatedAt.Time, GlobalRank: rank.GlobalRank, TimeSeries: make([]*RankHistoryEntry, 0, 0)}
+		if prevChild != nil {
+			child.Change = int64(prevChild.GlobalRank) - int64(child.GlobalRank)
+		}
+		prevChild = child
+		if rank.CreatedAt.Before(*notBeforeTime.Time) || rank.CreatedAt.After(*notAfterTime.Time) {
+			continue
+		}
+		parentTime, pErr := stdlibtime.ParseInLocation(parentDateLayout, rank.CreatedAt.Format(parentDateLayout), stdlibtime.UTC)
+		log.Panic(pErr) //nolint:revive // Intended.
+		if parent == nil || !parent.Time.Equal(parentTime) {
+			if parent != nil {
+				prevParent = parent
+			}
+			parent = &RankHistoryEntry{Time: parentTime, TimeSeries: make([]*RankHistoryEntry, 0, int(r.cfg.GlobalAggregationInterval.Parent/r.cfg.GlobalAggregationInterval.Child))}
+			history = append(history, parent)
+		}
+		parent.TimeSeries = append(parent.TimeSeries, child)
+		parent.GlobalRank = child.GlobalRank
+		if prevParent != nil {
+			parent.Change = int64(prevParent.GlobalRank) - int64(parent.GlobalRank)
+		}
+	}
+	if !startDateIsBeforeEndDate {
+		for _, entry := range history {
+			sort.SliceStable(entry.TimeSeries, func(i, j int) bool {
+				return entry.TimeSeries[i].Time.After(entry.TimeSeries[j].Time)
+			})
+		}
+		sort.SliceStable(history, func(i, j int) bool {
+			return history[i].Time.After(history[j].Time)
+		})
+	}
+
+	return history
+}
@@ -0,0 +1,51 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package tokenomics
+
+import (
+	"testing"
+	stdlibtime "time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	dwh "github.com/ice-blockchain/freezer/bookkeeper/storage"
+	appCfg "github.com/ice-blockchain/wintr/config"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+func TestProcessRankHistory(t *testing.T) {
+	t.Parallel()
+	var cfg Config
+	appCfg.MustLoadFromKey(applicationYamlKey, &cfg)
+	repo := &repository{cfg: &cfg}
+
+	parent := stdlibtime.Date(2023, 10, 1, 10, 0, 0, 0, stdlibtime.UTC)
+	history := []*dwh.RankHistory{
+		{CreatedAt: time.New(parent.Add(-2 * stdlibtime.Minute)), GlobalRank: 20},
+		{CreatedAt: time.New(parent.Add(-1 * stdlibtime.Minute)), GlobalRank: 15},
+		{CreatedAt: time.New(parent), GlobalRank: 12},
+		{CreatedAt: time.New(parent.Add(1 * stdlibtime.Minute)), GlobalRank: 14},
+	}
+	notBefore, notAfter := time.New(parent.Add(-1*stdlibtime.Minute)), time.New(parent.Add(stdlibtime.Hour))
+
+	entries := repo.processRankHistory(history, true, notBefore, notAfter)
+	require.Len(t, entries, 2)
+	assert.Equal(t, parent.Add(-stdlibtime.Hour), entries[0].Time)
+	assert.EqualValues(t, 15, entries[0].GlobalRank)
+	assert.EqualValues(t, 0, entries[0].Change)
+	require.Len(t, entries[0].TimeSeries, 1)
+	assert.EqualValues(t, 5, entries[0].TimeSeries[0].Change)
+	assert.Equal(t, parent, entries[1].Time)
+	assert.EqualValues(t, 14, entries[1].GlobalRank)
+	assert.EqualValues(t, 1, entries[1].Change)
+	require.Len(t, entries[1].TimeSeries, 2)
+	assert.EqualValues(t, 3, entries[1].TimeSeries[0].Change)
+	assert.EqualValues(t, -2, entries[1].TimeSeries[1].Change)
+
+	entries = repo.processRankHistory(history, false, notBefore, notAfter)
+	require.Len(t, entries, 2)
+	assert.Equal(t, parent, entries[0].Time)
+	assert.Equal(t, parent.Add(stdlibtime.Minute), entries[0].TimeSeries[0].Time)
+	assert.Equal(t, parent.Add(-stdlibtime.Hour), entries[1].Time)
+}
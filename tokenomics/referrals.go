@@ -0,0 +1,116 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package tokenomics
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/freezer/model"
+	"github.com/ice-blockchain/wintr/connectors/storage/v3"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+type (
+	indexedReferral struct {
+		model.UserIDField
+		model.UsernameField
+		model.ProfilePictureNameField
+		model.MiningSessionSoloEndedAtField
+		model.BalanceForT0Field
+		model.BalanceForTMinus1Field
+		model.SlashingRateForT0Field
+		model.SlashingRateForTMinus1Field
+		model.IDT0Field
+		model.IDTMinus1Field
+		model.DeserializedUsersKey
+	}
+)
+
+//nolint:funlen // .
+func (r *repository) GetReferrals(
+	ctx context.Context, userID string, tier ReferralTier, limit, offset uint64,
+) (referrals *Referrals, nextOffset uint64, err error) {
+	id, err := GetOrInitInternalID(ctx, r.db, userID)
+	if err != nil {
+		return nil, 0, errors.Wrapf(err, "failed to getOrInitInternalID for userID:%v", userID)
+	}
+	key := referralsKey(tier, id)
+	referrals = &Referrals{Referrals: make([]*Referral, 0, limit)}
+	now := time.Now()
+	// The stale referrals are removed from the index as we find them and we keep reading until the page is full, so that it matches `Total` and the next offset.
+	for start := offset; uint64(len(referrals.Referrals)) < limit; {
+		ids, zErr := r.db.ZRevRange(ctx, key, int64(start), int64(start+limit-uint64(len(referrals.Referrals)))-1).Result()
+		if zErr != nil {
+			return nil, 0, errors.Wrapf(zErr, "failed to get %v referrals of userID:%v for offset:%v,limit:%v", tier, userID, start, limit)
+		}
+		if len(ids) == 0 {
+			break
+		}
+		resp, gErr := storage.Get[indexedReferral](ctx, r.db, ids...)
+		if gErr != nil {
+			return nil, 0, errors.Wrapf(gErr, "failed to get %v referrals for ids:%#v", tier, ids)
+		}
+		valid, stale := splitStaleReferrals(tier, id, ids, resp)
+		if len(stale) > 0 {
+			if zErr = r.db.ZRem(ctx, key, stale...).Err(); zErr != nil {
+				return nil, 0, errors.Wrapf(zErr, "failed to remove the stale %v referrals %#v of userID:%v", tier, stale, userID)
+			}
+		}
+		start += uint64(len(valid)) // The stale ones are not in the index anymore.
+		for _, referral := range valid {
+			contribution, slashingRate := referral.BalanceForT0, referral.SlashingRateForT0
+			if tier == T2ReferralTier {
+				contribution, slashingRate = referral.BalanceForTMinus1, referral.SlashingRateForTMinus1
+			}
+			referrals.Referrals = append(referrals.Referrals, &Referral{
+				UserID:            referral.UserID,
+				Username:          referral.Username,
+				ProfilePictureURL: r.pictureClient.DownloadURL(referral.ProfilePictureName),
+				Contribution:      fmt.Sprintf(floatToStringFormatter, contribution),
+				Active:            !referral.MiningSessionSoloEndedAt.IsNil() && referral.MiningSessionSoloEndedAt.After(*now.Time),
+				Slashing:          slashingRate > 0,
+			})
+		}
+		if len(stale) == 0 {
+			break
+		}
+	}
+	total, err := r.db.ZCard(ctx, key).Result()
+	if err != nil {
+		return nil, 0, errors.Wrapf(err, "failed to count the %v referrals of userID:%v", tier, userID)
+	}
+	referrals.Total = uint64(total)
+	if next := offset + uint64(len(referrals.Referrals)); uint64(len(referrals.Referrals)) == limit && next < referrals.Total {
+		nextOffset = next
+	}
+
+	return referrals, nextOffset, nil
+}
+
+// splitStaleReferrals splits the referrals found in the index of the id in the ones still referred by it and the stale ones,
+// the ones that were deleted or moved to someone else since they were indexed.
+func splitStaleReferrals(tier ReferralTier, id int64, ids []string, resp []*indexedReferral) (valid []*indexedReferral, stale []any) {
+	valid = make([]*indexedReferral, 0, len(resp))
+	found := make(map[string]struct{}, len(resp))
+	for _, referral := range resp {
+		referredByID := referral.IDT0
+		if tier == T2ReferralTier {
+			referredByID = referral.IDTMinus1
+		}
+		if absInt64(referredByID) != id {
+			continue
+		}
+		found[model.SerializedUsersKey(referral.ID)] = struct{}{}
+		valid = append(valid, referral)
+	}
+	for _, key := range ids {
+		if _, ok := found[key]; !ok {
+			stale = append(stale, key)
+		}
+	}
+
+	return valid, stale
+}
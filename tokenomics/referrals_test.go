@@ -0,0 +1,37 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package tokenomics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ice-blockchain/freezer/model"
+)
+
+func TestSplitStaleReferrals(t *testing.T) {
+	t.Parallel()
+
+	referral := func(id, idT0, idTMinus1 int64) *indexedReferral {
+		ref := new(indexedReferral)
+		ref.ID, ref.IDT0, ref.IDTMinus1 = id, idT0, idTMinus1
+
+		return ref
+	}
+	key := func(id int64) string { return model.SerializedUsersKey(id) }
+	ids := []string{key(2), key(3), key(4), key(5)}
+	resp := []*indexedReferral{referral(2, 1, 10), referral(3, -1, 11), referral(5, 12, 1)} // 4 was deleted.
+
+	valid, stale := splitStaleReferrals(T1ReferralTier, 1, ids, resp)
+	assert.Equal(t, []*indexedReferral{resp[0], resp[1]}, valid)
+	assert.Equal(t, []any{key(4), key(5)}, stale)
+
+	valid, stale = splitStaleReferrals(T2ReferralTier, 1, ids, resp)
+	assert.Equal(t, []*indexedReferral{resp[2]}, valid)
+	assert.Equal(t, []any{key(2), key(3), key(4)}, stale)
+
+	valid, stale = splitStaleReferrals(T1ReferralTier, 1, nil, nil)
+	assert.Empty(t, valid)
+	assert.Empty(t, stale)
+}
@@ -26,7 +26,7 @@ import (
 	"github.com/ice-blockchain/wintr/time"
 )
 
-func New(ctx context.Context, _ context.CancelFunc) Repository {
+func New(ctx context.Context, cancel context.CancelFunc) Repository {
 	var cfg Config
 	appCfg.MustLoadFromKey(applicationYamlKey, &cfg)
 
@@ -36,13 +36,19 @@ func New(ctx context.Context, _ context.CancelFunc) Repository {
 		cfg:                           &cfg,
 		extraBonusStartDate:           extrabonusnotifier.MustGetExtraBonusStartDate(ctx, db),
 		extraBonusIndicesDistribution: extrabonusnotifier.MustGetExtraBonusIndicesDistribution(ctx, db),
-		shutdown: func() error {
-			return multierror.Append(db.Close(), dwhClient.Close()).ErrorOrNil()
-		},
 		db:            db,
 		dwh:           dwhClient,
 		pictureClient: picture.New(applicationYamlKey),
 	}
+	// The consumer group is shared, so every update is appended once to the balance stream, which all instances read from.
+	//nolint:contextcheck // It's intended. Cuz we want to close everything gracefully.
+	mbConsumer := messagebroker.MustConnectAndStartConsuming(context.Background(), cancel, balanceStreamApplicationYamlKey,
+		&balanceStreamMiningSessionsSource{repository: repo},
+		&balanceStreamBalancesSource{repository: repo},
+	)
+	repo.shutdown = func() error {
+		return multierror.Append(mbConsumer.Close(), db.Close(), dwhClient.Close()).ErrorOrNil()
+	}
 	go repo.startDisableAdvancedTeamCfgSyncer(ctx)
 	go repo.startBlockchainCoinStatsJSONSyncer(ctx)
 
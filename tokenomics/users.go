@@ -84,6 +84,7 @@ func (s *usersTableSource) deleteUser(ctx context.Context, usr *users.User) erro
 		model.BalanceForT0Field
 		model.BalanceForTMinus1Field
 		model.ActiveT1ReferralsField
+		model.CountryField
 	}](ctx, s.db, model.SerializedUsersKey(id))
 	if err != nil || len(dbUserAfterMiningStopped) == 0 {
 		if err == nil && len(dbUserAfterMiningStopped) == 0 {
@@ -139,6 +140,31 @@ func (s *usersTableSource) deleteUser(ctx context.Context, usr *users.User) erro
 		if err = pipeliner.ZRem(ctx, "top_miners", model.SerializedUsersKey(id)).Err(); err != nil {
 			return err
 		}
+		if err = pipeliner.SRem(ctx, "top_miners_announced", model.SerializedUsersKey(id)).Err(); err != nil {
+			return err
+		}
+		for _, key := range []string{
+			TopMinersKey(CountryTopMinersDimension, dbUserAfterMiningStopped[0].Country),
+			TopMinersKey(ReferralsTopMinersDimension, ""),
+			TopMinersKey(MiningStreakTopMinersDimension, ""),
+		} {
+			if err = pipeliner.ZRem(ctx, key, model.SerializedUsersKey(id)).Err(); err != nil {
+				return err
+			}
+		}
+		if idT0 := dbUserAfterMiningStopped[0].IDT0; idT0 != 0 {
+			if err = pipeliner.ZRem(ctx, referralsKey(T1ReferralTier, idT0), model.SerializedUsersKey(id)).Err(); err != nil {
+				return err
+			}
+		}
+		if idTMinus1 := dbUserAfterMiningStopped[0].IDTMinus1; idTMinus1 != 0 {
+			if err = pipeliner.ZRem(ctx, referralsKey(T2ReferralTier, idTMinus1), model.SerializedUsersKey(id)).Err(); err != nil {
+				return err
+			}
+		}
+		if err = pipeliner.Del(ctx, referralsKey(T1ReferralTier, id), referralsKey(T2ReferralTier, id)).Err(); err != nil {
+			return err
+		}
 		if err = pipeliner.Del(ctx, model.SerializedUsersKey(id), model.SerializedUsersKey(usr.ID)).Err(); err != nil {
 			return err
 		}
@@ -227,6 +253,10 @@ func (s *usersTableSource) replaceUser(ctx context.Context, usr *users.User) err
 		newPartialState.KYCStepPassed != dbUser[0].KYCStepPassed {
 		err = storage.Set(ctx, s.db, newPartialState)
 	}
+	if err == nil && dbUser[0].Country != "" && !strings.EqualFold(newPartialState.Country, dbUser[0].Country) {
+		// The miner adds the user to the leaderboard of the new country next time it updates its rank.
+		err = s.db.ZRem(ctx, TopMinersKey(CountryTopMinersDimension, dbUser[0].Country), model.SerializedUsersKey(internalID)).Err()
+	}
 
 	return multierror.Append( //nolint:wrapcheck // Not Needed.
 		errors.Wrapf(err, "failed to replace user:%#v", usr),
@@ -246,7 +276,14 @@ func (s *usersTableSource) updateReferredBy(ctx context.Context, id, oldIDT0, ol
 	if err != nil {
 		return errors.Wrapf(err, "failed to getOrInitInternalID for referredBy:%v", referredBy)
 	} else if (oldIDT0 == idT0) || (oldIDT0*-1 == idT0) {
-		return nil
+		// Backfills the referrals index for the users that were referred before it existed, once.
+		if err = s.db.ZScore(ctx, referralsKey(T1ReferralTier, absInt64(idT0)), model.SerializedUsersKey(id)).Err(); err == nil {
+			return nil
+		} else if !errors.Is(err, redis.Nil) {
+			return errors.Wrapf(err, "failed to check if id:%v is in the referrals index of idT0:%v", id, idT0)
+		}
+
+		return errors.Wrapf(s.updateReferralsIndex(ctx, id, 0, 0, idT0, oldTMinus1), "failed to updateReferralsIndex for id:%v", id)
 	}
 	type (
 		user struct {
@@ -308,7 +345,65 @@ func (s *usersTableSource) updateReferredBy(ctx context.Context, id, oldIDT0, ol
 		}
 	}
 
-	return errors.Wrapf(storage.Set(ctx, s.db, newPartialState), "failed to replace newPartialState:%#v", newPartialState)
+	if err = storage.Set(ctx, s.db, newPartialState); err != nil {
+		return errors.Wrapf(err, "failed to replace newPartialState:%#v", newPartialState)
+	}
+
+	return errors.Wrapf(s.updateReferralsIndex(ctx, id, oldIDT0, oldTMinus1, newPartialState.IDT0, newPartialState.IDTMinus1),
+		"failed to updateReferralsIndex for id:%v", id)
+}
+
+// updateReferralsIndex moves the user from the referrals index of its previous T0/T-1 to the one of its new T0/T-1.
+func (s *usersTableSource) updateReferralsIndex(ctx context.Context, id, oldIDT0, oldIDTMinus1, newIDT0, newIDTMinus1 int64) error {
+	results, err := s.db.TxPipelined(ctx, func(pipeliner redis.Pipeliner) error {
+		if cmdErr := MoveReferral(ctx, pipeliner, T1ReferralTier, id, oldIDT0, newIDT0); cmdErr != nil {
+			return cmdErr
+		}
+
+		return MoveReferral(ctx, pipeliner, T2ReferralTier, id, oldIDTMinus1, newIDTMinus1)
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to move referral id:%v from t0:%v,t-1:%v to t0:%v,t-1:%v", id, oldIDT0, oldIDTMinus1, newIDT0, newIDTMinus1)
+	}
+	errs := make([]error, 0, len(results))
+	for _, result := range results {
+		if err = result.Err(); err != nil {
+			errs = append(errs, errors.Wrapf(err, "failed to `%#v` for referrals index", result.FullName()))
+		}
+	}
+
+	return multierror.Append(nil, errs...).ErrorOrNil()
+}
+
+// MoveReferral moves the user from the referrals index of its previous referrer in that tier to the one of its new referrer.
+// The miner uses it as well, it's the one that changes the T-1 of the users when their T0 changes its own T0.
+func MoveReferral(ctx context.Context, pipeliner redis.Pipeliner, tier ReferralTier, id, oldReferredByID, newReferredByID int64) error {
+	oldReferredByID, newReferredByID = absInt64(oldReferredByID), absInt64(newReferredByID)
+	member := model.SerializedUsersKey(id)
+	if oldReferredByID != 0 && oldReferredByID != newReferredByID {
+		if err := pipeliner.ZRem(ctx, referralsKey(tier, oldReferredByID), member).Err(); err != nil {
+			return errors.Wrapf(err, "failed to remove id:%v from the %v referrals of id:%v", id, tier, oldReferredByID)
+		}
+	}
+	if newReferredByID != 0 {
+		if err := pipeliner.ZAdd(ctx, referralsKey(tier, newReferredByID), redis.Z{Score: float64(id), Member: member}).Err(); err != nil {
+			return errors.Wrapf(err, "failed to add id:%v to the %v referrals of id:%v", id, tier, newReferredByID)
+		}
+	}
+
+	return nil
+}
+
+func referralsKey(tier ReferralTier, id int64) string {
+	return "referrals_" + string(tier) + ":" + strconv.FormatInt(id, 10)
+}
+
+func absInt64(val int64) int64 {
+	if val < 0 {
+		return -val
+	}
+
+	return val
 }
 
 func (s *usersTableSource) updateUsernameKeywords(